@@ -0,0 +1,358 @@
+// Copyright (C) 2026 Storj Labs, Inc.
+// See LICENSE for copying information.
+
+package accesslogs
+
+import (
+	"bytes"
+	"compress/gzip"
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/klauspost/compress/zstd"
+	"github.com/zeebo/errs"
+)
+
+// Format selects how entries are encoded into log lines.
+type Format int
+
+const (
+	// FormatString encodes entries using Entry.String.
+	FormatString Format = iota
+	// FormatS3 encodes entries in the Amazon S3 server access log format.
+	// Entries must implement S3Entry.
+	FormatS3
+	// FormatJSONLines encodes every entry as a single line JSON object.
+	//
+	// S3Entry entries are encoded from their S3Fields, json.Marshaler
+	// entries are encoded with MarshalJSON, and other entries are encoded
+	// as {"message": Entry.String()}.
+	FormatJSONLines
+)
+
+// String returns the name of the format.
+func (f Format) String() string {
+	switch f {
+	case FormatString:
+		return "string"
+	case FormatS3:
+		return "s3"
+	case FormatJSONLines:
+		return "jsonl"
+	default:
+		return "unknown"
+	}
+}
+
+// encode returns the encoded entry and its size including the line feed.
+func (f Format) encode(entry Entry) (line string, size int, err error) {
+	switch f {
+	case FormatString:
+		return entry.String(), entry.Size().Int() + lfSize, nil
+	case FormatS3:
+		s3, ok := entry.(S3Entry)
+		if !ok {
+			return "", 0, errs.New("entry does not support %s format", f)
+		}
+		line = s3.S3Fields().String()
+	case FormatJSONLines:
+		var data []byte
+		switch entry := entry.(type) {
+		case S3Entry:
+			data, err = json.Marshal(entry.S3Fields())
+		case json.Marshaler:
+			data, err = json.Marshal(entry)
+		default:
+			data, err = json.Marshal(messageJSON{Message: entry.String()})
+		}
+		if err != nil {
+			return "", 0, err
+		}
+		line = string(data)
+	default:
+		return "", 0, errs.New("unknown format %d", int(f))
+	}
+	return line, len(line) + lfSize, nil
+}
+
+// messageJSON is the JSON lines representation of entries without structure.
+type messageJSON struct {
+	Message string `json:"message"`
+}
+
+// S3Entry is an Entry that can be described with the fields of the Amazon
+// S3 server access log format.
+type S3Entry interface {
+	Entry
+	S3Fields() S3Fields
+}
+
+// S3Fields are the fields of a single line in the Amazon S3 server access
+// log format, in the order they appear in the line.
+//
+// Empty strings and zero numbers are written as "-".
+type S3Fields struct {
+	BucketOwner        string
+	Bucket             string
+	Time               time.Time
+	RemoteIP           string
+	Requester          string
+	RequestID          string
+	Operation          string
+	Key                string
+	RequestURI         string
+	HTTPStatus         int
+	ErrorCode          string
+	BytesSent          int64
+	ObjectSize         int64
+	TotalTime          time.Duration
+	TurnAroundTime     time.Duration
+	Referer            string
+	UserAgent          string
+	VersionID          string
+	HostID             string
+	SignatureVersion   string
+	CipherSuite        string
+	AuthenticationType string
+	HostHeader         string
+	TLSVersion         string
+	AccessPointARN     string
+	ACLRequired        string
+}
+
+// String returns the fields formatted as a single S3 server access log line.
+//
+// The key is URL-encoded like in S3, quoted fields are escaped with Go string
+// syntax and other fields percent-encode spaces, quotes and control
+// characters, such that values can't break the structure of the line.
+func (f S3Fields) String() string {
+	var b strings.Builder
+
+	write := func(s string) {
+		if b.Len() > 0 {
+			b.WriteByte(' ')
+		}
+		if s == "" {
+			s = "-"
+		}
+		b.WriteString(s)
+	}
+	field := func(s string) {
+		write(escapeS3Field(s))
+	}
+	key := func(s string) {
+		segments := strings.Split(s, "/")
+		for i, segment := range segments {
+			segments[i] = url.PathEscape(segment)
+		}
+		write(strings.Join(segments, "/"))
+	}
+	quoted := func(s string) {
+		if s == "" {
+			s = "-"
+		}
+		write(strconv.Quote(s))
+	}
+	number := func(n int64) {
+		if n == 0 {
+			write("")
+			return
+		}
+		write(strconv.FormatInt(n, 10))
+	}
+	timestamp := func(t time.Time) {
+		if t.IsZero() {
+			write("")
+			return
+		}
+		write(t.UTC().Format("[02/Jan/2006:15:04:05 -0700]"))
+	}
+
+	field(f.BucketOwner)
+	field(f.Bucket)
+	timestamp(f.Time)
+	field(f.RemoteIP)
+	field(f.Requester)
+	field(f.RequestID)
+	field(f.Operation)
+	key(f.Key)
+	quoted(f.RequestURI)
+	number(int64(f.HTTPStatus))
+	field(f.ErrorCode)
+	number(f.BytesSent)
+	number(f.ObjectSize)
+	number(f.TotalTime.Milliseconds())
+	number(f.TurnAroundTime.Milliseconds())
+	quoted(f.Referer)
+	quoted(f.UserAgent)
+	field(f.VersionID)
+	field(f.HostID)
+	field(f.SignatureVersion)
+	field(f.CipherSuite)
+	field(f.AuthenticationType)
+	field(f.HostHeader)
+	field(f.TLSVersion)
+	field(f.AccessPointARN)
+	field(f.ACLRequired)
+
+	return b.String()
+}
+
+// escapeS3Field percent-encodes the bytes of an unquoted field, which would
+// break the structure of the line, and percent signs.
+func escapeS3Field(s string) string {
+	if !strings.ContainsFunc(s, needsS3Escape) {
+		return s
+	}
+
+	var b strings.Builder
+	for i := 0; i < len(s); i++ {
+		if c := s[i]; needsS3Escape(rune(c)) {
+			fmt.Fprintf(&b, "%%%02X", c)
+		} else {
+			b.WriteByte(c)
+		}
+	}
+	return b.String()
+}
+
+// needsS3Escape returns whether the character is escaped in unquoted fields.
+func needsS3Escape(c rune) bool {
+	return c <= ' ' || c == '"' || c == '%' || c == 0x7f
+}
+
+// s3FieldsJSON is the JSON lines representation of S3Fields.
+type s3FieldsJSON struct {
+	BucketOwner        string    `json:"bucket_owner,omitempty"`
+	Bucket             string    `json:"bucket,omitempty"`
+	Time               time.Time `json:"time"`
+	RemoteIP           string    `json:"remote_ip,omitempty"`
+	Requester          string    `json:"requester,omitempty"`
+	RequestID          string    `json:"request_id,omitempty"`
+	Operation          string    `json:"operation,omitempty"`
+	Key                string    `json:"key,omitempty"`
+	RequestURI         string    `json:"request_uri,omitempty"`
+	HTTPStatus         int       `json:"http_status,omitempty"`
+	ErrorCode          string    `json:"error_code,omitempty"`
+	BytesSent          int64     `json:"bytes_sent,omitempty"`
+	ObjectSize         int64     `json:"object_size,omitempty"`
+	TotalTimeMS        int64     `json:"total_time_ms,omitempty"`
+	TurnAroundTimeMS   int64     `json:"turn_around_time_ms,omitempty"`
+	Referer            string    `json:"referer,omitempty"`
+	UserAgent          string    `json:"user_agent,omitempty"`
+	VersionID          string    `json:"version_id,omitempty"`
+	HostID             string    `json:"host_id,omitempty"`
+	SignatureVersion   string    `json:"signature_version,omitempty"`
+	CipherSuite        string    `json:"cipher_suite,omitempty"`
+	AuthenticationType string    `json:"authentication_type,omitempty"`
+	HostHeader         string    `json:"host_header,omitempty"`
+	TLSVersion         string    `json:"tls_version,omitempty"`
+	AccessPointARN     string    `json:"access_point_arn,omitempty"`
+	ACLRequired        string    `json:"acl_required,omitempty"`
+}
+
+// MarshalJSON marshals the fields into json.
+func (f S3Fields) MarshalJSON() ([]byte, error) {
+	return json.Marshal(s3FieldsJSON{
+		BucketOwner:        f.BucketOwner,
+		Bucket:             f.Bucket,
+		Time:               f.Time.UTC(),
+		RemoteIP:           f.RemoteIP,
+		Requester:          f.Requester,
+		RequestID:          f.RequestID,
+		Operation:          f.Operation,
+		Key:                f.Key,
+		RequestURI:         f.RequestURI,
+		HTTPStatus:         f.HTTPStatus,
+		ErrorCode:          f.ErrorCode,
+		BytesSent:          f.BytesSent,
+		ObjectSize:         f.ObjectSize,
+		TotalTimeMS:        f.TotalTime.Milliseconds(),
+		TurnAroundTimeMS:   f.TurnAroundTime.Milliseconds(),
+		Referer:            f.Referer,
+		UserAgent:          f.UserAgent,
+		VersionID:          f.VersionID,
+		HostID:             f.HostID,
+		SignatureVersion:   f.SignatureVersion,
+		CipherSuite:        f.CipherSuite,
+		AuthenticationType: f.AuthenticationType,
+		HostHeader:         f.HostHeader,
+		TLSVersion:         f.TLSVersion,
+		AccessPointARN:     f.AccessPointARN,
+		ACLRequired:        f.ACLRequired,
+	})
+}
+
+// Compression selects how shipped log files are compressed.
+type Compression int
+
+const (
+	// CompressionNone ships log files uncompressed.
+	CompressionNone Compression = iota
+	// CompressionGzip ships gzip compressed log files with the ".gz" extension.
+	CompressionGzip
+	// CompressionZstd ships zstd compressed log files with the ".zst" extension.
+	CompressionZstd
+)
+
+// String returns the name of the compression.
+func (c Compression) String() string {
+	switch c {
+	case CompressionNone:
+		return "none"
+	case CompressionGzip:
+		return "gzip"
+	case CompressionZstd:
+		return "zstd"
+	default:
+		return "unknown"
+	}
+}
+
+// Extension returns the object key extension for the compression.
+func (c Compression) Extension() string {
+	switch c {
+	case CompressionGzip:
+		return ".gz"
+	case CompressionZstd:
+		return ".zst"
+	default:
+		return ""
+	}
+}
+
+// zstdEncoder is shared, because EncodeAll is safe for concurrent use.
+var zstdEncoder = sync.OnceValues(func() (*zstd.Encoder, error) {
+	return zstd.NewWriter(nil)
+})
+
+// compress compresses data.
+func (c Compression) compress(data []byte) ([]byte, error) {
+	switch c {
+	case CompressionNone:
+		return data, nil
+	case CompressionGzip:
+		var buf bytes.Buffer
+		w := gzip.NewWriter(&buf)
+		if _, err := w.Write(data); err != nil {
+			return nil, err
+		}
+		if err := w.Close(); err != nil {
+			return nil, err
+		}
+		return buf.Bytes(), nil
+	case CompressionZstd:
+		encoder, err := zstdEncoder()
+		if err != nil {
+			return nil, err
+		}
+		return encoder.EncodeAll(data, nil), nil
+	default:
+		return nil, errs.New("unknown compression %d", int(c))
+	}
+}
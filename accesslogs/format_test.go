@@ -0,0 +1,162 @@
+// Copyright (C) 2026 Storj Labs, Inc.
+// See LICENSE for copying information.
+
+package accesslogs
+
+import (
+	"bytes"
+	"compress/gzip"
+	"io"
+	"testing"
+	"time"
+
+	"github.com/klauspost/compress/zstd"
+	"github.com/stretchr/testify/require"
+
+	"storj.io/common/memory"
+	"storj.io/common/testrand"
+)
+
+type testS3Entry struct {
+	fields S3Fields
+}
+
+func (e testS3Entry) Size() memory.Size {
+	return memory.Size(len(e.String()))
+}
+
+func (e testS3Entry) String() string {
+	return e.fields.String()
+}
+
+func (e testS3Entry) S3Fields() S3Fields {
+	return e.fields
+}
+
+var exampleS3Fields = S3Fields{
+	BucketOwner:        "79a59df900b949e55d96a1e698fbacedfd6e09d98eacf8f8d5218e7cd47ef2be",
+	Bucket:             "DOC-EXAMPLE-BUCKET1",
+	Time:               time.Date(2019, time.February, 6, 0, 0, 38, 0, time.UTC),
+	RemoteIP:           "192.0.2.3",
+	Requester:          "79a59df900b949e55d96a1e698fbacedfd6e09d98eacf8f8d5218e7cd47ef2be",
+	RequestID:          "3E57427F3EXAMPLE",
+	Operation:          "REST.GET.VERSIONING",
+	RequestURI:         "GET /DOC-EXAMPLE-BUCKET1?versioning HTTP/1.1",
+	HTTPStatus:         200,
+	BytesSent:          113,
+	TotalTime:          7 * time.Millisecond,
+	UserAgent:          "S3Console/0.4",
+	HostID:             "s9lzHYrFp76ZVxRcpX9+5cjAnEH2ROuNkd2BHfIa6UkFVdtjf5mKR3/eTPFvsiP/XV/VLi31234=",
+	SignatureVersion:   "SigV4",
+	CipherSuite:        "ECDHE-RSA-AES128-GCM-SHA256",
+	AuthenticationType: "AuthHeader",
+	HostHeader:         "DOC-EXAMPLE-BUCKET1.s3.us-west-1.amazonaws.com",
+	TLSVersion:         "TLSV1.2",
+	AccessPointARN:     "arn:aws:s3:us-west-1:123456789012:accesspoint/example-AP",
+	ACLRequired:        "Yes",
+}
+
+func TestS3Fields(t *testing.T) {
+	require.Equal(t, `79a59df900b949e55d96a1e698fbacedfd6e09d98eacf8f8d5218e7cd47ef2be DOC-EXAMPLE-BUCKET1 [06/Feb/2019:00:00:38 +0000] 192.0.2.3 79a59df900b949e55d96a1e698fbacedfd6e09d98eacf8f8d5218e7cd47ef2be 3E57427F3EXAMPLE REST.GET.VERSIONING - "GET /DOC-EXAMPLE-BUCKET1?versioning HTTP/1.1" 200 - 113 - 7 - "-" "S3Console/0.4" - s9lzHYrFp76ZVxRcpX9+5cjAnEH2ROuNkd2BHfIa6UkFVdtjf5mKR3/eTPFvsiP/XV/VLi31234= SigV4 ECDHE-RSA-AES128-GCM-SHA256 AuthHeader DOC-EXAMPLE-BUCKET1.s3.us-west-1.amazonaws.com TLSV1.2 arn:aws:s3:us-west-1:123456789012:accesspoint/example-AP Yes`, exampleS3Fields.String())
+}
+
+func TestS3Fields_Escaping(t *testing.T) {
+	for _, test := range []struct {
+		name   string
+		fields S3Fields
+		want   string
+	}{
+		{
+			name:   "key with space and newline",
+			fields: S3Fields{Key: "photos/my cat\n.jpg\n- - forged"},
+			want:   "photos/my%20cat%0A.jpg%0A-%20-%20forged",
+		},
+		{
+			name:   "key with percent and quote",
+			fields: S3Fields{Key: `a/100%"done"`},
+			want:   "a/100%25%22done%22",
+		},
+		{
+			name:   "user agent with quote and newline",
+			fields: S3Fields{UserAgent: "evil\" 200\nforged \\ \x00"},
+			want:   `"evil\" 200\nforged \\ \x00"`,
+		},
+		{
+			name:   "request uri with quote",
+			fields: S3Fields{RequestURI: `GET /"x" HTTP/1.1`},
+			want:   `"GET /\"x\" HTTP/1.1"`,
+		},
+		{
+			name:   "bare field with space and newline",
+			fields: S3Fields{ErrorCode: "Bad Request\nforged"},
+			want:   "Bad%20Request%0Aforged",
+		},
+	} {
+		line := test.fields.String()
+		require.Contains(t, line, " "+test.want+" ", test.name)
+		require.NotContains(t, line, "\n", test.name)
+	}
+}
+
+func TestFormatEncode(t *testing.T) {
+	s3 := testS3Entry{fields: S3Fields{
+		Bucket:     "bucket",
+		Time:       time.Date(2019, time.February, 6, 0, 0, 38, 0, time.UTC),
+		HTTPStatus: 200,
+		TotalTime:  7 * time.Millisecond,
+	}}
+	plain := newTestEntry("plain\nentry")
+
+	line, size, err := FormatString.encode(plain)
+	require.NoError(t, err)
+	require.Equal(t, "plain\nentry", line)
+	require.Equal(t, len(line)+lfSize, size)
+
+	line, size, err = FormatS3.encode(s3)
+	require.NoError(t, err)
+	require.Equal(t, `- bucket [06/Feb/2019:00:00:38 +0000] - - - - - "-" 200 - - - 7 - "-" "-" - - - - - - - - -`, line)
+	require.Equal(t, len(line)+lfSize, size)
+
+	_, _, err = FormatS3.encode(plain)
+	require.Error(t, err)
+
+	line, _, err = FormatJSONLines.encode(s3)
+	require.NoError(t, err)
+	require.JSONEq(t, `{"bucket":"bucket","time":"2019-02-06T00:00:38Z","http_status":200,"total_time_ms":7}`, line)
+
+	line, size, err = FormatJSONLines.encode(plain)
+	require.NoError(t, err)
+	require.Equal(t, `{"message":"plain\nentry"}`, line)
+	require.Equal(t, len(line)+lfSize, size)
+
+	_, _, err = Format(100).encode(plain)
+	require.Error(t, err)
+}
+
+func TestCompression(t *testing.T) {
+	data := testrand.BytesInt(10000)
+
+	plain, err := CompressionNone.compress(data)
+	require.NoError(t, err)
+	require.Equal(t, data, plain)
+
+	gzipped, err := CompressionGzip.compress(data)
+	require.NoError(t, err)
+	r, err := gzip.NewReader(bytes.NewReader(gzipped))
+	require.NoError(t, err)
+	decompressed, err := io.ReadAll(r)
+	require.NoError(t, err)
+	require.Equal(t, data, decompressed)
+
+	zstded, err := CompressionZstd.compress(data)
+	require.NoError(t, err)
+	decoder, err := zstd.NewReader(nil)
+	require.NoError(t, err)
+	defer decoder.Close()
+	decompressed, err = decoder.DecodeAll(zstded, nil)
+	require.NoError(t, err)
+	require.Equal(t, data, decompressed)
+
+	_, err = Compression(100).compress(data)
+	require.Error(t, err)
+}
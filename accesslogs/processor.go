@@ -31,6 +31,10 @@ const (
 	defaultShipmentLimit      = 63 * memory.MiB
 	defaultUploaderQueueLimit = 100
 	defaultUploaderRetryLimit = 3
+	defaultRetryBackoff       = time.Second
+	defaultMaxRetryBackoff    = time.Minute
+	defaultSpoolLimit         = memory.GiB
+	defaultSpoolRetryInterval = 5 * time.Minute
 
 	lf     = '\n'
 	lfSize = 1
@@ -49,6 +53,38 @@ type Key struct {
 	PublicProjectID uuid.UUID
 	Bucket          string
 	Prefix          string
+
+	// Format selects how entries are encoded.
+	Format Format
+	// Compression selects how shipped log files are compressed.
+	Compression Compression
+	// KeyTemplate is the template for object keys of shipped log files. If
+	// empty, SimpleKeyTemplate is used. See SimpleKeyTemplate for the
+	// supported placeholders.
+	KeyTemplate string
+}
+
+const (
+	// SimpleKeyTemplate is the default object key template:
+	// [Prefix][YYYY]-[MM]-[DD]-[hh]-[mm]-[ss]-[UniqueString].
+	//
+	// Supported placeholders are [Prefix], [ProjectID], [Bucket], [YYYY],
+	// [MM], [DD], [hh], [mm], [ss] and [UniqueString]. Time placeholders
+	// are in UTC. Every template must contain [UniqueString].
+	SimpleKeyTemplate = "[Prefix][YYYY]-[MM]-[DD]-[hh]-[mm]-[ss]-[UniqueString]"
+	// DatePartitionedKeyTemplate partitions object keys by project and date,
+	// similarly to the date-based partitioning of Amazon S3 server access logs.
+	DatePartitionedKeyTemplate = "[Prefix][ProjectID]/[YYYY]/[MM]/[DD]/[YYYY]-[MM]-[DD]-[hh]-[mm]-[ss]-[UniqueString]"
+)
+
+func (k Key) validate() error {
+	if k.KeyTemplate != "" && !strings.Contains(k.KeyTemplate, "[UniqueString]") {
+		return errs.New("key template %q must contain [UniqueString]", k.KeyTemplate)
+	}
+	if k.Compression.Extension() == "" && k.Compression != CompressionNone {
+		return errs.New("unknown compression %d", int(k.Compression))
+	}
+	return nil
 }
 
 // Entry represents a single log line of collected logs.
@@ -95,8 +131,15 @@ type Options struct {
 	UploadingOptions        struct {
 		QueueLimit      int           `user:"true" help:"log file upload queue limit" default:"100"`
 		RetryLimit      int           `user:"true" help:"maximum number of retries for log file uploads" default:"3"`
+		RetryBackoff    time.Duration `user:"true" help:"delay before retrying a failed log file upload, doubled with every retry" default:"1s"`
+		MaxRetryBackoff time.Duration `user:"true" help:"maximum delay before retrying a failed log file upload" default:"1m"`
 		ShutdownTimeout time.Duration `user:"true" help:"time limit waiting for queued logs to finish uploading when gateway is shutting down" default:"1m"`
 	}
+	SpoolingOptions struct {
+		Dir           string        `user:"true" help:"directory for log files that couldn't be queued or uploaded (empty means disabled)" default:""`
+		Limit         memory.Size   `user:"true" help:"maximum disk usage of spooled log files" default:"1GiB"`
+		RetryInterval time.Duration `user:"true" help:"how often to retry uploading spooled log files" default:"5m"`
+	}
 }
 
 // NewProcessor returns initialized Processor.
@@ -115,17 +158,38 @@ func NewProcessor(log *zap.Logger, opts Options) *Processor {
 	if opts.UploadingOptions.RetryLimit <= 0 {
 		opts.UploadingOptions.RetryLimit = defaultUploaderRetryLimit
 	}
+	if opts.UploadingOptions.RetryBackoff <= 0 {
+		opts.UploadingOptions.RetryBackoff = defaultRetryBackoff
+	}
+	if opts.UploadingOptions.MaxRetryBackoff <= 0 {
+		opts.UploadingOptions.MaxRetryBackoff = defaultMaxRetryBackoff
+	}
 	if opts.UploadingOptions.ShutdownTimeout <= 0 {
 		opts.UploadingOptions.ShutdownTimeout = time.Minute
 	}
+	if opts.SpoolingOptions.Limit <= 0 {
+		opts.SpoolingOptions.Limit = defaultSpoolLimit
+	}
+	if opts.SpoolingOptions.RetryInterval <= 0 {
+		opts.SpoolingOptions.RetryInterval = defaultSpoolRetryInterval
+	}
+
+	var s *spool
+	if opts.SpoolingOptions.Dir != "" {
+		s = newSpool(log, opts.SpoolingOptions.Dir, opts.SpoolingOptions.Limit)
+	}
 
 	return &Processor{
 		log: log,
 		upload: newSequentialUploader(log, sequentialUploaderOptions{
-			entryLimit:      opts.DefaultShipmentLimit,
-			queueLimit:      opts.UploadingOptions.QueueLimit,
-			retryLimit:      opts.UploadingOptions.RetryLimit,
-			shutdownTimeout: opts.UploadingOptions.ShutdownTimeout,
+			entryLimit:         opts.DefaultShipmentLimit,
+			queueLimit:         opts.UploadingOptions.QueueLimit,
+			retryLimit:         opts.UploadingOptions.RetryLimit,
+			retryBackoff:       opts.UploadingOptions.RetryBackoff,
+			maxRetryBackoff:    opts.UploadingOptions.MaxRetryBackoff,
+			shutdownTimeout:    opts.UploadingOptions.ShutdownTimeout,
+			spool:              s,
+			spoolRetryInterval: opts.SpoolingOptions.RetryInterval,
 		}),
 
 		defaultEntryLimit:       opts.DefaultEntryLimit,
@@ -141,7 +205,14 @@ func NewProcessor(log *zap.Logger, opts Options) *Processor {
 func (p *Processor) QueueEntry(store Storage, key Key, entry Entry) (err error) {
 	defer mon.Task()(nil)(&err)
 
-	entrySize := entry.Size().Int() + lfSize
+	if err := key.validate(); err != nil {
+		return Error.Wrap(err)
+	}
+
+	line, entrySize, err := key.Format.encode(entry)
+	if err != nil {
+		return Error.Wrap(err)
+	}
 
 	if g := p.globalSize.Load(); g+int64(entrySize) > p.globalLimit.Int64() {
 		// NOTE(artur): this is a best-effort check; we could return an
@@ -159,8 +230,7 @@ func (p *Processor) QueueEntry(store Storage, key Key, entry Entry) (err error)
 		entryLimit:    p.defaultEntryLimit.Int(),
 		shipmentLimit: p.defaultShipmentLimit.Int(),
 		store:         store,
-		bucket:        key.Bucket,
-		prefix:        key.Prefix,
+		key:           key,
 	})
 
 	parcel := actual.(*parcel)
@@ -181,7 +251,7 @@ func (p *Processor) QueueEntry(store Storage, key Key, entry Entry) (err error)
 		return Error.Wrap(ErrTooLarge)
 	}
 
-	shipped, err := parcel.add(p.upload, entrySize, entry.String())
+	shipped, err := parcel.add(p.upload, entrySize, line)
 	if err != nil {
 		return Error.Wrap(err)
 	}
@@ -224,6 +294,17 @@ func (p *Processor) timedFlush(key Key, interval time.Duration) error {
 	return nil
 }
 
+// RecoverSpool allows uploading log files spooled by previous runs of
+// Processor. resolve returns the Storage for the key a spooled log file was
+// queued with. Only PublicProjectID, Bucket and Prefix of the key are set.
+// Log files for which resolve fails stay spooled.
+//
+// Log files spooled during the current run are uploaded using the Storage
+// they were queued with, regardless of RecoverSpool.
+func (p *Processor) RecoverSpool(resolve func(key Key) (Storage, error)) {
+	p.upload.recoverSpool(resolve)
+}
+
 // Run starts Processor.
 func (p *Processor) Run() error {
 	return Error.Wrap(p.upload.run())
@@ -268,11 +349,12 @@ type parcel struct {
 	entryLimit    int
 	shipmentLimit int
 
-	store          Storage
-	bucket, prefix string
+	store Storage
+	key   Key
 
 	mu      sync.Mutex
 	current bytes.Buffer
+	entries int
 	closed  bool
 }
 
@@ -289,40 +371,50 @@ func (p *parcel) add(upload uploader, size int, s string) (shipped int, err erro
 	if currentSize+size < p.shipmentLimit {
 		p.current.WriteString(s)
 		p.current.WriteByte(lf)
+		p.entries++
 		return 0, nil
 	}
 	// slow…
-	k, err := randomKey(p.prefix, time.Now())
+	k, err := objectKey(p.key, time.Now())
 	if err != nil {
 		return 0, err
 	}
 	// we use cloneUnsafe here because we already have the lock.
-	c, err := p.cloneUnsafe()
+	c, entries, err := p.cloneUnsafe()
 	if err != nil {
 		return 0, err
 	}
-	if err = upload.queueUpload(p.store, p.bucket, k, c); err != nil {
+	if c, err = p.key.Compression.compress(c); err != nil {
+		monDroppedEntries.Inc(int64(entries))
+		return 0, err
+	}
+	if err = upload.queueUpload(p.store, p.key, k, c, entries); err != nil {
 		// FIXME(artur): rewind the buffer if we fail to upload.
+		monDroppedEntries.Inc(int64(entries))
 		return 0, err
 	}
 	shipped = currentSize
 	// add again
 	p.current.WriteString(s)
 	p.current.WriteByte(lf)
+	p.entries++
 	return shipped, nil
 }
 
-// cloneUnsafe creates a thread-unsafe clone of the parcel.
-func (p *parcel) cloneUnsafe() ([]byte, error) {
+// cloneUnsafe creates a thread-unsafe clone of the parcel and returns it
+// along with the number of entries it contains.
+func (p *parcel) cloneUnsafe() ([]byte, int, error) {
 	c := bytes.NewBuffer(nil)
 	if _, err := p.current.WriteTo(c); err != nil {
-		return nil, err
+		return nil, 0, err
 	}
-	return c.Bytes(), nil
+	entries := p.entries
+	p.entries = 0
+	return c.Bytes(), entries, nil
 }
 
 // clone creates a thread-safe clone of the parcel.
-func (p *parcel) clone() ([]byte, error) {
+func (p *parcel) clone() ([]byte, int, error) {
 	p.mu.Lock()
 	defer p.mu.Unlock()
 
@@ -333,15 +425,24 @@ func (p *parcel) flush(upload uploader) (int, error) {
 	// NOTE(artur): here we need to queue upload without limits because when we
 	// flush before close, we really want to drain all parcels as we won't have
 	// the chance to trigger shipment later on.
-	k, err := randomKey(p.prefix, time.Now())
+	k, err := objectKey(p.key, time.Now())
 	if err != nil {
 		return 0, err
 	}
-	c, err := p.clone()
+	c, entries, err := p.clone()
 	if err != nil {
 		return 0, err
 	}
-	return len(c), upload.queueUploadWithoutQueueLimit(p.store, p.bucket, k, c)
+	shipped := len(c)
+	if c, err = p.key.Compression.compress(c); err != nil {
+		monDroppedEntries.Inc(int64(entries))
+		return shipped, err
+	}
+	if err = upload.queueUploadWithoutQueueLimit(p.store, p.key, k, c, entries); err != nil {
+		monDroppedEntries.Inc(int64(entries))
+		return shipped, err
+	}
+	return shipped, nil
 }
 
 func (p *parcel) close(upload uploader) error {
@@ -356,6 +457,36 @@ func (p *parcel) close(upload uploader) error {
 	return nil
 }
 
+// objectKey returns the object key for a log file shipped at t.
+func objectKey(key Key, t time.Time) (string, error) {
+	var k string
+	if key.KeyTemplate == "" {
+		var err error
+		if k, err = randomKey(key.Prefix, t); err != nil {
+			return "", err
+		}
+	} else {
+		u, err := uniqueString()
+		if err != nil {
+			return "", err
+		}
+		t = t.UTC()
+		k = strings.NewReplacer(
+			"[Prefix]", key.Prefix,
+			"[ProjectID]", key.PublicProjectID.String(),
+			"[Bucket]", key.Bucket,
+			"[YYYY]", t.Format("2006"),
+			"[MM]", t.Format("01"),
+			"[DD]", t.Format("02"),
+			"[hh]", t.Format("15"),
+			"[mm]", t.Format("04"),
+			"[ss]", t.Format("05"),
+			"[UniqueString]", u,
+		).Replace(key.KeyTemplate)
+	}
+	return k + key.Compression.Extension(), nil
+}
+
 func randomKey(prefix string, t time.Time) (string, error) {
 	// TODO(artur): let's return something like
 	// [DestinationPrefix][YYYY]-[MM]-[DD]-[hh]-[mm]-[ss]-[UniqueString]
@@ -5,7 +5,9 @@ package accesslogs
 
 import (
 	"bytes"
+	"compress/gzip"
 	"context"
+	"io"
 	"strings"
 	"sync"
 	"sync/atomic"
@@ -273,6 +275,87 @@ func TestRandomKey(t *testing.T) {
 	}
 }
 
+func TestObjectKey(t *testing.T) {
+	now := time.Date(2019, time.February, 6, 0, 0, 38, 0, time.UTC)
+	id, err := uuid.New()
+	require.NoError(t, err)
+
+	for _, tc := range []struct {
+		key    Key
+		regexp string
+	}{
+		{
+			key:    Key{Prefix: "prefix/"},
+			regexp: "^prefix/2019-02-06-00-00-38-[0-9A-F]{16}$",
+		},
+		{
+			key:    Key{Prefix: "prefix/", KeyTemplate: SimpleKeyTemplate, Compression: CompressionGzip},
+			regexp: "^prefix/2019-02-06-00-00-38-[0-9A-F]{16}\\.gz$",
+		},
+		{
+			key:    Key{PublicProjectID: id, Prefix: "logs/", KeyTemplate: DatePartitionedKeyTemplate, Compression: CompressionZstd},
+			regexp: "^logs/" + id.String() + "/2019/02/06/2019-02-06-00-00-38-[0-9A-F]{16}\\.zst$",
+		},
+		{
+			key:    Key{Bucket: "bucket", KeyTemplate: "[Bucket]/[hh][mm][ss]/[UniqueString].log"},
+			regexp: "^bucket/000038/[0-9A-F]{16}\\.log$",
+		},
+	} {
+		require.NoError(t, tc.key.validate())
+		k, err := objectKey(tc.key, now)
+		require.NoError(t, err)
+		require.Regexp(t, tc.regexp, k)
+	}
+
+	require.Error(t, Key{KeyTemplate: "[Prefix][YYYY]"}.validate())
+	require.Error(t, Key{Compression: Compression(100)}.validate())
+}
+
+func TestProcessorWithFormatAndCompression(t *testing.T) {
+	t.Parallel()
+
+	ctx := testcontext.New(t)
+
+	log := zaptest.NewLogger(t)
+	defer ctx.Check(log.Sync)
+
+	s := newInMemoryStorage()
+	p := NewProcessor(log, Options{})
+
+	ctx.Go(p.Run)
+
+	id, err := uuid.New()
+	require.NoError(t, err)
+	key := Key{
+		PublicProjectID: id,
+		Bucket:          "bucket",
+		Prefix:          "logs/",
+		Format:          FormatJSONLines,
+		Compression:     CompressionGzip,
+		KeyTemplate:     DatePartitionedKeyTemplate,
+	}
+
+	require.NoError(t, p.QueueEntry(s, key, newTestEntry("entry1")))
+	require.NoError(t, p.QueueEntry(s, key, newTestEntry("entry2")))
+	require.Error(t, p.QueueEntry(s, Key{Format: FormatS3}, newTestEntry("entry3")))
+	require.Error(t, p.QueueEntry(s, Key{KeyTemplate: "static"}, newTestEntry("entry3")))
+
+	require.NoError(t, p.Close())
+
+	contents := s.getBucketContents("bucket")
+	require.Len(t, contents, 1)
+	for k, v := range contents {
+		require.True(t, strings.HasPrefix(k, "logs/"+id.String()+"/"))
+		require.True(t, strings.HasSuffix(k, ".gz"))
+
+		r, err := gzip.NewReader(bytes.NewReader(v))
+		require.NoError(t, err)
+		data, err := io.ReadAll(r)
+		require.NoError(t, err)
+		require.Equal(t, "{\"message\":\"entry1\"}\n{\"message\":\"entry2\"}\n", string(data))
+	}
+}
+
 var exampleAmazonS3ServerAccessLogLine = func() func() string {
 	var i atomic.Int64
 	i.Store(-1)
@@ -0,0 +1,302 @@
+// Copyright (C) 2026 Storj Labs, Inc.
+// See LICENSE for copying information.
+
+package accesslogs
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"sync"
+
+	"github.com/zeebo/errs"
+	"go.uber.org/zap"
+
+	"storj.io/common/memory"
+	"storj.io/common/uuid"
+)
+
+const (
+	spoolMagic   = "ALS2"
+	spoolExt     = ".spool"
+	spoolTempExt = ".tmp"
+)
+
+var (
+	// ErrSpoolFull means that the spool disk usage limit has been reached.
+	ErrSpoolFull = errors.New("spool limit reached")
+
+	monSpooledBytes = mon.IntVal("spooled_bytes")
+)
+
+// spool is an on-disk write-ahead queue for log files that couldn't be
+// uploaded. Every log file is kept in a separate file, which is removed
+// after the log file is successfully uploaded.
+type spool struct {
+	log   *zap.Logger
+	dir   string
+	limit int64
+
+	initOnce sync.Once
+	initErr  error
+
+	mu   sync.Mutex
+	size int64
+}
+
+func newSpool(log *zap.Logger, dir string, limit memory.Size) *spool {
+	return &spool{
+		log:   log.Named("spool"),
+		dir:   dir,
+		limit: limit.Int64(),
+	}
+}
+
+// init creates the spool directory, removes incomplete files and calculates
+// the current disk usage.
+func (s *spool) init() error {
+	s.initOnce.Do(func() {
+		if err := os.MkdirAll(s.dir, 0700); err != nil {
+			s.initErr = errs.Wrap(err)
+			return
+		}
+
+		entries, err := os.ReadDir(s.dir)
+		if err != nil {
+			s.initErr = errs.Wrap(err)
+			return
+		}
+
+		var size int64
+		for _, entry := range entries {
+			switch filepath.Ext(entry.Name()) {
+			case spoolTempExt:
+				if err := os.Remove(filepath.Join(s.dir, entry.Name())); err != nil {
+					s.log.Warn("couldn't remove incomplete spool file", zap.String("name", entry.Name()), zap.Error(err))
+				}
+			case spoolExt:
+				info, err := entry.Info()
+				if err != nil {
+					s.initErr = errs.Wrap(err)
+					return
+				}
+				size += info.Size()
+			}
+		}
+
+		s.mu.Lock()
+		s.size = size
+		s.mu.Unlock()
+		monSpooledBytes.Observe(size)
+	})
+	return s.initErr
+}
+
+// put writes the upload to disk and returns the name of the spool file.
+func (s *spool) put(up upload) (name string, err error) {
+	if err := s.init(); err != nil {
+		return "", err
+	}
+
+	data := encodeSpoolFile(up)
+
+	s.mu.Lock()
+	if s.size+int64(len(data)) > s.limit {
+		s.mu.Unlock()
+		mon.Event("spool_limit_reached")
+		return "", ErrSpoolFull
+	}
+	s.size += int64(len(data))
+	monSpooledBytes.Observe(s.size)
+	s.mu.Unlock()
+
+	defer func() {
+		if err != nil {
+			s.release(int64(len(data)))
+		}
+	}()
+
+	u, err := uniqueString()
+	if err != nil {
+		return "", err
+	}
+	name = u + spoolExt
+
+	// write to a temporary file first, so that incomplete files are never
+	// mistaken for spooled log files.
+	temp := filepath.Join(s.dir, u+spoolTempExt)
+	if err := writeFileSync(temp, data); err != nil {
+		return "", errs.Combine(err, ignoreNotExist(os.Remove(temp)))
+	}
+	if err := os.Rename(temp, filepath.Join(s.dir, name)); err != nil {
+		return "", errs.Combine(errs.Wrap(err), ignoreNotExist(os.Remove(temp)))
+	}
+
+	return name, nil
+}
+
+// list returns names of all spooled files in the order they were written.
+func (s *spool) list() ([]string, error) {
+	if err := s.init(); err != nil {
+		return nil, err
+	}
+
+	entries, err := os.ReadDir(s.dir)
+	if err != nil {
+		return nil, errs.Wrap(err)
+	}
+
+	type spooled struct {
+		name    string
+		modTime int64
+	}
+	var files []spooled
+	for _, entry := range entries {
+		if filepath.Ext(entry.Name()) != spoolExt {
+			continue
+		}
+		info, err := entry.Info()
+		if err != nil {
+			return nil, errs.Wrap(err)
+		}
+		files = append(files, spooled{name: entry.Name(), modTime: info.ModTime().UnixNano()})
+	}
+	sort.SliceStable(files, func(i, k int) bool {
+		return files[i].modTime < files[k].modTime
+	})
+
+	names := make([]string, len(files))
+	for i, file := range files {
+		names[i] = file.name
+	}
+	return names, nil
+}
+
+// load reads the spooled upload with the specified name. The returned upload
+// has no store assigned.
+func (s *spool) load(name string) (upload, error) {
+	data, err := os.ReadFile(filepath.Join(s.dir, name))
+	if err != nil {
+		return upload{}, errs.Wrap(err)
+	}
+	up, err := decodeSpoolFile(data)
+	if err != nil {
+		return upload{}, errs.New("invalid spool file %q: %w", name, err)
+	}
+	up.spooled = name
+	return up, nil
+}
+
+// remove removes the spooled file with the specified name.
+func (s *spool) remove(name string) error {
+	path := filepath.Join(s.dir, name)
+	info, err := os.Stat(path)
+	if err != nil {
+		return errs.Wrap(err)
+	}
+	if err := os.Remove(path); err != nil {
+		return errs.Wrap(err)
+	}
+	s.release(info.Size())
+	return nil
+}
+
+func (s *spool) release(size int64) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.size -= size
+	monSpooledBytes.Observe(s.size)
+}
+
+// encodeSpoolFile encodes the upload into:
+//
+//	magic | uvarint(entries) | project ID | uvarint(len(bucket)) | bucket |
+//	uvarint(len(prefix)) | prefix | uvarint(len(key)) | key | body
+//
+// The project ID, bucket and prefix identify the destination of the upload.
+func encodeSpoolFile(up upload) []byte {
+	data := make([]byte, 0, len(spoolMagic)+4*binary.MaxVarintLen64+len(uuid.UUID{})+len(up.dest.Bucket)+len(up.dest.Prefix)+len(up.key)+len(up.body))
+	data = append(data, spoolMagic...)
+	data = binary.AppendUvarint(data, uint64(up.entries))
+	data = append(data, up.dest.PublicProjectID[:]...)
+	for _, s := range []string{up.dest.Bucket, up.dest.Prefix, up.key} {
+		data = binary.AppendUvarint(data, uint64(len(s)))
+		data = append(data, s...)
+	}
+	data = append(data, up.body...)
+	return data
+}
+
+func decodeSpoolFile(data []byte) (upload, error) {
+	if !bytes.HasPrefix(data, []byte(spoolMagic)) {
+		return upload{}, errs.New("invalid header")
+	}
+	data = data[len(spoolMagic):]
+
+	entries, n := binary.Uvarint(data)
+	if n <= 0 {
+		return upload{}, errs.New("invalid entry count")
+	}
+	data = data[n:]
+
+	if len(data) < len(uuid.UUID{}) {
+		return upload{}, errs.New("invalid project ID")
+	}
+	var dest Key
+	copy(dest.PublicProjectID[:], data)
+	data = data[len(uuid.UUID{}):]
+
+	readString := func() (string, bool) {
+		length, n := binary.Uvarint(data)
+		if n <= 0 || length > uint64(len(data)-n) {
+			return "", false
+		}
+		s := string(data[n : n+int(length)])
+		data = data[n+int(length):]
+		return s, true
+	}
+
+	var ok bool
+	if dest.Bucket, ok = readString(); !ok {
+		return upload{}, errs.New("invalid bucket")
+	}
+	if dest.Prefix, ok = readString(); !ok {
+		return upload{}, errs.New("invalid prefix")
+	}
+	key, ok := readString()
+	if !ok {
+		return upload{}, errs.New("invalid key")
+	}
+
+	return upload{
+		dest:    dest,
+		key:     key,
+		body:    data,
+		entries: int(entries),
+	}, nil
+}
+
+func writeFileSync(path string, data []byte) (err error) {
+	f, err := os.OpenFile(path, os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0600)
+	if err != nil {
+		return errs.Wrap(err)
+	}
+	defer func() { err = errs.Combine(err, errs.Wrap(f.Close())) }()
+
+	if _, err := f.Write(data); err != nil {
+		return errs.Wrap(err)
+	}
+	return errs.Wrap(f.Sync())
+}
+
+func ignoreNotExist(err error) error {
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil
+	}
+	return err
+}
@@ -0,0 +1,248 @@
+// Copyright (C) 2026 Storj Labs, Inc.
+// See LICENSE for copying information.
+
+package accesslogs
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strconv"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"github.com/zeebo/errs"
+	"go.uber.org/zap/zaptest"
+
+	"storj.io/common/memory"
+	"storj.io/common/testcontext"
+	"storj.io/common/testrand"
+)
+
+func TestSpool(t *testing.T) {
+	t.Parallel()
+
+	log := zaptest.NewLogger(t)
+	dir := t.TempDir()
+
+	up := upload{
+		dest: Key{
+			PublicProjectID: testrand.UUID(),
+			Bucket:          "bucket",
+			Prefix:          "prefix/",
+		},
+		key:     "prefix/key",
+		body:    testrand.BytesInt(100),
+		entries: 5,
+	}
+	size := int64(len(encodeSpoolFile(up)))
+
+	s := newSpool(log, dir, memory.Size(2*size))
+
+	name1, err := s.put(up)
+	require.NoError(t, err)
+	name2, err := s.put(up)
+	require.NoError(t, err)
+	_, err = s.put(up)
+	require.ErrorIs(t, err, ErrSpoolFull)
+
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "incomplete"+spoolTempExt), []byte("x"), 0600))
+
+	names, err := s.list()
+	require.NoError(t, err)
+	require.ElementsMatch(t, []string{name1, name2}, names)
+
+	loaded, err := s.load(name1)
+	require.NoError(t, err)
+	require.Equal(t, name1, loaded.spooled)
+	loaded.spooled = ""
+	require.Equal(t, up, loaded)
+
+	require.NoError(t, s.remove(name1))
+	_, err = s.put(up)
+	require.NoError(t, err)
+
+	// a new spool over the same directory sees the same usage and
+	// removes incomplete files.
+	reopened := newSpool(log, dir, memory.Size(2*size))
+	_, err = reopened.put(up)
+	require.ErrorIs(t, err, ErrSpoolFull)
+	_, err = os.Stat(filepath.Join(dir, "incomplete"+spoolTempExt))
+	require.ErrorIs(t, err, os.ErrNotExist)
+
+	projectID := string(make([]byte, 16))
+	for _, invalid := range [][]byte{
+		{},
+		[]byte("ALS"),
+		[]byte("ALS1\x01" + projectID + "\x00\x00\x00"),
+		[]byte("ALS2"),
+		[]byte("ALS2\x01\x00"),
+		[]byte("ALS2\x01" + projectID + "\x05abc"),
+		[]byte("ALS2\x01" + projectID + "\x03abc\x05x"),
+		[]byte("ALS2\x01" + projectID + "\x03abc\x01x\x05x"),
+	} {
+		_, err := decodeSpoolFile(invalid)
+		require.Error(t, err)
+	}
+}
+
+type toggleStorage struct {
+	mu      sync.Mutex
+	failing bool
+	s       *inMemoryStorage
+}
+
+func (s *toggleStorage) setFailing(failing bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.failing = failing
+}
+
+func (s *toggleStorage) count(bucket string) int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return len(s.s.getBucketContents(bucket))
+}
+
+func (s *toggleStorage) Put(ctx context.Context, bucket, key string, body []byte) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.failing {
+		return errs.New("failing")
+	}
+	return s.s.Put(ctx, bucket, key, body)
+}
+
+func TestUploaderSpool(t *testing.T) {
+	t.Parallel()
+
+	ctx := testcontext.New(t)
+
+	log := zaptest.NewLogger(t)
+	defer ctx.Check(log.Sync)
+
+	dir := t.TempDir()
+	store := &toggleStorage{failing: true, s: newInMemoryStorage()}
+
+	opts := sequentialUploaderOptions{
+		entryLimit:         5 * memory.KiB,
+		queueLimit:         2,
+		retryLimit:         1,
+		retryBackoff:       time.Millisecond,
+		maxRetryBackoff:    time.Millisecond,
+		shutdownTimeout:    time.Second,
+		spool:              newSpool(log, dir, memory.MiB),
+		spoolRetryInterval: time.Hour,
+	}
+
+	// log files of both projects are shipped to the same bucket name, but
+	// into different stores.
+	dest1 := Key{PublicProjectID: testrand.UUID(), Bucket: "bucket", Prefix: "logs/"}
+	dest2 := Key{PublicProjectID: testrand.UUID(), Bucket: "bucket", Prefix: "logs/"}
+	store2 := &toggleStorage{failing: true, s: newInMemoryStorage()}
+
+	u := newSequentialUploader(log, opts)
+	ctx.Go(u.run)
+	for i := range 5 {
+		// uploads over the queue limit are spooled immediately, the rest
+		// are spooled after reaching the retry limit.
+		require.NoError(t, u.queueUpload(store, dest1, "key"+strconv.Itoa(i), testrand.Bytes(memory.KiB), 1))
+	}
+	require.NoError(t, u.queueUpload(store2, dest2, "key", testrand.Bytes(memory.KiB), 1))
+	require.NoError(t, u.close())
+
+	names, err := opts.spool.list()
+	require.NoError(t, err)
+	require.Len(t, names, 6)
+
+	// simulate a restart with a working storage.
+	store.setFailing(false)
+	store2.setFailing(false)
+	opts.spool = newSpool(log, dir, memory.MiB)
+	opts.spoolRetryInterval = 10 * time.Millisecond
+
+	u = newSequentialUploader(log, opts)
+	u.recoverSpool(func(dest Key) (Storage, error) {
+		switch dest {
+		case dest1:
+			return store, nil
+		case dest2:
+			return store2, nil
+		}
+		return nil, errs.New("unknown destination %v", dest)
+	})
+	ctx.Go(u.run)
+
+	require.Eventually(t, func() bool {
+		names, err := opts.spool.list()
+		require.NoError(t, err)
+		return len(names) == 0
+	}, 10*time.Second, 10*time.Millisecond)
+	require.NoError(t, u.close())
+
+	require.Equal(t, 5, store.count("bucket"))
+	require.Equal(t, 1, store2.count("bucket"))
+	require.Zero(t, opts.spool.size)
+}
+
+// countingStorage counts uploads and fails all of them.
+type countingStorage struct {
+	puts atomic.Int64
+}
+
+func (s *countingStorage) Put(ctx context.Context, bucket, key string, body []byte) error {
+	s.puts.Add(1)
+	return errs.New("failing")
+}
+
+func TestUploaderCloseWithPendingRetry(t *testing.T) {
+	t.Parallel()
+
+	ctx := testcontext.New(t)
+
+	log := zaptest.NewLogger(t)
+	defer ctx.Check(log.Sync)
+
+	for _, spooling := range []bool{false, true} {
+		opts := sequentialUploaderOptions{
+			entryLimit:      5 * memory.KiB,
+			queueLimit:      2,
+			retryLimit:      3,
+			retryBackoff:    time.Hour,
+			shutdownTimeout: 10 * time.Millisecond,
+		}
+		if spooling {
+			opts.spool = newSpool(log, t.TempDir(), memory.MiB)
+		}
+
+		store := &countingStorage{}
+		u := newSequentialUploader(log, opts)
+		ctx.Go(u.run)
+
+		require.NoError(t, u.queueUpload(store, Key{Bucket: "bucket"}, "key", testrand.Bytes(memory.KiB), 1))
+		require.Eventually(t, func() bool {
+			u.mu.Lock()
+			defer u.mu.Unlock()
+			return len(u.retries) == 1
+		}, 10*time.Second, time.Millisecond)
+
+		// the retry is still in backoff, hence close times out.
+		require.ErrorIs(t, u.close(), context.DeadlineExceeded)
+		require.True(t, u.runDone.Wait(ctx))
+		require.Empty(t, u.retries)
+		require.EqualValues(t, 1, store.puts.Load())
+
+		if spooling {
+			names, err := opts.spool.list()
+			require.NoError(t, err)
+			require.Len(t, names, 1)
+
+			loaded, err := opts.spool.load(names[0])
+			require.NoError(t, err)
+			require.Equal(t, Key{Bucket: "bucket"}, loaded.dest)
+		}
+	}
+}
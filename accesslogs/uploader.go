@@ -56,8 +56,9 @@ func (s *inMemoryStorage) Put(_ context.Context, bucket, key string, body []byte
 }
 
 type uploader interface {
-	queueUpload(store Storage, bucket, key string, body []byte) error
-	queueUploadWithoutQueueLimit(store Storage, bucket, key string, body []byte) error
+	queueUpload(store Storage, dest Key, key string, body []byte, entries int) error
+	queueUploadWithoutQueueLimit(store Storage, dest Key, key string, body []byte, entries int) error
+	recoverSpool(resolve func(dest Key) (Storage, error))
 	run() error
 	close() error
 }
@@ -65,51 +66,105 @@ type uploader interface {
 var _ uploader = (*sequentialUploader)(nil)
 
 type upload struct {
-	store   Storage
-	bucket  string
+	store Storage
+	// dest identifies the destination of the upload, it's used to find the
+	// store for uploads spooled by previous runs.
+	dest    Key
 	key     string
 	body    []byte
+	entries int
 	retries int
+	// spooled is the name of the spool file that holds the upload, if any.
+	spooled string
+}
+
+// retry is an upload waiting for its backoff delay to pass.
+type retry struct {
+	up    upload
+	timer *time.Timer
+	due   bool
 }
 
 type sequentialUploader struct {
 	log *zap.Logger
 
-	entryLimit      memory.Size
-	queueLimit      int
-	retryLimit      int
-	shutdownTimeout time.Duration
+	entryLimit         memory.Size
+	queueLimit         int
+	retryLimit         int
+	retryBackoff       time.Duration
+	maxRetryBackoff    time.Duration
+	shutdownTimeout    time.Duration
+	spool              *spool
+	spoolRetryInterval time.Duration
 
 	mu          sync.Mutex
 	queue       chan upload
 	queueLen    int
 	queueClosed bool
 
+	// retries contains uploads waiting to be retried, retryDue is notified
+	// when some of them are due.
+	retries  []*retry
+	retryDue chan struct{}
+
+	// spoolStores remembers stores for uploads spooled by this process.
+	spoolStores map[string]Storage
+	// spoolQueued contains spool files that are currently queued.
+	spoolQueued map[string]struct{}
+	// spoolResolve returns stores for uploads spooled by previous runs.
+	spoolResolve func(dest Key) (Storage, error)
+
 	closing      sync2.Event
 	queueDrained sync2.Event
+
+	// runCtx is canceled to stop run, when close times out.
+	runCtx     context.Context
+	stopRun    context.CancelFunc
+	runStarted bool
+	runDone    sync2.Fence
 }
 
 type sequentialUploaderOptions struct {
 	entryLimit      memory.Size
 	queueLimit      int
 	retryLimit      int
+	retryBackoff    time.Duration
+	maxRetryBackoff time.Duration
 	shutdownTimeout time.Duration
+
+	// spool, if not nil, keeps uploads that couldn't be queued or
+	// uploaded on disk.
+	spool              *spool
+	spoolRetryInterval time.Duration
 }
 
 func newSequentialUploader(log *zap.Logger, opts sequentialUploaderOptions) *sequentialUploader {
+	runCtx, stopRun := context.WithCancel(context.Background())
 	return &sequentialUploader{
-		log:             log.Named("sequential uploader"),
-		entryLimit:      opts.entryLimit,
-		queueLimit:      opts.queueLimit,
-		retryLimit:      opts.retryLimit,
-		shutdownTimeout: opts.shutdownTimeout,
-		queue:           make(chan upload, opts.queueLimit),
+		log:                log.Named("sequential uploader"),
+		entryLimit:         opts.entryLimit,
+		queueLimit:         opts.queueLimit,
+		retryLimit:         opts.retryLimit,
+		retryBackoff:       opts.retryBackoff,
+		maxRetryBackoff:    opts.maxRetryBackoff,
+		shutdownTimeout:    opts.shutdownTimeout,
+		spool:              opts.spool,
+		spoolRetryInterval: opts.spoolRetryInterval,
+		queue:              make(chan upload, opts.queueLimit),
+		retryDue:           make(chan struct{}, 1),
+		spoolStores:        make(map[string]Storage),
+		spoolQueued:        make(map[string]struct{}),
+		runCtx:             runCtx,
+		stopRun:            stopRun,
 	}
 }
 
-var monQueueLength = mon.IntVal("queue_length")
+var (
+	monQueueLength    = mon.IntVal("queue_length")
+	monDroppedEntries = mon.Counter("dropped_entries")
+)
 
-func (u *sequentialUploader) queueUpload(store Storage, bucket, key string, body []byte) error {
+func (u *sequentialUploader) queueUpload(store Storage, dest Key, key string, body []byte, entries int) error {
 	u.mu.Lock()
 	if u.queueClosed {
 		u.mu.Unlock()
@@ -122,6 +177,17 @@ func (u *sequentialUploader) queueUpload(store Storage, bucket, key string, body
 		u.mu.Unlock()
 		mon.Event("queue_limit_reached")
 		u.log.Info("queue limit reached", zap.Int("limit", u.queueLimit))
+		if u.spool != nil {
+			if err := u.spoolUpload(upload{
+				store:   store,
+				dest:    dest,
+				key:     key,
+				body:    body,
+				entries: entries,
+			}); err == nil {
+				return nil
+			}
+		}
 		return ErrQueueLimit
 	}
 	u.queueLen++
@@ -130,16 +196,17 @@ func (u *sequentialUploader) queueUpload(store Storage, bucket, key string, body
 
 	u.queue <- upload{
 		store:   store,
-		bucket:  bucket,
+		dest:    dest,
 		key:     key,
 		body:    body,
+		entries: entries,
 		retries: 0,
 	}
 
 	return nil
 }
 
-func (u *sequentialUploader) queueUploadWithoutQueueLimit(store Storage, bucket, key string, body []byte) error {
+func (u *sequentialUploader) queueUploadWithoutQueueLimit(store Storage, dest Key, key string, body []byte, entries int) error {
 	u.mu.Lock()
 	if u.queueClosed {
 		u.mu.Unlock()
@@ -155,15 +222,104 @@ func (u *sequentialUploader) queueUploadWithoutQueueLimit(store Storage, bucket,
 
 	u.queue <- upload{
 		store:   store,
-		bucket:  bucket,
+		dest:    dest,
 		key:     key,
 		body:    body,
+		entries: entries,
 		retries: 0,
 	}
 
 	return nil
 }
 
+// recoverSpool sets the function used to find stores for uploads spooled by
+// previous runs.
+func (u *sequentialUploader) recoverSpool(resolve func(dest Key) (Storage, error)) {
+	u.mu.Lock()
+	defer u.mu.Unlock()
+
+	u.spoolResolve = resolve
+}
+
+// spoolUpload writes the upload to the spool, so it can be retried later.
+func (u *sequentialUploader) spoolUpload(up upload) error {
+	name, err := u.spool.put(up)
+	if err != nil {
+		u.log.Warn("couldn't spool",
+			zap.String("bucket", up.dest.Bucket),
+			zap.String("prefix", up.key),
+			zap.Error(err),
+		)
+		return err
+	}
+
+	u.mu.Lock()
+	u.spoolStores[name] = up.store
+	u.mu.Unlock()
+
+	mon.Event("upload_spooled")
+	return nil
+}
+
+// requeueSpooled queues spooled uploads while there's room in the queue.
+func (u *sequentialUploader) requeueSpooled() {
+	names, err := u.spool.list()
+	if err != nil {
+		u.log.Warn("couldn't list spool", zap.Error(err))
+		return
+	}
+
+	for _, name := range names {
+		u.mu.Lock()
+		if u.queueClosed || u.queueLen >= u.queueLimit {
+			u.mu.Unlock()
+			return
+		}
+		_, queued := u.spoolQueued[name]
+		store, known := u.spoolStores[name]
+		resolve := u.spoolResolve
+		u.mu.Unlock()
+
+		if queued || (!known && resolve == nil) {
+			continue
+		}
+
+		up, err := u.spool.load(name)
+		if err != nil {
+			u.log.Warn("couldn't load spooled upload", zap.String("name", name), zap.Error(err))
+			continue
+		}
+		if !known {
+			if store, err = resolve(up.dest); err != nil {
+				u.log.Warn("couldn't find storage for spooled upload",
+					zap.String("public_project_id", up.dest.PublicProjectID.String()),
+					zap.String("bucket", up.dest.Bucket),
+					zap.String("prefix", up.dest.Prefix),
+					zap.Error(err),
+				)
+				continue
+			}
+		}
+		up.store = store
+
+		u.mu.Lock()
+		if u.queueClosed {
+			u.mu.Unlock()
+			return
+		}
+		select {
+		case u.queue <- up:
+			u.queueLen++
+			monQueueLength.Observe(int64(u.queueLen))
+			u.spoolQueued[name] = struct{}{}
+			u.mu.Unlock()
+		default:
+			u.mu.Unlock()
+			return
+		}
+	}
+}
+
 func (u *sequentialUploader) close() error {
 	u.mu.Lock()
 	if u.queueClosed {
@@ -179,6 +335,17 @@ func (u *sequentialUploader) close() error {
 	defer cancel()
 
 	if !u.queueDrained.Wait(ctx) {
+		// stop run first, so that nothing consumes the queue or the retries
+		// while they're spooled.
+		u.stopRun()
+		u.mu.Lock()
+		started := u.runStarted
+		u.mu.Unlock()
+		if started {
+			u.runDone.Wait(context.Background())
+		}
+
+		u.spoolRemaining()
 		return ctx.Err()
 	} else {
 		close(u.queue)
@@ -187,36 +354,77 @@ func (u *sequentialUploader) close() error {
 	return nil
 }
 
+// spoolRemaining moves uploads still waiting in the queue or for a retry to
+// the spool. It must be called after run has stopped.
+func (u *sequentialUploader) spoolRemaining() {
+	u.mu.Lock()
+	retries := u.retries
+	u.retries = nil
+	u.mu.Unlock()
+
+	for _, r := range retries {
+		r.timer.Stop()
+		u.spoolOrDrop(r.up)
+	}
+
+	for {
+		select {
+		case up := <-u.queue:
+			u.spoolOrDrop(up)
+		default:
+			return
+		}
+	}
+}
+
+// spoolOrDrop spools an upload, which won't be attempted by this uploader
+// anymore, or drops it if spooling isn't possible.
+func (u *sequentialUploader) spoolOrDrop(up upload) {
+	if up.spooled != "" {
+		// the upload is already on disk.
+		return
+	}
+	if u.spool != nil && u.spoolUpload(up) == nil {
+		return
+	}
+	mon.Event("upload_dropped")
+	monDroppedEntries.Inc(int64(up.entries))
+}
+
 func (u *sequentialUploader) run() error {
+	u.mu.Lock()
+	u.runStarted = true
+	u.mu.Unlock()
+	defer u.runDone.Release()
+
+	var spoolRetry <-chan time.Time
+	if u.spool != nil {
+		u.requeueSpooled()
+		if u.spoolRetryInterval > 0 {
+			ticker := time.NewTicker(u.spoolRetryInterval)
+			defer ticker.Stop()
+			spoolRetry = ticker.C
+		}
+	}
+
 	var closing bool
+	closingSignal := u.closing.Signaled()
 	for {
 		select {
 		case up := <-u.queue:
-			// TODO(artur): we need to figure out what context we want
-			// to pass here. WithTimeout(Background, …)?
-			if err := up.store.Put(context.TODO(), up.bucket, up.key, up.body); err != nil {
-				if up.retries == u.retryLimit {
-					mon.Event("upload_dropped")
-					u.log.Error("retry limit reached",
-						zap.String("bucket", up.bucket),
-						zap.String("prefix", up.key),
-						zap.Error(err),
-					)
-					if done := u.decrementQueueLen(closing); done {
-						return nil
-					}
-					continue // NOTE(artur): here we could spill to disk or something
-				}
-				up.retries++
-				u.queue <- up // failure; don't decrement u.queueLen
-				mon.Event("upload_failed")
-				continue
-			}
-			mon.Event("upload_successful")
-			if done := u.decrementQueueLen(closing); done {
+			if done := u.process(up, closing); done {
 				return nil
 			}
-		case <-u.closing.Signaled():
+		case <-u.retryDue:
+			for _, up := range u.takeDueRetries() {
+				if done := u.process(up, closing); done {
+					return nil
+				}
+			}
+		case <-spoolRetry:
+			u.requeueSpooled()
+		case <-closingSignal:
+			closingSignal = nil
 			u.mu.Lock()
 			if u.queueLen == 0 {
 				u.mu.Unlock()
@@ -226,7 +434,119 @@ func (u *sequentialUploader) run() error {
 				u.mu.Unlock()
 				closing = true
 			}
+		case <-u.runCtx.Done():
+			return nil
+		}
+	}
+}
+
+// process uploads up and handles a failure. It returns true, when run should
+// return.
+func (u *sequentialUploader) process(up upload, closing bool) (done bool) {
+	if err := up.store.Put(u.runCtx, up.dest.Bucket, up.key, up.body); err != nil {
+		if u.runCtx.Err() != nil {
+			// close timed out, the upload won't be attempted anymore.
+			u.spoolOrDrop(up)
+			return true
+		}
+		if up.retries == u.retryLimit {
+			u.giveUp(up, err)
+			return u.decrementQueueLen(closing)
+		}
+		up.retries++
+		// failure; don't decrement u.queueLen
+		u.retryAfter(up, closing)
+		mon.Event("upload_failed")
+		return false
+	}
+	mon.Event("upload_successful")
+	if up.spooled != "" {
+		u.unspool(up.spooled)
+	}
+	return u.decrementQueueLen(closing)
+}
+
+// retryAfter schedules the upload to be retried after a backoff delay. There
+// is no delay when closing, so that shutdown isn't delayed unnecessarily.
+func (u *sequentialUploader) retryAfter(up upload, closing bool) {
+	var delay time.Duration
+	if !closing && u.retryBackoff > 0 {
+		delay = u.retryBackoff << (up.retries - 1)
+		if u.maxRetryBackoff > 0 && (delay > u.maxRetryBackoff || delay <= 0) {
+			delay = u.maxRetryBackoff
+		}
+	}
+
+	r := &retry{up: up}
+
+	u.mu.Lock()
+	defer u.mu.Unlock()
+
+	u.retries = append(u.retries, r)
+	r.timer = time.AfterFunc(delay, func() {
+		u.mu.Lock()
+		r.due = true
+		u.mu.Unlock()
+
+		select {
+		case u.retryDue <- struct{}{}:
+		default:
 		}
+	})
+}
+
+// takeDueRetries removes and returns the uploads, which are due to be retried.
+func (u *sequentialUploader) takeDueRetries() []upload {
+	u.mu.Lock()
+	defer u.mu.Unlock()
+
+	var due []upload
+	pending := u.retries[:0]
+	for _, r := range u.retries {
+		if r.due {
+			due = append(due, r.up)
+		} else {
+			pending = append(pending, r)
+		}
+	}
+	clear(u.retries[len(pending):])
+	u.retries = pending
+	return due
+}
+
+// giveUp handles an upload that reached the retry limit by spooling it, or
+// dropping it if spooling isn't possible.
+func (u *sequentialUploader) giveUp(up upload, err error) {
+	if up.spooled != "" {
+		// the upload is already on disk; keep it there for the next attempt.
+		u.mu.Lock()
+		delete(u.spoolQueued, up.spooled)
+		u.mu.Unlock()
+		mon.Event("upload_respooled")
+		return
+	}
+	if u.spool != nil && u.spoolUpload(up) == nil {
+		return
+	}
+
+	mon.Event("upload_dropped")
+	monDroppedEntries.Inc(int64(up.entries))
+	u.log.Error("retry limit reached",
+		zap.String("bucket", up.dest.Bucket),
+		zap.String("prefix", up.key),
+		zap.Error(err),
+	)
+}
+
+// unspool removes a successfully uploaded spool file.
+func (u *sequentialUploader) unspool(name string) {
+	u.mu.Lock()
+	delete(u.spoolQueued, name)
+	delete(u.spoolStores, name)
+	u.mu.Unlock()
+
+	if err := u.spool.remove(name); err != nil {
+		u.log.Warn("couldn't remove spooled upload", zap.String("name", name), zap.Error(err))
 	}
 }
 
@@ -34,11 +34,11 @@ func TestLimits(t *testing.T) {
 	})
 
 	for range 2 {
-		require.NoError(t, u.queueUpload(s, "test", "test", testrand.Bytes(memory.KiB)))
+		require.NoError(t, u.queueUpload(s, Key{Bucket: "test"}, "test", testrand.Bytes(memory.KiB), 1))
 	}
-	require.ErrorIs(t, u.queueUpload(s, "test", "test", testrand.Bytes(memory.KiB)), ErrQueueLimit)
-	require.ErrorIs(t, u.queueUpload(s, "test", "test", testrand.Bytes(6*memory.KiB)), ErrTooLarge)
-	require.ErrorIs(t, u.queueUploadWithoutQueueLimit(s, "test", "test", testrand.Bytes(6*memory.KiB)), ErrTooLarge)
+	require.ErrorIs(t, u.queueUpload(s, Key{Bucket: "test"}, "test", testrand.Bytes(memory.KiB), 1), ErrQueueLimit)
+	require.ErrorIs(t, u.queueUpload(s, Key{Bucket: "test"}, "test", testrand.Bytes(6*memory.KiB), 1), ErrTooLarge)
+	require.ErrorIs(t, u.queueUploadWithoutQueueLimit(s, Key{Bucket: "test"}, "test", testrand.Bytes(6*memory.KiB), 1), ErrTooLarge)
 }
 
 func TestQueueNoLimit(t *testing.T) {
@@ -60,7 +60,7 @@ func TestQueueNoLimit(t *testing.T) {
 	ctx.Go(u.run)
 
 	for range 10 {
-		require.NoError(t, u.queueUploadWithoutQueueLimit(s, "test", "test", testrand.Bytes(memory.KiB)))
+		require.NoError(t, u.queueUploadWithoutQueueLimit(s, Key{Bucket: "test"}, "test", testrand.Bytes(memory.KiB), 1))
 	}
 }
 
@@ -90,7 +90,7 @@ func TestQueueNoLimitErroringStorage(t *testing.T) {
 	ctx.Go(u.run)
 
 	for range 10 {
-		require.NoError(t, u.queueUploadWithoutQueueLimit(s, "test", "test", testrand.Bytes(memory.KiB)))
+		require.NoError(t, u.queueUploadWithoutQueueLimit(s, Key{Bucket: "test"}, "test", testrand.Bytes(memory.KiB), 1))
 	}
 }
 
@@ -113,6 +113,6 @@ func TestQueueErroringStorage(t *testing.T) {
 	ctx.Go(u.run)
 
 	for range 10 {
-		require.NoError(t, u.queueUpload(s, "test", "test", testrand.Bytes(memory.KiB)))
+		require.NoError(t, u.queueUpload(s, Key{Bucket: "test"}, "test", testrand.Bytes(memory.KiB), 1))
 	}
 }
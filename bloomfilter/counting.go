@@ -0,0 +1,215 @@
+// Copyright (C) 2026 Storj Labs, Inc.
+// See LICENSE for copying information.
+
+package bloomfilter
+
+import (
+	"math"
+
+	"github.com/bmkessler/fastdiv"
+	"github.com/zeebo/errs"
+
+	"storj.io/common/memory"
+	"storj.io/common/storj"
+)
+
+const (
+	// countingVersion1 has the high bit set so that the encoding cannot be
+	// confused with the compact Filter encoding.
+	countingVersion1 = 0x80 | 1
+)
+
+// CountingFilter is a bloom filter that supports removing elements.
+//
+// Every bit of the compact Filter is replaced with a saturating 8-bit counter.
+// The filter uses the same hashing as Filter, so it can be converted into the
+// compact Filter wire format with Bytes.
+type CountingFilter struct {
+	seed      byte
+	hashCount byte
+	// counters contains 8 counters for every byte in the equivalent Filter table.
+	counters []byte
+
+	offset      byte
+	rangeOffset byte
+	tableSize   fastdiv.Uint64
+}
+
+// NewCountingExplicit returns a new counting filter with the explicit seed and parameters.
+//
+// sizeInBytes is the size of the equivalent compact Filter table, the counting
+// filter uses 8 times more memory.
+func NewCountingExplicit(seed, hashCount byte, sizeInBytes int) *CountingFilter {
+	offset, rangeOffset := initialConditions(seed)
+
+	return &CountingFilter{
+		seed:      seed,
+		hashCount: hashCount,
+		counters:  make([]byte, sizeInBytes*8),
+
+		offset:      offset,
+		rangeOffset: rangeOffset,
+		tableSize:   fastdiv.NewUint64(uint64(sizeInBytes)),
+	}
+}
+
+// NewCountingOptimal returns a counting filter based on expected element count and false positive rate.
+func NewCountingOptimal(expectedElements int64, falsePositiveRate float64) *CountingFilter {
+	hashCount, sizeInBytes := OptimalParameters(expectedElements, falsePositiveRate, 0)
+	seed := GenerateSeed()
+
+	return NewCountingExplicit(seed, hashCount, sizeInBytes)
+}
+
+// NewCountingOptimalMaxSize returns a counting filter based on expected element count and false positive rate,
+// where the equivalent compact Filter is capped at a maximum size in bytes.
+func NewCountingOptimalMaxSize(expectedElements int64, falsePositiveRate float64, maxSize memory.Size) *CountingFilter {
+	hashCount, sizeInBytes := OptimalParameters(expectedElements, falsePositiveRate, maxSize)
+	seed := GenerateSeed()
+
+	return NewCountingExplicit(seed, hashCount, sizeInBytes)
+}
+
+// Parameters returns filter parameters of the equivalent compact Filter.
+func (filter *CountingFilter) Parameters() (hashCount, size int) {
+	return int(filter.hashCount), len(filter.counters) / 8
+}
+
+// SeedAndParameters returns the seed along with the filter parameters.
+func (filter *CountingFilter) SeedAndParameters() (seed, hashCount byte, size int) {
+	return filter.seed, filter.hashCount, len(filter.counters) / 8
+}
+
+// Add adds an element to the counting filter.
+func (filter *CountingFilter) Add(pieceID storj.PieceID) {
+	id, offset := doubleID(pieceID), filter.offset
+	for h := int(filter.hashCount); h > 0; h-- {
+		hash, bit, next := nextHash(&id, offset, filter.rangeOffset)
+		offset = next
+		counter := filter.tableSize.Mod(hash)*8 + uint64(bit%8)
+		if filter.counters[counter] < math.MaxUint8 {
+			filter.counters[counter]++
+		}
+	}
+}
+
+// Remove removes an element from the counting filter.
+//
+// Removing an element that was not added may introduce false negatives.
+// Counters that have saturated are never decremented.
+func (filter *CountingFilter) Remove(pieceID storj.PieceID) {
+	if !filter.Contains(pieceID) {
+		return
+	}
+
+	// NB: several hashes may land on the same counter, which is decremented
+	// for each of them, as Add increments it for each of them. The counter
+	// must not underflow, when the element is a false positive.
+	id, offset := doubleID(pieceID), filter.offset
+	for h := int(filter.hashCount); h > 0; h-- {
+		hash, bit, next := nextHash(&id, offset, filter.rangeOffset)
+		offset = next
+		counter := filter.tableSize.Mod(hash)*8 + uint64(bit%8)
+		if count := filter.counters[counter]; count > 0 && count < math.MaxUint8 {
+			filter.counters[counter]--
+		}
+	}
+}
+
+// Contains return true if pieceID may be in the set.
+func (filter *CountingFilter) Contains(pieceID storj.PieceID) bool {
+	id, offset := doubleID(pieceID), filter.offset
+	for h := int(filter.hashCount); h > 0; h-- {
+		hash, bit, next := nextHash(&id, offset, filter.rangeOffset)
+		offset = next
+		counter := filter.tableSize.Mod(hash)*8 + uint64(bit%8)
+		if filter.counters[counter] == 0 {
+			return false
+		}
+	}
+
+	return true
+}
+
+// Filter returns the equivalent compact Filter.
+func (filter *CountingFilter) Filter() *Filter {
+	compact := &Filter{
+		seed:      filter.seed,
+		hashCount: filter.hashCount,
+		table:     make([]byte, len(filter.counters)/8),
+
+		offset:      filter.offset,
+		rangeOffset: filter.rangeOffset,
+		tableSize:   filter.tableSize,
+	}
+	for i, count := range filter.counters {
+		if count > 0 {
+			compact.table[i/8] |= 1 << (i % 8)
+		}
+	}
+	return compact
+}
+
+// FillRate calculates the proportion of bits filled in the equivalent compact Filter.
+func (filter *CountingFilter) FillRate() float64 {
+	filled := uint64(0)
+	for _, count := range filter.counters {
+		if count > 0 {
+			filled++
+		}
+	}
+	return float64(filled) / float64(len(filter.counters))
+}
+
+// Bytes encodes the filter into the compact Filter wire format.
+// Use NewFromBytes to decode it.
+func (filter *CountingFilter) Bytes() []byte {
+	return filter.Filter().Bytes()
+}
+
+// Size returns the size of Bytes call.
+func (filter *CountingFilter) Size() int64 {
+	// the first three bytes represent the version, seed, and hash count
+	return int64(1 + 1 + 1 + len(filter.counters)/8)
+}
+
+// CountingBytes encodes the filter including the counters.
+// Use NewCountingFromBytes to decode it.
+func (filter *CountingFilter) CountingBytes() []byte {
+	bytes := make([]byte, 1+1+1+len(filter.counters))
+	bytes[0] = countingVersion1
+	bytes[1] = filter.seed
+	bytes[2] = filter.hashCount
+	copy(bytes[3:], filter.counters)
+	return bytes
+}
+
+// NewCountingFromBytes decodes the counting filter from a sequence of bytes
+// produced by CountingBytes.
+//
+// Note: data will be referenced inside the counters.
+func NewCountingFromBytes(data []byte) (*CountingFilter, error) {
+	if len(data) < 3 {
+		return nil, errs.New("not enough data")
+	}
+	if data[0] != countingVersion1 {
+		return nil, errs.New("unsupported version %d", data[0])
+	}
+
+	filter := &CountingFilter{}
+	filter.seed = data[1]
+	filter.hashCount = data[2]
+	filter.counters = data[3:]
+
+	if filter.hashCount == 0 {
+		return nil, errs.New("invalid hash count %d", filter.hashCount)
+	}
+	if len(filter.counters) == 0 || len(filter.counters)%8 != 0 {
+		return nil, errs.New("invalid counters length %d", len(filter.counters))
+	}
+
+	filter.offset, filter.rangeOffset = initialConditions(filter.seed)
+	filter.tableSize = fastdiv.NewUint64(uint64(len(filter.counters) / 8))
+
+	return filter, nil
+}
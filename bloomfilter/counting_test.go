@@ -0,0 +1,107 @@
+// Copyright (C) 2026 Storj Labs, Inc.
+// See LICENSE for copying information.
+
+package bloomfilter_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"storj.io/common/bloomfilter"
+)
+
+func TestCountingFilter_Remove(t *testing.T) {
+	kept := generateTestIDs(1000)
+	removed := generateTestIDs(1000)
+
+	filter := bloomfilter.NewCountingOptimal(2000, 0.01)
+	for _, id := range kept {
+		filter.Add(id)
+	}
+	for _, id := range removed {
+		filter.Add(id)
+	}
+	for _, id := range removed {
+		require.True(t, filter.Contains(id))
+		filter.Remove(id)
+	}
+
+	for _, id := range kept {
+		require.True(t, filter.Contains(id))
+	}
+
+	falsePositives := 0
+	for _, id := range removed {
+		if filter.Contains(id) {
+			falsePositives++
+		}
+	}
+	require.Less(t, falsePositives, len(removed)/10)
+}
+
+func TestCountingFilter_MatchesFilter(t *testing.T) {
+	ids := generateTestIDs(1000)
+
+	counting := bloomfilter.NewCountingOptimal(int64(len(ids)), 0.1)
+	filter := bloomfilter.NewExplicit(counting.SeedAndParameters())
+	for _, id := range ids {
+		counting.Add(id)
+		filter.Add(id)
+	}
+
+	require.Equal(t, filter.Bytes(), counting.Bytes())
+	require.Equal(t, filter.Size(), counting.Size())
+	require.Equal(t, filter.FillRate(), counting.FillRate())
+
+	decoded, err := bloomfilter.NewFromBytes(counting.Bytes())
+	require.NoError(t, err)
+	for _, id := range ids {
+		require.True(t, decoded.Contains(id))
+	}
+}
+
+func TestCountingFilter_CountingBytes(t *testing.T) {
+	for _, count := range []int64{0, 100, 1000, 10000} {
+		filter := bloomfilter.NewCountingOptimal(count, 0.1)
+		for _, id := range generateTestIDs(int(count)) {
+			filter.Add(id)
+		}
+
+		unmarshaled, err := bloomfilter.NewCountingFromBytes(filter.CountingBytes())
+		require.NoError(t, err)
+
+		require.Equal(t, filter, unmarshaled)
+	}
+
+	failing := [][]byte{
+		{},
+		{0x81},
+		{0x81, 0},
+		{0x81, 10, 10, 10},
+		{0x81, 10, 0, 0, 0, 0, 0, 0, 0, 0, 0},
+		{1, 10, 10, 0, 0, 0, 0, 0, 0, 0, 0},
+	}
+	for _, bytes := range failing {
+		_, err := bloomfilter.NewCountingFromBytes(bytes)
+		require.Error(t, err)
+	}
+
+	_, err := bloomfilter.NewFromBytes(bloomfilter.NewCountingExplicit(1, 1, 1).CountingBytes())
+	require.Error(t, err)
+}
+
+func TestCountingFilter_RemoveCollision(t *testing.T) {
+	// with a tiny table the hashes of an element often land on the same
+	// counter, such counters must not be decremented twice.
+	ids := generateTestIDs(1000)
+	for _, id := range ids[1:] {
+		filter := bloomfilter.NewCountingExplicit(0, 8, 1)
+		filter.Add(ids[0])
+		if !filter.Contains(id) {
+			continue
+		}
+		filter.Remove(id)
+		require.NotContains(t, filter.CountingBytes()[3:], byte(255))
+	}
+}
@@ -0,0 +1,288 @@
+// Copyright (C) 2026 Storj Labs, Inc.
+// See LICENSE for copying information.
+
+package bloomfilter
+
+import (
+	"errors"
+	"io"
+	"os"
+
+	"github.com/bmkessler/fastdiv"
+	"github.com/zeebo/errs"
+
+	"storj.io/common/storj"
+)
+
+const (
+	// filePageSize is the size of a single cached page of a FileFilter.
+	filePageSize = 64 << 10
+	// fileCachedPages is the maximum number of pages a FileFilter keeps in memory.
+	fileCachedPages = 64
+	// fileHeaderSize is the size of the version, seed and hash count header.
+	fileHeaderSize = 3
+)
+
+// FileFilter is a bloom filter that keeps the table in a file, using the
+// same encoding as Filter.Bytes. Only a bounded number of table pages are
+// kept in memory.
+//
+// Add and Contains do not return errors; the first I/O error is remembered
+// and returned from Flush and Close. After a failed read, Contains
+// conservatively reports true.
+type FileFilter struct {
+	file *os.File
+
+	seed      byte
+	hashCount byte
+	size      int64
+
+	offset      byte
+	rangeOffset byte
+	tableSize   fastdiv.Uint64
+
+	pages map[int64]*filePage
+	order []int64
+	err   error
+}
+
+type filePage struct {
+	data  []byte
+	dirty bool
+}
+
+// CreateFileFilter creates a new empty filter at path with the explicit seed and parameters.
+func CreateFileFilter(path string, seed, hashCount byte, sizeInBytes int) (_ *FileFilter, err error) {
+	if hashCount == 0 {
+		return nil, errs.New("invalid hash count %d", hashCount)
+	}
+	if sizeInBytes <= 0 {
+		return nil, errs.New("invalid size %d", sizeInBytes)
+	}
+
+	file, err := os.OpenFile(path, os.O_RDWR|os.O_CREATE|os.O_EXCL, 0644)
+	if err != nil {
+		return nil, errs.Wrap(err)
+	}
+	defer func() {
+		if err != nil {
+			err = errs.Combine(err, file.Close(), os.Remove(path))
+		}
+	}()
+
+	if _, err := file.Write([]byte{version1, seed, hashCount}); err != nil {
+		return nil, errs.Wrap(err)
+	}
+	if err := file.Truncate(fileHeaderSize + int64(sizeInBytes)); err != nil {
+		return nil, errs.Wrap(err)
+	}
+
+	return newFileFilter(file, seed, hashCount, int64(sizeInBytes)), nil
+}
+
+// OpenFileFilter opens an existing filter at path, which contains the
+// encoding produced by Filter.Bytes or Filter.WriteTo.
+func OpenFileFilter(path string) (_ *FileFilter, err error) {
+	file, err := os.OpenFile(path, os.O_RDWR, 0)
+	if err != nil {
+		return nil, errs.Wrap(err)
+	}
+	defer func() {
+		if err != nil {
+			err = errs.Combine(err, file.Close())
+		}
+	}()
+
+	stat, err := file.Stat()
+	if err != nil {
+		return nil, errs.Wrap(err)
+	}
+	if stat.Size() <= fileHeaderSize {
+		return nil, errs.New("not enough data")
+	}
+
+	var header [fileHeaderSize]byte
+	if _, err := io.ReadFull(file, header[:]); err != nil {
+		return nil, errs.Wrap(err)
+	}
+	if err := checkHeader(header); err != nil {
+		return nil, err
+	}
+
+	return newFileFilter(file, header[1], header[2], stat.Size()-fileHeaderSize), nil
+}
+
+// CreateFileFilterFromReader creates a new filter at path from r, which
+// contains the encoding produced by Filter.Bytes or Filter.WriteTo. The table
+// is copied to the file in chunks, so r may be larger than the memory.
+func CreateFileFilterFromReader(path string, r io.Reader) (_ *FileFilter, err error) {
+	var header [fileHeaderSize]byte
+	if _, err := io.ReadFull(r, header[:]); err != nil {
+		return nil, errs.Wrap(err)
+	}
+	if err := checkHeader(header); err != nil {
+		return nil, err
+	}
+
+	file, err := os.OpenFile(path, os.O_RDWR|os.O_CREATE|os.O_EXCL, 0644)
+	if err != nil {
+		return nil, errs.Wrap(err)
+	}
+	defer func() {
+		if err != nil {
+			err = errs.Combine(err, file.Close(), os.Remove(path))
+		}
+	}()
+
+	if _, err := file.Write(header[:]); err != nil {
+		return nil, errs.Wrap(err)
+	}
+	size, err := io.Copy(file, r)
+	if err != nil {
+		return nil, errs.Wrap(err)
+	}
+	if size == 0 {
+		return nil, errs.New("empty table")
+	}
+
+	return newFileFilter(file, header[1], header[2], size), nil
+}
+
+func newFileFilter(file *os.File, seed, hashCount byte, size int64) *FileFilter {
+	offset, rangeOffset := initialConditions(seed)
+	return &FileFilter{
+		file: file,
+
+		seed:      seed,
+		hashCount: hashCount,
+		size:      size,
+
+		offset:      offset,
+		rangeOffset: rangeOffset,
+		tableSize:   fastdiv.NewUint64(uint64(size)),
+
+		pages: map[int64]*filePage{},
+	}
+}
+
+// Parameters returns filter parameters.
+func (filter *FileFilter) Parameters() (hashCount, size int) {
+	return int(filter.hashCount), int(filter.size)
+}
+
+// SeedAndParameters returns the seed along with the filter parameters.
+func (filter *FileFilter) SeedAndParameters() (seed, hashCount byte, size int) {
+	return filter.seed, filter.hashCount, int(filter.size)
+}
+
+// Add adds an element to the bloom filter.
+func (filter *FileFilter) Add(pieceID storj.PieceID) {
+	id, offset := doubleID(pieceID), filter.offset
+	for h := int(filter.hashCount); h > 0; h-- {
+		hash, bit, next := nextHash(&id, offset, filter.rangeOffset)
+		offset = next
+		bucket := int64(filter.tableSize.Mod(hash))
+		page, ok := filter.page(bucket / filePageSize)
+		if !ok {
+			return
+		}
+		mask := byte(1) << (bit % 8)
+		if page.data[bucket%filePageSize]&mask == 0 {
+			page.data[bucket%filePageSize] |= mask
+			page.dirty = true
+		}
+	}
+}
+
+// Contains return true if pieceID may be in the set.
+func (filter *FileFilter) Contains(pieceID storj.PieceID) bool {
+	id, offset := doubleID(pieceID), filter.offset
+	for h := int(filter.hashCount); h > 0; h-- {
+		hash, bit, next := nextHash(&id, offset, filter.rangeOffset)
+		offset = next
+		bucket := int64(filter.tableSize.Mod(hash))
+		page, ok := filter.page(bucket / filePageSize)
+		if !ok {
+			return true
+		}
+		if page.data[bucket%filePageSize]&(1<<(bit%8)) == 0 {
+			return false
+		}
+	}
+
+	return true
+}
+
+// page returns the cached page at index, loading it from the file when necessary.
+func (filter *FileFilter) page(index int64) (*filePage, bool) {
+	if page, ok := filter.pages[index]; ok {
+		return page, true
+	}
+	if filter.err != nil {
+		return nil, false
+	}
+
+	if len(filter.order) >= fileCachedPages {
+		evict := filter.order[0]
+		if err := filter.writePage(evict, filter.pages[evict]); err != nil {
+			filter.err = err
+			return nil, false
+		}
+		delete(filter.pages, evict)
+		filter.order = filter.order[1:]
+	}
+
+	start := index * filePageSize
+	page := &filePage{data: make([]byte, min(filePageSize, filter.size-start))}
+	if _, err := filter.file.ReadAt(page.data, fileHeaderSize+start); err != nil && !errors.Is(err, io.EOF) {
+		filter.err = errs.Wrap(err)
+		return nil, false
+	}
+
+	filter.pages[index] = page
+	filter.order = append(filter.order, index)
+	return page, true
+}
+
+func (filter *FileFilter) writePage(index int64, page *filePage) error {
+	if !page.dirty {
+		return nil
+	}
+	if _, err := filter.file.WriteAt(page.data, fileHeaderSize+index*filePageSize); err != nil {
+		return errs.Wrap(err)
+	}
+	page.dirty = false
+	return nil
+}
+
+// Flush writes all modified pages to the file.
+func (filter *FileFilter) Flush() error {
+	if filter.err != nil {
+		return filter.err
+	}
+	for _, index := range filter.order {
+		if err := filter.writePage(index, filter.pages[index]); err != nil {
+			filter.err = err
+			return err
+		}
+	}
+	return nil
+}
+
+// Size returns the size of the encoded filter.
+func (filter *FileFilter) Size() int64 {
+	return fileHeaderSize + filter.size
+}
+
+// WriteTo flushes the filter and copies the encoded filter to w.
+func (filter *FileFilter) WriteTo(w io.Writer) (int64, error) {
+	if err := filter.Flush(); err != nil {
+		return 0, err
+	}
+	return io.Copy(w, io.NewSectionReader(filter.file, 0, filter.Size()))
+}
+
+// Close flushes the filter and closes the file.
+func (filter *FileFilter) Close() error {
+	return errs.Combine(filter.Flush(), filter.file.Close())
+}
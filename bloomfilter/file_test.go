@@ -0,0 +1,118 @@
+// Copyright (C) 2026 Storj Labs, Inc.
+// See LICENSE for copying information.
+
+package bloomfilter_test
+
+import (
+	"bytes"
+	"io"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"storj.io/common/bloomfilter"
+	"storj.io/common/memory"
+)
+
+func TestFileFilter(t *testing.T) {
+	ids := generateTestIDs(10000)
+	path := filepath.Join(t.TempDir(), "filter")
+
+	// use a table larger than the page cache to exercise eviction
+	seed, hashCount, size := byte(77), byte(3), (5 * memory.MiB).Int()
+
+	filter := bloomfilter.NewExplicit(seed, hashCount, size)
+	fileFilter, err := bloomfilter.CreateFileFilter(path, seed, hashCount, size)
+	require.NoError(t, err)
+
+	for _, id := range ids {
+		filter.Add(id)
+		fileFilter.Add(id)
+	}
+	for _, id := range ids {
+		require.True(t, fileFilter.Contains(id))
+	}
+	for _, id := range generateTestIDs(1000) {
+		require.Equal(t, filter.Contains(id), fileFilter.Contains(id))
+	}
+
+	var buf bytes.Buffer
+	n, err := fileFilter.WriteTo(&buf)
+	require.NoError(t, err)
+	require.Equal(t, filter.Size(), n)
+	require.Equal(t, filter.Bytes(), buf.Bytes())
+	require.NoError(t, fileFilter.Close())
+
+	data, err := os.ReadFile(path)
+	require.NoError(t, err)
+	require.Equal(t, filter.Bytes(), data)
+
+	reopened, err := bloomfilter.OpenFileFilter(path)
+	require.NoError(t, err)
+	defer func() { require.NoError(t, reopened.Close()) }()
+
+	gotSeed, gotHashCount, gotSize := reopened.SeedAndParameters()
+	require.Equal(t, seed, gotSeed)
+	require.Equal(t, hashCount, gotHashCount)
+	require.Equal(t, size, gotSize)
+	for _, id := range ids {
+		require.True(t, reopened.Contains(id))
+	}
+}
+
+func TestFileFilter_FromReader(t *testing.T) {
+	ids := generateTestIDs(10000)
+	path := filepath.Join(t.TempDir(), "filter")
+
+	filter := bloomfilter.NewExplicit(77, 3, (5 * memory.MiB).Int())
+	for _, id := range ids {
+		filter.Add(id)
+	}
+
+	// the size of the stream isn't known up front.
+	r, w := io.Pipe()
+	go func() { _, err := filter.WriteTo(w); _ = w.CloseWithError(err) }()
+
+	fileFilter, err := bloomfilter.CreateFileFilterFromReader(path, r)
+	require.NoError(t, err)
+	defer func() { require.NoError(t, fileFilter.Close()) }()
+
+	seed, hashCount, size := filter.SeedAndParameters()
+	gotSeed, gotHashCount, gotSize := fileFilter.SeedAndParameters()
+	require.Equal(t, seed, gotSeed)
+	require.Equal(t, hashCount, gotHashCount)
+	require.Equal(t, size, gotSize)
+	for _, id := range ids {
+		require.True(t, fileFilter.Contains(id))
+	}
+	for _, id := range generateTestIDs(1000) {
+		require.Equal(t, filter.Contains(id), fileFilter.Contains(id))
+	}
+
+	data, err := os.ReadFile(path)
+	require.NoError(t, err)
+	require.Equal(t, filter.Bytes(), data)
+}
+
+func TestFileFilter_Failing(t *testing.T) {
+	dir := t.TempDir()
+
+	_, err := bloomfilter.CreateFileFilter(filepath.Join(dir, "zero-hash"), 1, 0, 10)
+	require.Error(t, err)
+
+	_, err = bloomfilter.OpenFileFilter(filepath.Join(dir, "missing"))
+	require.Error(t, err)
+
+	for i, data := range [][]byte{{}, {1, 0, 1}, {2, 0, 1, 0}, {1, 0, 0, 0}} {
+		path := filepath.Join(dir, string(rune('a'+i)))
+		require.NoError(t, os.WriteFile(path, data, 0644))
+		_, err := bloomfilter.OpenFileFilter(path)
+		require.Error(t, err)
+
+		_, err = bloomfilter.CreateFileFilterFromReader(path+".copy", bytes.NewReader(data))
+		require.Error(t, err)
+		require.NoFileExists(t, path+".copy")
+	}
+}
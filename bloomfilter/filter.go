@@ -6,6 +6,7 @@ package bloomfilter
 
 import (
 	"encoding/binary"
+	"io"
 	"math"
 	"math/bits"
 	"math/rand"
@@ -84,38 +85,45 @@ func (filter *Filter) SeedAndParameters() (seed, hashCount byte, size int) {
 
 // Add adds an element to the bloom filter.
 func (filter *Filter) Add(pieceID storj.PieceID) {
-	var id [len(pieceID) * 2]byte
-	copy(id[:], pieceID[:])
-	copy(id[len(pieceID):], pieceID[:])
-
-	offset, rangeOffset := filter.offset, filter.rangeOffset
+	id, offset := doubleID(pieceID), filter.offset
 	for h := int(filter.hashCount); h > 0; h-- {
-		hash, bit := binary.LittleEndian.Uint64(id[offset:offset+8]), id[offset+8]
+		hash, bit, next := nextHash(&id, offset, filter.rangeOffset)
+		offset = next
 		bucket := filter.tableSize.Mod(hash)
 		filter.table[bucket] |= 1 << (bit % 8)
-		offset = (offset + rangeOffset) % byte(len(storj.PieceID{}))
 	}
 }
 
 // Contains return true if pieceID may be in the set.
 func (filter *Filter) Contains(pieceID storj.PieceID) bool {
-	var id [len(pieceID) * 2]byte
-	copy(id[:], pieceID[:])
-	copy(id[len(pieceID):], pieceID[:])
-
-	offset, rangeOffset := filter.offset, filter.rangeOffset
-	for k := byte(0); k < filter.hashCount; k++ {
-		hash, bit := binary.LittleEndian.Uint64(id[offset:offset+8]), id[offset+8]
+	id, offset := doubleID(pieceID), filter.offset
+	for h := int(filter.hashCount); h > 0; h-- {
+		hash, bit, next := nextHash(&id, offset, filter.rangeOffset)
+		offset = next
 		bucket := filter.tableSize.Mod(hash)
 		if filter.table[bucket]&(1<<(bit%8)) == 0 {
 			return false
 		}
-		offset = (offset + rangeOffset) % byte(len(storj.PieceID{}))
 	}
 
 	return true
 }
 
+// doubleID returns the piece ID repeated twice, so that a hash can be read at
+// any offset.
+func doubleID(pieceID storj.PieceID) (id [2 * len(storj.PieceID{})]byte) {
+	copy(id[:], pieceID[:])
+	copy(id[len(pieceID):], pieceID[:])
+	return id
+}
+
+// nextHash returns the hash at offset, which selects the byte in the table,
+// the byte, which selects the bit within it modulo 8, and the offset of the
+// next hash.
+func nextHash(id *[2 * len(storj.PieceID{})]byte, offset, rangeOffset byte) (hash uint64, bit, next byte) {
+	return binary.LittleEndian.Uint64(id[offset : offset+8]), id[offset+8], (offset + rangeOffset) % byte(len(storj.PieceID{}))
+}
+
 func initialConditions(seed byte) (initialOffset, rangeOffset byte) {
 	initialOffset = seed % 32
 	rangeOffset = rangeOffsets[int(seed/32)%len(rangeOffsets)]
@@ -158,6 +166,9 @@ func NewFromBytes(data []byte) (*Filter, error) {
 	if filter.hashCount == 0 {
 		return nil, errs.New("invalid hash count %d", filter.hashCount)
 	}
+	if len(filter.table) == 0 {
+		return nil, errs.New("empty table")
+	}
 
 	filter.offset, filter.rangeOffset = initialConditions(filter.seed)
 	filter.tableSize = fastdiv.NewUint64(uint64(len(filter.table)))
@@ -184,6 +195,61 @@ func (filter *Filter) Bytes() []byte {
 	return bytes
 }
 
+// WriteTo writes the filter in the same encoding as Bytes, without
+// making an intermediate copy of the table.
+func (filter *Filter) WriteTo(w io.Writer) (n int64, err error) {
+	header := [3]byte{version1, filter.seed, filter.hashCount}
+	written, err := w.Write(header[:])
+	n += int64(written)
+	if err != nil {
+		return n, err
+	}
+	written, err = w.Write(filter.table)
+	n += int64(written)
+	return n, err
+}
+
+// NewFromReader decodes the filter from r, which contains the encoding
+// produced by Bytes or WriteTo. Exactly size bytes are read from r and the
+// table is allocated only once.
+//
+// Use CreateFileFilterFromReader, when the size isn't known or the filter
+// shouldn't be kept in memory.
+func NewFromReader(r io.Reader, size int64) (*Filter, error) {
+	if size < 0 {
+		return nil, errs.New("invalid size %d", size)
+	}
+	if size < 3 {
+		return nil, errs.New("not enough data")
+	}
+
+	var header [3]byte
+	if _, err := io.ReadFull(r, header[:]); err != nil {
+		return nil, errs.Wrap(err)
+	}
+	if err := checkHeader(header); err != nil {
+		return nil, err
+	}
+
+	data := make([]byte, size)
+	copy(data, header[:])
+	if _, err := io.ReadFull(r, data[3:]); err != nil {
+		return nil, errs.Wrap(err)
+	}
+	return NewFromBytes(data)
+}
+
+// checkHeader verifies the version and hash count of an encoded Filter.
+func checkHeader(header [3]byte) error {
+	if header[0] != version1 {
+		return errs.New("unsupported version %d", header[0])
+	}
+	if header[2] == 0 {
+		return errs.New("invalid hash count %d", header[2])
+	}
+	return nil
+}
+
 // Size returns the size of Bytes call.
 func (filter *Filter) Size() int64 {
 	// the first three bytes represent the version, seed, and hash count
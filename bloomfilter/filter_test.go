@@ -4,6 +4,7 @@
 package bloomfilter_test
 
 import (
+	"bytes"
 	"flag"
 	"sort"
 	"testing"
@@ -238,3 +239,33 @@ func TestFillRate(t *testing.T) {
 		require.InDelta(t, test.expect, filter.FillRate(), 0.001)
 	}
 }
+
+func TestWriteToAndNewFromReader(t *testing.T) {
+	for _, count := range []int64{0, 100, 1000, 10000} {
+		filter := bloomfilter.NewOptimal(count, 0.1)
+		for _, id := range generateTestIDs(int(count)) {
+			filter.Add(id)
+		}
+
+		var buf bytes.Buffer
+		n, err := filter.WriteTo(&buf)
+		require.NoError(t, err)
+		require.Equal(t, filter.Size(), n)
+		require.Equal(t, filter.Bytes(), buf.Bytes())
+
+		sized, err := bloomfilter.NewFromReader(bytes.NewReader(buf.Bytes()), n)
+		require.NoError(t, err)
+		require.Equal(t, filter, sized)
+
+		// the size is required.
+		_, err = bloomfilter.NewFromReader(bytes.NewReader(buf.Bytes()), -1)
+		require.Error(t, err)
+	}
+
+	_, err := bloomfilter.NewFromReader(bytes.NewReader([]byte{1, 0, 1, 0}), 10)
+	require.Error(t, err)
+	_, err = bloomfilter.NewFromReader(bytes.NewReader([]byte{2, 0, 1, 0}), 4)
+	require.Error(t, err)
+	_, err = bloomfilter.NewFromReader(bytes.NewReader([]byte{1, 0, 1}), 3)
+	require.Error(t, err)
+}
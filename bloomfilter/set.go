@@ -0,0 +1,179 @@
+// Copyright (C) 2026 Storj Labs, Inc.
+// See LICENSE for copying information.
+
+package bloomfilter
+
+import (
+	"encoding/binary"
+	"math"
+
+	"github.com/zeebo/errs"
+
+	"storj.io/common/memory"
+	"storj.io/common/storj"
+)
+
+const (
+	// setVersion1 has a distinct high bit pattern so that the encoding cannot
+	// be confused with the Filter or CountingFilter encodings.
+	setVersion1 = 0x40 | 1
+
+	// MaxShardCount is the maximum number of shards in a FilterSet.
+	MaxShardCount = math.MaxUint16
+)
+
+// FilterSet is a set of independent filters, where the piece ID space
+// is partitioned by prefix into equally sized ranges.
+//
+// A FilterSet allows using more memory than a single Filter permits,
+// and each shard can be transferred separately.
+type FilterSet struct {
+	shards []*Filter
+}
+
+// NewOptimalSet returns a filter set with shardCount shards. Each shard is
+// sized for an equal part of expectedElements and capped at maxShardSize
+// bytes, when maxShardSize is non-zero.
+func NewOptimalSet(shardCount int, expectedElements int64, falsePositiveRate float64, maxShardSize memory.Size) (*FilterSet, error) {
+	if shardCount <= 0 || shardCount > MaxShardCount {
+		return nil, errs.New("invalid shard count %d", shardCount)
+	}
+
+	perShard := (expectedElements + int64(shardCount) - 1) / int64(shardCount)
+	hashCount, sizeInBytes := OptimalParameters(perShard, falsePositiveRate, maxShardSize)
+
+	set := &FilterSet{shards: make([]*Filter, shardCount)}
+	for i := range set.shards {
+		set.shards[i] = NewExplicit(GenerateSeed(), hashCount, sizeInBytes)
+	}
+	return set, nil
+}
+
+// NewSetFromFilters returns a filter set using the specified filters as shards.
+//
+// Note: filters will be referenced inside the set.
+func NewSetFromFilters(shards []*Filter) (*FilterSet, error) {
+	if len(shards) == 0 || len(shards) > MaxShardCount {
+		return nil, errs.New("invalid shard count %d", len(shards))
+	}
+	for i, shard := range shards {
+		if shard == nil {
+			return nil, errs.New("shard %d missing", i)
+		}
+	}
+	return &FilterSet{shards: shards}, nil
+}
+
+// ShardIndex returns the index of the shard that pieceID belongs to,
+// when the piece ID space is partitioned into shardCount shards.
+func ShardIndex(shardCount int, pieceID storj.PieceID) int {
+	prefix := uint64(binary.BigEndian.Uint32(pieceID[:4]))
+	return int((prefix * uint64(shardCount)) >> 32)
+}
+
+// ShardCount returns the number of shards in the set.
+func (set *FilterSet) ShardCount() int {
+	return len(set.shards)
+}
+
+// Shard returns the filter for the shard at index.
+func (set *FilterSet) Shard(index int) *Filter {
+	return set.shards[index]
+}
+
+// Add adds an element to the filter set.
+func (set *FilterSet) Add(pieceID storj.PieceID) {
+	set.shards[ShardIndex(len(set.shards), pieceID)].Add(pieceID)
+}
+
+// Contains return true if pieceID may be in the set.
+func (set *FilterSet) Contains(pieceID storj.PieceID) bool {
+	return set.shards[ShardIndex(len(set.shards), pieceID)].Contains(pieceID)
+}
+
+// AddFilterSet adds the given filter set into the receiver. The sets must
+// have the same number of shards and each shard must have a matching seed
+// and parameters.
+func (set *FilterSet) AddFilterSet(operand *FilterSet) error {
+	if len(set.shards) != len(operand.shards) {
+		return errs.New("cannot merge: mismatched shard count: expected %d but got %d", len(set.shards), len(operand.shards))
+	}
+	for i, shard := range set.shards {
+		if err := shard.AddFilter(operand.shards[i]); err != nil {
+			return errs.New("shard %d: %w", i, err)
+		}
+	}
+	return nil
+}
+
+// FillRates calculates the proportion of bits filled in for each shard.
+func (set *FilterSet) FillRates() []float64 {
+	rates := make([]float64, len(set.shards))
+	for i, shard := range set.shards {
+		rates[i] = shard.FillRate()
+	}
+	return rates
+}
+
+// Bytes encodes the filter set into a sequence of bytes that can be transferred on network.
+func (set *FilterSet) Bytes() []byte {
+	bytes := make([]byte, 0, set.Size())
+	bytes = append(bytes, setVersion1)
+	bytes = binary.BigEndian.AppendUint16(bytes, uint16(len(set.shards)))
+	for _, shard := range set.shards {
+		bytes = binary.AppendUvarint(bytes, uint64(shard.Size()))
+		bytes = append(bytes, shard.Bytes()...)
+	}
+	return bytes
+}
+
+// Size returns the size of Bytes call.
+func (set *FilterSet) Size() int64 {
+	// the first three bytes represent the version and shard count
+	size := int64(1 + 2)
+	var buf [binary.MaxVarintLen64]byte
+	for _, shard := range set.shards {
+		shardSize := shard.Size()
+		size += int64(binary.PutUvarint(buf[:], uint64(shardSize))) + shardSize
+	}
+	return size
+}
+
+// NewSetFromBytes decodes the filter set from a sequence of bytes.
+//
+// Note: data will be referenced inside the shard tables.
+func NewSetFromBytes(data []byte) (*FilterSet, error) {
+	if len(data) < 3 {
+		return nil, errs.New("not enough data")
+	}
+	if data[0] != setVersion1 {
+		return nil, errs.New("unsupported version %d", data[0])
+	}
+
+	shardCount := int(binary.BigEndian.Uint16(data[1:3]))
+	if shardCount == 0 {
+		return nil, errs.New("invalid shard count %d", shardCount)
+	}
+	data = data[3:]
+
+	shards := make([]*Filter, shardCount)
+	for i := range shards {
+		size, n := binary.Uvarint(data)
+		if n <= 0 || size > uint64(len(data)-n) {
+			return nil, errs.New("shard %d: not enough data", i)
+		}
+		data = data[n:]
+
+		shard, err := NewFromBytes(data[:size])
+		if err != nil {
+			return nil, errs.New("shard %d: %w", i, err)
+		}
+		shards[i] = shard
+		data = data[size:]
+	}
+	if len(data) > 0 {
+		return nil, errs.New("unexpected %d trailing bytes", len(data))
+	}
+
+	return &FilterSet{shards: shards}, nil
+}
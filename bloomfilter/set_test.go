@@ -0,0 +1,104 @@
+// Copyright (C) 2026 Storj Labs, Inc.
+// See LICENSE for copying information.
+
+package bloomfilter_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"storj.io/common/bloomfilter"
+	"storj.io/common/memory"
+	"storj.io/common/storj"
+)
+
+func TestFilterSet(t *testing.T) {
+	ids := generateTestIDs(10000)
+
+	set, err := bloomfilter.NewOptimalSet(7, int64(len(ids)), 0.1, 1*memory.KiB)
+	require.NoError(t, err)
+	require.Equal(t, 7, set.ShardCount())
+
+	for _, id := range ids {
+		set.Add(id)
+	}
+	for _, id := range ids {
+		require.True(t, set.Contains(id))
+		require.True(t, set.Shard(bloomfilter.ShardIndex(set.ShardCount(), id)).Contains(id))
+	}
+
+	for _, rate := range set.FillRates() {
+		require.Greater(t, rate, 0.0)
+		require.Less(t, rate, 1.0)
+	}
+
+	bytes := set.Bytes()
+	require.EqualValues(t, len(bytes), set.Size())
+
+	unmarshaled, err := bloomfilter.NewSetFromBytes(bytes)
+	require.NoError(t, err)
+	require.Equal(t, set, unmarshaled)
+}
+
+func TestFilterSet_ShardIndex(t *testing.T) {
+	require.Equal(t, 0, bloomfilter.ShardIndex(4, storj.PieceID{}))
+	require.Equal(t, 1, bloomfilter.ShardIndex(4, storj.PieceID{0x40}))
+	require.Equal(t, 2, bloomfilter.ShardIndex(4, storj.PieceID{0x80}))
+	require.Equal(t, 3, bloomfilter.ShardIndex(4, storj.PieceID{0xFF, 0xFF, 0xFF, 0xFF}))
+}
+
+func TestFilterSet_AddFilterSet(t *testing.T) {
+	ids1 := generateTestIDs(1000)
+	ids2 := generateTestIDs(1000)
+
+	set1, err := bloomfilter.NewOptimalSet(3, 1000, 0.1, 0)
+	require.NoError(t, err)
+
+	shards := make([]*bloomfilter.Filter, set1.ShardCount())
+	for i := range shards {
+		shards[i] = bloomfilter.NewExplicit(set1.Shard(i).SeedAndParameters())
+	}
+	set2, err := bloomfilter.NewSetFromFilters(shards)
+	require.NoError(t, err)
+
+	for _, id := range ids1 {
+		set1.Add(id)
+	}
+	for _, id := range ids2 {
+		set2.Add(id)
+	}
+
+	require.NoError(t, set1.AddFilterSet(set2))
+	for _, id := range ids2 {
+		require.True(t, set1.Contains(id))
+	}
+
+	other, err := bloomfilter.NewOptimalSet(4, 1000, 0.1, 0)
+	require.NoError(t, err)
+	require.EqualError(t, set1.AddFilterSet(other), "cannot merge: mismatched shard count: expected 3 but got 4")
+}
+
+func TestFilterSet_Failing(t *testing.T) {
+	_, err := bloomfilter.NewOptimalSet(0, 1000, 0.1, 0)
+	require.Error(t, err)
+
+	_, err = bloomfilter.NewSetFromFilters(nil)
+	require.Error(t, err)
+
+	valid := bloomfilter.NewExplicit(1, 1, 1).Bytes()
+	failing := [][]byte{
+		{},
+		{0x41, 0},
+		{0x41, 0, 0},
+		{0x41, 0, 1},
+		{0x41, 0, 1, 10},
+		{0x41, 0, 1, 1, 0},
+		append([]byte{1, 0, 1, byte(len(valid))}, valid...),
+		append(append([]byte{0x41, 0, 1, byte(len(valid))}, valid...), 0),
+	}
+	for _, bytes := range failing {
+		_, err := bloomfilter.NewSetFromBytes(bytes)
+		require.Error(t, err)
+	}
+}
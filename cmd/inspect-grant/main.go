@@ -0,0 +1,90 @@
+// Copyright (C) 2026 Storj Labs, Inc.
+// See LICENSE for copying information.
+
+// inspect-grant describes a serialized access grant or API key, or explains
+// the differences between two of them.
+//
+// Usage:
+//
+//	inspect-grant [-json] <access or api key>
+//	inspect-grant [-json] <from access or api key> <to access or api key>
+//
+// Use "-" as an argument to read it from stdin.
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+
+	"storj.io/common/grant/inspect"
+)
+
+func main() {
+	asJSON := flag.Bool("json", false, "print the output as JSON")
+	flag.Usage = func() {
+		fmt.Fprintf(flag.CommandLine.Output(), "usage: %s [-json] <access or api key> [<other access or api key>]\n", os.Args[0])
+		flag.PrintDefaults()
+	}
+	flag.Parse()
+
+	if flag.NArg() < 1 || flag.NArg() > 2 {
+		flag.Usage()
+		os.Exit(2)
+	}
+
+	if err := run(os.Stdout, os.Stdin, *asJSON, flag.Args()); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+}
+
+func run(w io.Writer, stdin io.Reader, asJSON bool, args []string) error {
+	var descs []*inspect.Description
+	for _, arg := range args {
+		if arg == "-" {
+			data, err := io.ReadAll(stdin)
+			if err != nil {
+				return err
+			}
+			arg = string(data)
+		}
+
+		desc, err := inspect.Parse(arg)
+		if err != nil {
+			return err
+		}
+		descs = append(descs, desc)
+	}
+
+	if len(descs) == 1 {
+		if asJSON {
+			return writeJSON(w, descs[0])
+		}
+		_, err := fmt.Fprint(w, descs[0])
+		return err
+	}
+
+	diffs := inspect.Diff(descs[0], descs[1])
+	if asJSON {
+		return writeJSON(w, diffs)
+	}
+	if len(diffs) == 0 {
+		_, err := fmt.Fprintln(w, "no differences")
+		return err
+	}
+	for _, diff := range diffs {
+		if _, err := fmt.Fprintln(w, diff); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func writeJSON(w io.Writer, v any) error {
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(v)
+}
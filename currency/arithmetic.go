@@ -0,0 +1,229 @@
+// Copyright (C) 2026 Storj Labs, Inc.
+// See LICENSE for copying information.
+
+package currency
+
+import (
+	"math/big"
+
+	"github.com/shopspring/decimal"
+	"github.com/zeebo/errs"
+)
+
+// RoundingMode specifies how fractional base units are rounded.
+type RoundingMode int
+
+const (
+	// RoundHalfEven rounds to the nearest base unit, and ties to the
+	// nearest even base unit (a.k.a. banker's rounding).
+	RoundHalfEven RoundingMode = iota
+	// RoundHalfUp rounds to the nearest base unit, and ties away from zero.
+	RoundHalfUp
+	// RoundFloor rounds towards negative infinity.
+	RoundFloor
+	// RoundCeil rounds towards positive infinity.
+	RoundCeil
+)
+
+// String returns the name of the rounding mode.
+func (mode RoundingMode) String() string {
+	switch mode {
+	case RoundHalfEven:
+		return "half-even"
+	case RoundHalfUp:
+		return "half-up"
+	case RoundFloor:
+		return "floor"
+	case RoundCeil:
+		return "ceil"
+	default:
+		return "unknown"
+	}
+}
+
+// round rounds d to an integer using the rounding mode.
+func (mode RoundingMode) round(d decimal.Decimal) (decimal.Decimal, error) {
+	switch mode {
+	case RoundHalfEven:
+		return d.RoundBank(0), nil
+	case RoundHalfUp:
+		return d.Round(0), nil
+	case RoundFloor:
+		return d.Floor(), nil
+	case RoundCeil:
+		return d.Ceil(), nil
+	default:
+		return decimal.Decimal{}, Error.New("unknown rounding mode %d", int(mode))
+	}
+}
+
+// div divides dividend by a non-zero divisor and rounds the result to an
+// integer using the rounding mode.
+func (mode RoundingMode) div(dividend, divisor decimal.Decimal) (decimal.Decimal, error) {
+	quotient, remainder := dividend.QuoRem(divisor, 0)
+	return mode.roundQuotient(quotient, remainder, dividend, divisor)
+}
+
+// roundQuotient rounds the result of an integer division, where quotient
+// and remainder are the result of truncated division of dividend by divisor.
+func (mode RoundingMode) roundQuotient(quotient, remainder, dividend, divisor decimal.Decimal) (decimal.Decimal, error) {
+	if remainder.IsZero() {
+		return quotient, nil
+	}
+
+	negative := dividend.Sign()*divisor.Sign() < 0
+	away := decimal.New(1, 0)
+	if negative {
+		away = away.Neg()
+	}
+
+	switch mode {
+	case RoundHalfEven, RoundHalfUp:
+		c := remainder.Abs().Mul(decimal.New(2, 0)).Cmp(divisor.Abs())
+		odd := quotient.BigInt().Bit(0) == 1
+		if c > 0 || (c == 0 && (mode == RoundHalfUp || odd)) {
+			return quotient.Add(away), nil
+		}
+		return quotient, nil
+	case RoundFloor:
+		if negative {
+			return quotient.Add(away), nil
+		}
+		return quotient, nil
+	case RoundCeil:
+		if !negative {
+			return quotient.Add(away), nil
+		}
+		return quotient, nil
+	default:
+		return decimal.Decimal{}, Error.New("unknown rounding mode %d", int(mode))
+	}
+}
+
+// Sub subtracts the second monetary amount from the first and returns the result.
+// If the currencies are different, an error is thrown.
+func Sub(i, j Amount) (Amount, error) {
+	if !sameCurrency(i.currency, j.currency) {
+		return i.currency.Zero(), errs.New("Amounts to subtract must use the same currency")
+	}
+	return AmountFromBaseUnits(i.baseUnits-j.baseUnits, i.currency), nil
+}
+
+// Cmp compares two monetary amounts and returns -1, 0 or +1 when the first
+// is less than, equal to or greater than the second.
+// If the currencies are different, an error is thrown.
+func Cmp(i, j Amount) (int, error) {
+	if !sameCurrency(i.currency, j.currency) {
+		return 0, errs.New("Amounts to compare must use the same currency")
+	}
+	switch {
+	case i.baseUnits < j.baseUnits:
+		return -1, nil
+	case i.baseUnits > j.baseUnits:
+		return 1, nil
+	default:
+		return 0, nil
+	}
+}
+
+// Neg returns the amount with the opposite sign.
+func (a Amount) Neg() Amount {
+	return AmountFromBaseUnits(-a.baseUnits, a.currency)
+}
+
+// Abs returns the absolute value of the amount.
+func (a Amount) Abs() Amount {
+	if a.baseUnits < 0 {
+		return a.Neg()
+	}
+	return a
+}
+
+// MulDecimal multiplies the amount by d and rounds the result to base units
+// using the rounding mode.
+func MulDecimal(a Amount, d decimal.Decimal, mode RoundingMode) (Amount, error) {
+	if a.currency == nil {
+		return Amount{}, Error.New("amount has no currency")
+	}
+	product := decimal.NewFromInt(a.baseUnits).Mul(d)
+	rounded, err := mode.round(product)
+	if err != nil {
+		return a.currency.Zero(), err
+	}
+	return amountFromIntegerDecimal(rounded, a.currency)
+}
+
+// DivDecimal divides the amount by d and rounds the result to base units
+// using the rounding mode. Division by zero returns an error.
+func DivDecimal(a Amount, d decimal.Decimal, mode RoundingMode) (Amount, error) {
+	if a.currency == nil {
+		return Amount{}, Error.New("amount has no currency")
+	}
+	if d.IsZero() {
+		return a.currency.Zero(), Error.New("division by zero")
+	}
+	rounded, err := mode.div(decimal.NewFromInt(a.baseUnits), d)
+	if err != nil {
+		return a.currency.Zero(), err
+	}
+	return amountFromIntegerDecimal(rounded, a.currency)
+}
+
+// amountFromIntegerDecimal converts an integer number of base units into an
+// Amount, returning an error when it does not fit.
+func amountFromIntegerDecimal(units decimal.Decimal, currency *Currency) (Amount, error) {
+	value := units.BigInt()
+	if !value.IsInt64() {
+		return currency.Zero(), Error.New("amount %s overflows base units", units)
+	}
+	return AmountFromBaseUnits(value.Int64(), currency), nil
+}
+
+// Allocate splits the amount into parts proportional to ratios, such that the
+// parts add up exactly to the amount. Base units that cannot be split evenly are
+// distributed one at a time to the parts, starting from the first one.
+//
+// Ratios must not be negative and at least one of them must be positive.
+func Allocate(a Amount, ratios ...int64) ([]Amount, error) {
+	if a.currency == nil {
+		return nil, Error.New("amount has no currency")
+	}
+
+	total := new(big.Int)
+	for _, ratio := range ratios {
+		if ratio < 0 {
+			return nil, Error.New("negative ratio %d", ratio)
+		}
+		total.Add(total, big.NewInt(ratio))
+	}
+	if total.Sign() == 0 {
+		return nil, Error.New("ratios must add up to a positive value")
+	}
+
+	amount := big.NewInt(a.baseUnits)
+	parts := make([]Amount, len(ratios))
+	remaining := a.baseUnits
+
+	var share big.Int
+	for i, ratio := range ratios {
+		// |amount * ratio / total| <= |amount|, hence it always fits int64
+		share.Mul(amount, big.NewInt(ratio))
+		share.Quo(&share, total)
+		parts[i] = AmountFromBaseUnits(share.Int64(), a.currency)
+		remaining -= share.Int64()
+	}
+
+	unit := int64(1)
+	if remaining < 0 {
+		unit = -1
+	}
+	for i := 0; remaining != 0; i = (i + 1) % len(parts) {
+		if ratios[i] == 0 {
+			continue
+		}
+		parts[i].baseUnits += unit
+		remaining -= unit
+	}
+
+	return parts, nil
+}
@@ -0,0 +1,131 @@
+// Copyright (C) 2026 Storj Labs, Inc.
+// See LICENSE for copying information.
+
+package currency
+
+import (
+	"math"
+	"testing"
+
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSubNegAbsCmp(t *testing.T) {
+	a := AmountFromBaseUnits(150, USDollars)
+	b := AmountFromBaseUnits(400, USDollars)
+
+	diff, err := Sub(a, b)
+	require.NoError(t, err)
+	require.Equal(t, AmountFromBaseUnits(-250, USDollars), diff)
+	require.Equal(t, AmountFromBaseUnits(250, USDollars), diff.Neg())
+	require.Equal(t, AmountFromBaseUnits(250, USDollars), diff.Abs())
+	require.Equal(t, b, b.Abs())
+
+	for _, test := range []struct {
+		i, j   Amount
+		expect int
+	}{
+		{a, b, -1},
+		{b, a, 1},
+		{a, a, 0},
+	} {
+		c, err := Cmp(test.i, test.j)
+		require.NoError(t, err)
+		require.Equal(t, test.expect, c)
+	}
+
+	_, err = Sub(a, AmountFromBaseUnits(1, StorjToken))
+	require.Error(t, err)
+	_, err = Cmp(a, AmountFromBaseUnits(1, StorjToken))
+	require.Error(t, err)
+}
+
+func TestMulDivDecimal(t *testing.T) {
+	tests := []struct {
+		units    int64
+		factor   string
+		mode     RoundingMode
+		mul, div int64
+	}{
+		{units: 5, factor: "0.5", mode: RoundHalfEven, mul: 2, div: 10},
+		{units: 7, factor: "0.5", mode: RoundHalfEven, mul: 4, div: 14},
+		{units: 5, factor: "0.5", mode: RoundHalfUp, mul: 3, div: 10},
+		{units: -5, factor: "0.5", mode: RoundHalfUp, mul: -3, div: -10},
+		{units: 5, factor: "0.5", mode: RoundFloor, mul: 2, div: 10},
+		{units: -5, factor: "0.5", mode: RoundFloor, mul: -3, div: -10},
+		{units: 5, factor: "0.5", mode: RoundCeil, mul: 3, div: 10},
+		{units: -5, factor: "0.5", mode: RoundCeil, mul: -2, div: -10},
+		{units: 10, factor: "3", mode: RoundHalfEven, mul: 30, div: 3},
+		{units: 10, factor: "3", mode: RoundFloor, mul: 30, div: 3},
+		{units: 10, factor: "3", mode: RoundCeil, mul: 30, div: 4},
+		{units: -10, factor: "3", mode: RoundFloor, mul: -30, div: -4},
+		{units: -10, factor: "3", mode: RoundCeil, mul: -30, div: -3},
+		{units: 10, factor: "4", mode: RoundHalfEven, mul: 40, div: 2},
+		{units: 14, factor: "4", mode: RoundHalfEven, mul: 56, div: 4},
+		{units: 10, factor: "4", mode: RoundHalfUp, mul: 40, div: 3},
+		{units: 10, factor: "-4", mode: RoundHalfUp, mul: -40, div: -3},
+	}
+
+	for _, test := range tests {
+		factor := decimal.RequireFromString(test.factor)
+		amount := AmountFromBaseUnits(test.units, USDollars)
+
+		mul, err := MulDecimal(amount, factor, test.mode)
+		require.NoError(t, err)
+		require.Equal(t, test.mul, mul.BaseUnits(), "%d * %s (%s)", test.units, test.factor, test.mode)
+		require.Equal(t, USDollars, mul.Currency())
+
+		div, err := DivDecimal(amount, factor, test.mode)
+		require.NoError(t, err)
+		require.Equal(t, test.div, div.BaseUnits(), "%d / %s (%s)", test.units, test.factor, test.mode)
+		require.Equal(t, USDollars, div.Currency())
+	}
+
+	_, err := DivDecimal(AmountFromBaseUnits(1, USDollars), decimal.Zero, RoundHalfEven)
+	require.Error(t, err)
+
+	_, err = MulDecimal(AmountFromBaseUnits(math.MaxInt64, USDollars), decimal.New(2, 0), RoundHalfEven)
+	require.Error(t, err)
+
+	_, err = MulDecimal(AmountFromBaseUnits(1, USDollars), decimal.New(2, 0), RoundingMode(100))
+	require.Error(t, err)
+}
+
+func TestAllocate(t *testing.T) {
+	tests := []struct {
+		units  int64
+		ratios []int64
+		expect []int64
+	}{
+		{units: 100, ratios: []int64{1, 1, 1}, expect: []int64{34, 33, 33}},
+		{units: 5, ratios: []int64{3, 7}, expect: []int64{2, 3}},
+		{units: -100, ratios: []int64{1, 1, 1}, expect: []int64{-34, -33, -33}},
+		{units: 1, ratios: []int64{0, 1, 1}, expect: []int64{0, 1, 0}},
+		{units: 0, ratios: []int64{1, 2}, expect: []int64{0, 0}},
+		{units: math.MaxInt64, ratios: []int64{math.MaxInt64, math.MaxInt64}, expect: []int64{math.MaxInt64/2 + 1, math.MaxInt64 / 2}},
+	}
+
+	for _, test := range tests {
+		parts, err := Allocate(AmountFromBaseUnits(test.units, StorjToken), test.ratios...)
+		require.NoError(t, err)
+
+		total := StorjToken.Zero()
+		got := make([]int64, len(parts))
+		for i, part := range parts {
+			require.Equal(t, StorjToken, part.Currency())
+			got[i] = part.BaseUnits()
+			total, err = Add(total, part)
+			require.NoError(t, err)
+		}
+		require.Equal(t, test.expect, got)
+		require.Equal(t, test.units, total.BaseUnits())
+	}
+
+	_, err := Allocate(AmountFromBaseUnits(1, StorjToken))
+	require.Error(t, err)
+	_, err = Allocate(AmountFromBaseUnits(1, StorjToken), 0, 0)
+	require.Error(t, err)
+	_, err = Allocate(AmountFromBaseUnits(1, StorjToken), 1, -1)
+	require.Error(t, err)
+}
@@ -0,0 +1,281 @@
+// Copyright (C) 2026 Storj Labs, Inc.
+// See LICENSE for copying information.
+
+package currency
+
+import (
+	"encoding/json"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/shopspring/decimal"
+)
+
+// ExchangeRate is the value of one unit of the From currency expressed in
+// units of the To currency, as of Time.
+//
+// Example:
+//
+//	ExchangeRate{From: StorjToken, To: USDollars, Rate: decimal.RequireFromString("0.25")}
+//
+// means that 1 STORJ is worth 0.25 USD.
+type ExchangeRate struct {
+	From *Currency
+	To   *Currency
+	Rate decimal.Decimal
+	Time time.Time
+}
+
+// Convert converts the amount from the From currency into the To currency,
+// rounding the result to base units of the To currency using the rounding mode.
+func (rate ExchangeRate) Convert(a Amount, mode RoundingMode) (Amount, error) {
+	if err := rate.check(); err != nil {
+		return Amount{}, err
+	}
+	if !sameCurrency(a.currency, rate.From) {
+		return Amount{}, Error.New("amount currency does not match exchange rate")
+	}
+
+	units := decimal.NewFromInt(a.baseUnits).Mul(rate.Rate).Shift(rate.To.decimalPlaces - rate.From.decimalPlaces)
+	rounded, err := mode.round(units)
+	if err != nil {
+		return rate.To.Zero(), err
+	}
+	return amountFromIntegerDecimal(rounded, rate.To)
+}
+
+// ConvertInverse converts the amount from the To currency into the From currency,
+// rounding the result to base units of the From currency using the rounding mode.
+func (rate ExchangeRate) ConvertInverse(a Amount, mode RoundingMode) (Amount, error) {
+	if err := rate.check(); err != nil {
+		return Amount{}, err
+	}
+	if !sameCurrency(a.currency, rate.To) {
+		return Amount{}, Error.New("amount currency does not match exchange rate")
+	}
+
+	units := decimal.NewFromInt(a.baseUnits).Shift(rate.From.decimalPlaces - rate.To.decimalPlaces)
+	rounded, err := mode.div(units, rate.Rate)
+	if err != nil {
+		return rate.From.Zero(), err
+	}
+	return amountFromIntegerDecimal(rounded, rate.From)
+}
+
+// check verifies that the exchange rate can be used for conversion.
+func (rate ExchangeRate) check() error {
+	if rate.From == nil || rate.To == nil {
+		return Error.New("exchange rate is missing currency")
+	}
+	if rate.Rate.Sign() <= 0 {
+		return Error.New("exchange rate must be positive: %s", rate.Rate)
+	}
+	return nil
+}
+
+// exchangeRateJSON is exchange rate json data structure.
+//
+// It extends amountJSON, where value is the rate and currency is the
+// currency the rate is expressed in.
+type exchangeRateJSON struct {
+	amountJSON
+	Base string     `json:"base"`
+	Time *time.Time `json:"time,omitempty"`
+}
+
+// MarshalJSON marshals exchange rate into json.
+func (rate ExchangeRate) MarshalJSON() ([]byte, error) {
+	if err := rate.check(); err != nil {
+		return nil, err
+	}
+
+	rateJSON := exchangeRateJSON{
+		amountJSON: amountJSON{
+			Value:    rate.Rate,
+			Currency: rate.To.symbol,
+		},
+		Base: rate.From.symbol,
+	}
+	if !rate.Time.IsZero() {
+		rateJSON.Time = &rate.Time
+	}
+
+	return json.Marshal(rateJSON)
+}
+
+// UnmarshalJSON unmarshals json bytes into exchange rate.
+func (rate *ExchangeRate) UnmarshalJSON(data []byte) error {
+	var rateJSON exchangeRateJSON
+	if err := json.Unmarshal(data, &rateJSON); err != nil {
+		return err
+	}
+
+	from, err := FromSymbol(rateJSON.Base)
+	if err != nil {
+		return err
+	}
+	to, err := FromSymbol(rateJSON.Currency)
+	if err != nil {
+		return err
+	}
+
+	*rate = ExchangeRate{
+		From: from,
+		To:   to,
+		Rate: rateJSON.Value,
+	}
+	if rateJSON.Time != nil {
+		rate.Time = *rateJSON.Time
+	}
+	return rate.check()
+}
+
+// ConversionPolicy configures how a Converter converts amounts.
+type ConversionPolicy struct {
+	// Rounding is used to round converted amounts to base units of the target currency.
+	Rounding RoundingMode
+	// MaxRateAge rejects rates older than the given duration relative to the
+	// conversion time. Zero means that rates never expire.
+	MaxRateAge time.Duration
+	// AllowInverse allows converting using the inverse of a rate, when there
+	// is no rate for the requested direction.
+	AllowInverse bool
+}
+
+// currencyPair is a key for an exchange rate table.
+type currencyPair struct {
+	from, to *Currency
+}
+
+// Converter converts amounts between currencies using time-stamped exchange rate tables.
+//
+// Converter is safe for concurrent use.
+type Converter struct {
+	policy ConversionPolicy
+
+	mu    sync.RWMutex
+	rates map[currencyPair][]ExchangeRate
+}
+
+// NewConverter creates a new Converter with the specified policy.
+func NewConverter(policy ConversionPolicy) *Converter {
+	return &Converter{
+		policy: policy,
+		rates:  map[currencyPair][]ExchangeRate{},
+	}
+}
+
+// AddRates adds exchange rates to the table. A rate replaces an existing rate
+// for the same currencies and time.
+func (c *Converter) AddRates(rates ...ExchangeRate) error {
+	for _, rate := range rates {
+		if err := rate.check(); err != nil {
+			return err
+		}
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	for _, rate := range rates {
+		pair := currencyPair{from: rate.From, to: rate.To}
+		table := c.rates[pair]
+
+		i := sort.Search(len(table), func(i int) bool {
+			return !table[i].Time.Before(rate.Time)
+		})
+		if i < len(table) && table[i].Time.Equal(rate.Time) {
+			table[i] = rate
+			continue
+		}
+
+		table = append(table, ExchangeRate{})
+		copy(table[i+1:], table[i:])
+		table[i] = rate
+		c.rates[pair] = table
+	}
+	return nil
+}
+
+// Rates returns all exchange rates in the table, ordered by time for each currency pair.
+func (c *Converter) Rates() []ExchangeRate {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	var rates []ExchangeRate
+	for _, table := range c.rates {
+		rates = append(rates, table...)
+	}
+	sort.SliceStable(rates, func(i, k int) bool {
+		if rates[i].From.symbol != rates[k].From.symbol {
+			return rates[i].From.symbol < rates[k].From.symbol
+		}
+		if rates[i].To.symbol != rates[k].To.symbol {
+			return rates[i].To.symbol < rates[k].To.symbol
+		}
+		return rates[i].Time.Before(rates[k].Time)
+	})
+	return rates
+}
+
+// Rate returns the latest exchange rate from one currency to another that was
+// valid at the specified time.
+func (c *Converter) Rate(from, to *Currency, at time.Time) (ExchangeRate, error) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	return c.rate(from, to, at)
+}
+
+func (c *Converter) rate(from, to *Currency, at time.Time) (ExchangeRate, error) {
+	table := c.rates[currencyPair{from: from, to: to}]
+
+	i := sort.Search(len(table), func(i int) bool {
+		return table[i].Time.After(at)
+	})
+	if i == 0 {
+		return ExchangeRate{}, Error.New("no exchange rate from %s to %s at %s", symbolOf(from), symbolOf(to), at)
+	}
+
+	rate := table[i-1]
+	if c.policy.MaxRateAge > 0 && at.Sub(rate.Time) > c.policy.MaxRateAge {
+		return ExchangeRate{}, Error.New("exchange rate from %s to %s at %s is too old", symbolOf(from), symbolOf(to), rate.Time)
+	}
+	return rate, nil
+}
+
+// Convert converts the amount into the target currency using the rate valid at the specified time.
+func (c *Converter) Convert(a Amount, to *Currency, at time.Time) (Amount, error) {
+	if a.currency == nil || to == nil {
+		return Amount{}, Error.New("missing currency")
+	}
+	if a.currency == to {
+		return a, nil
+	}
+
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	rate, err := c.rate(a.currency, to, at)
+	if err == nil {
+		return rate.Convert(a, c.policy.Rounding)
+	}
+	if !c.policy.AllowInverse {
+		return Amount{}, err
+	}
+
+	inverse, inverseErr := c.rate(to, a.currency, at)
+	if inverseErr != nil {
+		return Amount{}, err
+	}
+	return inverse.ConvertInverse(a, c.policy.Rounding)
+}
+
+// symbolOf returns the currency symbol, or a placeholder for a missing currency.
+func symbolOf(c *Currency) string {
+	if c == nil {
+		return "<nil>"
+	}
+	return c.symbol
+}
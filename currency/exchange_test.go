@@ -0,0 +1,136 @@
+// Copyright (C) 2026 Storj Labs, Inc.
+// See LICENSE for copying information.
+
+package currency
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/require"
+)
+
+func TestExchangeRate_Convert(t *testing.T) {
+	rate := ExchangeRate{
+		From: StorjToken,
+		To:   USDollarsMicro,
+		Rate: decimal.RequireFromString("0.333333"),
+	}
+
+	// 3 STORJ
+	usd, err := rate.Convert(AmountFromBaseUnits(300000000, StorjToken), RoundHalfEven)
+	require.NoError(t, err)
+	require.Equal(t, AmountFromBaseUnits(999999, USDollarsMicro), usd)
+
+	// 0.00000001 STORJ is 0.00000000333333 USD
+	usd, err = rate.Convert(AmountFromBaseUnits(1, StorjToken), RoundFloor)
+	require.NoError(t, err)
+	require.Equal(t, AmountFromBaseUnits(0, USDollarsMicro), usd)
+	usd, err = rate.Convert(AmountFromBaseUnits(1, StorjToken), RoundCeil)
+	require.NoError(t, err)
+	require.Equal(t, AmountFromBaseUnits(1, USDollarsMicro), usd)
+
+	storj, err := rate.ConvertInverse(AmountFromBaseUnits(999999, USDollarsMicro), RoundHalfEven)
+	require.NoError(t, err)
+	require.Equal(t, AmountFromBaseUnits(300000000, StorjToken), storj)
+
+	_, err = rate.Convert(AmountFromBaseUnits(1, USDollars), RoundHalfEven)
+	require.Error(t, err)
+	_, err = rate.ConvertInverse(AmountFromBaseUnits(1, StorjToken), RoundHalfEven)
+	require.Error(t, err)
+	_, err = ExchangeRate{From: StorjToken, To: USDollars}.Convert(AmountFromBaseUnits(1, StorjToken), RoundHalfEven)
+	require.Error(t, err)
+}
+
+func TestExchangeRate_JSON(t *testing.T) {
+	rate := ExchangeRate{
+		From: StorjToken,
+		To:   USDollars,
+		Rate: decimal.RequireFromString("0.5"),
+		Time: time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC),
+	}
+
+	data, err := json.Marshal(rate)
+	require.NoError(t, err)
+	require.JSONEq(t, `{"value":"0.5","currency":"USD","base":"STORJ","time":"2026-01-02T03:04:05Z"}`, string(data))
+
+	var decoded ExchangeRate
+	require.NoError(t, json.Unmarshal(data, &decoded))
+	require.Equal(t, rate.From, decoded.From)
+	require.Equal(t, rate.To, decoded.To)
+	require.True(t, rate.Rate.Equal(decoded.Rate))
+	require.True(t, rate.Time.Equal(decoded.Time))
+
+	rate.Time = time.Time{}
+	data, err = json.Marshal(rate)
+	require.NoError(t, err)
+	require.JSONEq(t, `{"value":"0.5","currency":"USD","base":"STORJ"}`, string(data))
+
+	for _, invalid := range []string{
+		`{"value":"0.5","currency":"USD","base":"XXX"}`,
+		`{"value":"0.5","currency":"XXX","base":"STORJ"}`,
+		`{"value":"-1","currency":"USD","base":"STORJ"}`,
+	} {
+		require.Error(t, json.Unmarshal([]byte(invalid), &decoded))
+	}
+}
+
+func TestConverter(t *testing.T) {
+	t0 := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	t1 := t0.Add(time.Hour)
+
+	converter := NewConverter(ConversionPolicy{
+		Rounding:     RoundHalfUp,
+		MaxRateAge:   2 * time.Hour,
+		AllowInverse: true,
+	})
+	require.NoError(t, converter.AddRates(
+		ExchangeRate{From: StorjToken, To: USDollars, Rate: decimal.RequireFromString("0.5"), Time: t1},
+		ExchangeRate{From: StorjToken, To: USDollars, Rate: decimal.RequireFromString("0.25"), Time: t0},
+	))
+	require.Len(t, converter.Rates(), 2)
+	require.Equal(t, t0, converter.Rates()[0].Time)
+
+	tokens := AmountFromBaseUnits(1000000000, StorjToken) // 10 STORJ
+
+	_, err := converter.Convert(tokens, USDollars, t0.Add(-time.Second))
+	require.Error(t, err)
+
+	usd, err := converter.Convert(tokens, USDollars, t0)
+	require.NoError(t, err)
+	require.Equal(t, AmountFromBaseUnits(250, USDollars), usd)
+
+	usd, err = converter.Convert(tokens, USDollars, t1.Add(time.Minute))
+	require.NoError(t, err)
+	require.Equal(t, AmountFromBaseUnits(500, USDollars), usd)
+
+	_, err = converter.Convert(tokens, USDollars, t1.Add(3*time.Hour))
+	require.Error(t, err)
+
+	back, err := converter.Convert(AmountFromBaseUnits(500, USDollars), StorjToken, t1)
+	require.NoError(t, err)
+	require.Equal(t, tokens, back)
+
+	same, err := converter.Convert(tokens, StorjToken, t1)
+	require.NoError(t, err)
+	require.Equal(t, tokens, same)
+
+	_, err = converter.Convert(tokens, Bitcoin, t1)
+	require.Error(t, err)
+
+	// replacing a rate for the same time
+	require.NoError(t, converter.AddRates(ExchangeRate{From: StorjToken, To: USDollars, Rate: decimal.RequireFromString("1"), Time: t1}))
+	require.Len(t, converter.Rates(), 2)
+	usd, err = converter.Convert(tokens, USDollars, t1)
+	require.NoError(t, err)
+	require.Equal(t, AmountFromBaseUnits(1000, USDollars), usd)
+
+	strict := NewConverter(ConversionPolicy{})
+	require.NoError(t, strict.AddRates(converter.Rates()...))
+	_, err = strict.Convert(AmountFromBaseUnits(500, USDollars), StorjToken, t1)
+	require.Error(t, err)
+
+	require.Error(t, strict.AddRates(ExchangeRate{From: StorjToken, To: USDollars}))
+}
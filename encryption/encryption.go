@@ -44,8 +44,12 @@ func Encrypt(data []byte, cipher storj.CipherSuite, key *storj.Key, nonce *storj
 		return EncryptAESGCM(data, key, ToAESGCMNonce(nonce))
 	case storj.EncSecretBox:
 		return EncryptSecretBox(data, key, nonce)
+	case storj.EncXChaCha20Poly1305Stream:
+		return EncryptXChaCha20Poly1305(data, key, nonce)
 	case storj.EncNullBase64URL:
 		return nil, ErrInvalidConfig.New("base64 encoding not supported for this operation")
+	case storj.EncPathOrderPreserving:
+		return nil, ErrInvalidConfig.New("order preserving encryption is only supported for paths")
 	default:
 		return nil, ErrInvalidConfig.New("encryption type %d is not supported", cipher)
 	}
@@ -65,8 +69,12 @@ func Decrypt(cipherData []byte, cipher storj.CipherSuite, key *storj.Key, nonce
 		return DecryptAESGCM(cipherData, key, ToAESGCMNonce(nonce))
 	case storj.EncSecretBox:
 		return DecryptSecretBox(cipherData, key, nonce)
+	case storj.EncXChaCha20Poly1305Stream:
+		return DecryptXChaCha20Poly1305(cipherData, key, nonce)
 	case storj.EncNullBase64URL:
 		return nil, ErrInvalidConfig.New("base64 encoding not supported for this operation")
+	case storj.EncPathOrderPreserving:
+		return nil, ErrInvalidConfig.New("order preserving encryption is only supported for paths")
 	default:
 		return nil, ErrInvalidConfig.New("encryption type %d is not supported", cipher)
 	}
@@ -81,8 +89,12 @@ func NewEncrypter(cipher storj.CipherSuite, key *storj.Key, startingNonce *storj
 		return NewAESGCMEncrypter(key, ToAESGCMNonce(startingNonce), encryptedBlockSize)
 	case storj.EncSecretBox:
 		return NewSecretboxEncrypter(key, startingNonce, encryptedBlockSize)
+	case storj.EncXChaCha20Poly1305Stream:
+		return NewXChaCha20Poly1305StreamEncrypter(key, startingNonce, encryptedBlockSize)
 	case storj.EncNullBase64URL:
 		return nil, ErrInvalidConfig.New("base64 encoding not supported for this operation")
+	case storj.EncPathOrderPreserving:
+		return nil, ErrInvalidConfig.New("order preserving encryption is only supported for paths")
 	default:
 		return nil, ErrInvalidConfig.New("encryption type %d is not supported", cipher)
 	}
@@ -97,8 +109,12 @@ func NewDecrypter(cipher storj.CipherSuite, key *storj.Key, startingNonce *storj
 		return NewAESGCMDecrypter(key, ToAESGCMNonce(startingNonce), encryptedBlockSize)
 	case storj.EncSecretBox:
 		return NewSecretboxDecrypter(key, startingNonce, encryptedBlockSize)
+	case storj.EncXChaCha20Poly1305Stream:
+		return NewXChaCha20Poly1305StreamDecrypter(key, startingNonce, encryptedBlockSize)
 	case storj.EncNullBase64URL:
 		return nil, ErrInvalidConfig.New("base64 encoding not supported for this operation")
+	case storj.EncPathOrderPreserving:
+		return nil, ErrInvalidConfig.New("order preserving encryption is only supported for paths")
 	default:
 		return nil, ErrInvalidConfig.New("encryption type %d is not supported", cipher)
 	}
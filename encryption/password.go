@@ -6,6 +6,7 @@ package encryption
 import (
 	"crypto/hmac"
 	"crypto/sha256"
+	"math"
 
 	"github.com/zeebo/errs"
 	"golang.org/x/crypto/argon2"
@@ -23,12 +24,99 @@ func sha256hmac(key, data []byte) ([]byte, error) {
 	return h.Sum(nil), nil
 }
 
+// KDFAlgorithm is the password hashing algorithm used for deriving root keys.
+type KDFAlgorithm byte
+
+const (
+	// KDFUnspecified indicates no algorithm has been selected.
+	KDFUnspecified = KDFAlgorithm(iota)
+	// KDFArgon2id indicates use of Argon2id.
+	KDFArgon2id
+)
+
+// SaltDerivation specifies how the salt for the password hashing is derived
+// from the password, the salt from the user and the path.
+type SaltDerivation byte
+
+const (
+	// SaltUnspecified indicates no salt derivation has been selected.
+	SaltUnspecified = SaltDerivation(iota)
+	// SaltHMACSHA256 derives the salt as HMAC-SHA256(HMAC-SHA256(password, salt), path).
+	SaltHMACSHA256
+)
+
+// KDFVersion1 is the current version of the key derivation parameters.
+const KDFVersion1 = 1
+
+// KDFParameters configures how a root key is derived from a password.
+//
+// The parameters must be stored along with anything that needs to derive the
+// same key again, because changing any of them changes the derived key.
+type KDFParameters struct {
+	Version        uint32
+	Algorithm      KDFAlgorithm
+	Memory         memory.Size
+	Iterations     uint32
+	Parallelism    uint8
+	SaltDerivation SaltDerivation
+}
+
+// LegacyKDFParameters returns the parameters used by DeriveRootKey.
+func LegacyKDFParameters(argon2Threads uint8) KDFParameters {
+	// use a time of 1, 64MB of ram, and all of the cores.
+	return KDFParameters{
+		Version:        KDFVersion1,
+		Algorithm:      KDFArgon2id,
+		Memory:         64 * memory.MiB,
+		Iterations:     1,
+		Parallelism:    argon2Threads,
+		SaltDerivation: SaltHMACSHA256,
+	}
+}
+
+// Validate checks whether the parameters can be used for deriving keys.
+func (params KDFParameters) Validate() error {
+	if params.Version != KDFVersion1 {
+		return ErrInvalidConfig.New("unsupported kdf version %d", params.Version)
+	}
+	if params.Algorithm != KDFArgon2id {
+		return ErrInvalidConfig.New("unsupported kdf algorithm %d", params.Algorithm)
+	}
+	if params.SaltDerivation != SaltHMACSHA256 {
+		return ErrInvalidConfig.New("unsupported salt derivation %d", params.SaltDerivation)
+	}
+	if params.Iterations == 0 {
+		return ErrInvalidConfig.New("kdf iterations must be positive")
+	}
+	if params.Parallelism == 0 {
+		return ErrInvalidConfig.New("kdf parallelism must be positive")
+	}
+	if params.Memory%memory.KiB != 0 || params.Memory/memory.KiB > math.MaxUint32 {
+		return ErrInvalidConfig.New("invalid kdf memory %v", params.Memory)
+	}
+	// argon2 uses at least 8KiB of memory per lane.
+	if params.Memory < memory.Size(params.Parallelism)*8*memory.KiB {
+		return ErrInvalidConfig.New("kdf memory %v too small for parallelism %d", params.Memory, params.Parallelism)
+	}
+	return nil
+}
+
 // DeriveRootKey derives a root key for some path using the salt for the bucket and
 // a password from the user. See the password key derivation design doc.
 func DeriveRootKey(password, salt []byte, path storj.Path, argon2Threads uint8) (*storj.Key, error) {
+	return DeriveRootKeyWithParameters(password, salt, path, LegacyKDFParameters(argon2Threads))
+}
+
+// DeriveRootKeyWithParameters derives a root key like DeriveRootKey, using
+// the specified key derivation parameters.
+func DeriveRootKeyWithParameters(password, salt []byte, path storj.Path, params KDFParameters) (*storj.Key, error) {
 	race2.ReadSlice(password)
 	race2.ReadSlice(salt)
 
+	if err := params.Validate(); err != nil {
+		return nil, err
+	}
+
 	mixedSalt, err := sha256hmac(password, salt)
 	if err != nil {
 		return nil, err
@@ -39,8 +127,7 @@ func DeriveRootKey(password, salt []byte, path storj.Path, argon2Threads uint8)
 		return nil, err
 	}
 
-	// use a time of 1, 64MB of ram, and all of the cores.
-	keyData := argon2.IDKey(password, pathSalt, 1, uint32(64*memory.MiB/memory.KiB), argon2Threads, 32)
+	keyData := argon2.IDKey(password, pathSalt, params.Iterations, uint32(params.Memory/memory.KiB), params.Parallelism, 32)
 	if len(keyData) != len(storj.Key{}) {
 		return nil, errs.New("invalid output from argon2id")
 	}
@@ -7,6 +7,9 @@ import (
 	"testing"
 
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"storj.io/common/memory"
 )
 
 func TestDeriveRootKey(t *testing.T) {
@@ -16,3 +19,49 @@ func TestDeriveRootKey(t *testing.T) {
 	_, err = DeriveRootKey([]byte("password"), []byte("salt"), "any/path", 8)
 	assert.NoError(t, err)
 }
+
+func TestDeriveRootKeyWithParameters(t *testing.T) {
+	password, salt := []byte("password"), []byte("salt")
+
+	legacy, err := DeriveRootKey(password, salt, "path", 2)
+	require.NoError(t, err)
+	derived, err := DeriveRootKeyWithParameters(password, salt, "path", LegacyKDFParameters(2))
+	require.NoError(t, err)
+	require.Equal(t, legacy, derived)
+
+	params := KDFParameters{
+		Version:        KDFVersion1,
+		Algorithm:      KDFArgon2id,
+		Memory:         memory.MiB,
+		Iterations:     2,
+		Parallelism:    1,
+		SaltDerivation: SaltHMACSHA256,
+	}
+	first, err := DeriveRootKeyWithParameters(password, salt, "path", params)
+	require.NoError(t, err)
+	second, err := DeriveRootKeyWithParameters(password, salt, "path", params)
+	require.NoError(t, err)
+	require.Equal(t, first, second)
+	require.NotEqual(t, legacy, first)
+
+	params.Iterations = 3
+	third, err := DeriveRootKeyWithParameters(password, salt, "path", params)
+	require.NoError(t, err)
+	require.NotEqual(t, first, third)
+
+	for _, invalid := range []func(p *KDFParameters){
+		func(p *KDFParameters) { p.Version = 2 },
+		func(p *KDFParameters) { p.Algorithm = KDFUnspecified },
+		func(p *KDFParameters) { p.SaltDerivation = SaltUnspecified },
+		func(p *KDFParameters) { p.Iterations = 0 },
+		func(p *KDFParameters) { p.Parallelism = 0 },
+		func(p *KDFParameters) { p.Memory = memory.MiB + 1 },
+		func(p *KDFParameters) { p.Memory = 8 * memory.KiB; p.Parallelism = 2 },
+	} {
+		p := params
+		invalid(&p)
+		require.Error(t, p.Validate())
+		_, err := DeriveRootKeyWithParameters(password, salt, "path", p)
+		require.True(t, ErrInvalidConfig.Has(err))
+	}
+}
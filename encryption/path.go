@@ -86,6 +86,41 @@ func EncryptPath(bucket string, path paths.Unencrypted, pathCipher storj.CipherS
 	return encryptPath(bucket, path, &pathCipher, store)
 }
 
+// EncryptedPathVersion is a path encrypted with a version of the key.
+type EncryptedPathVersion struct {
+	Version uint32
+	Path    paths.Encrypted
+}
+
+// EncryptPathVersionsWithStoreCipher encrypts the path with every version of the key,
+// starting with the active one, looking up keys and the cipher from the provided
+// store and bucket. The previous versions address objects, which were written
+// before a key rotation.
+func EncryptPathVersionsWithStoreCipher(bucket string, path paths.Unencrypted, store *Store) (
+	encPaths []EncryptedPathVersion, err error) {
+
+	// Invalid paths map to invalid paths
+	if !path.Valid() {
+		return []EncryptedPathVersion{{}}, nil
+	}
+
+	_, remaining, base := store.LookupUnencrypted(bucket, path)
+	if base == nil {
+		return nil, ErrMissingEncryptionBase.New("%q/%q", bucket, path)
+	}
+
+	candidates := base.CandidateKeys()
+	encPaths = make([]EncryptedPathVersion, 0, len(candidates))
+	for _, candidate := range candidates {
+		encPath, err := encryptPathWithKey(bucket, remaining, base, base.PathCipher, &candidate.Key)
+		if err != nil {
+			return nil, err
+		}
+		encPaths = append(encPaths, EncryptedPathVersion{Version: candidate.Version, Path: encPath})
+	}
+	return encPaths, nil
+}
+
 func encryptPath(bucket string, path paths.Unencrypted, pathCipher *storj.CipherSuite, store *Store) (
 	encPath paths.Encrypted, err error) {
 
@@ -102,10 +137,21 @@ func encryptPath(bucket string, path paths.Unencrypted, pathCipher *storj.Cipher
 	if pathCipher == nil {
 		pathCipher = &base.PathCipher
 	}
+	return encryptPathWithKey(bucket, remaining, base, *pathCipher, &base.Key)
+}
+
+// encryptPathWithKey encrypts the remaining path components with a version of
+// the base key.
+func encryptPathWithKey(bucket string, remaining paths.Iterator, base *Base, pathCipher storj.CipherSuite, baseKey *storj.Key) (
+	encPath paths.Encrypted, err error) {
+
+	if base.Default && pathCipher == storj.EncPathOrderPreserving {
+		return paths.Encrypted{}, errOrderPreservingOptIn
+	}
 
 	// if we're using the default base (meaning the default key), we need
 	// to include the bucket name in the path derivation.
-	key := &base.Key
+	key := baseKey
 	if base.Default {
 		key, err = derivePathKeyComponent(key, bucket)
 		if err != nil {
@@ -113,7 +159,7 @@ func encryptPath(bucket string, path paths.Unencrypted, pathCipher *storj.Cipher
 		}
 	}
 
-	encrypted, err := EncryptIterator(remaining, *pathCipher, key)
+	encrypted, err := EncryptIterator(remaining, pathCipher, key)
 	if err != nil {
 		return paths.Encrypted{}, errs.Wrap(err)
 	}
@@ -186,20 +232,37 @@ func decryptPath(bucket string, path paths.Encrypted, pathCipher *storj.CipherSu
 	if pathCipher == nil {
 		pathCipher = &base.PathCipher
 	}
+	if base.Default && *pathCipher == storj.EncPathOrderPreserving {
+		return paths.Unencrypted{}, errOrderPreservingOptIn
+	}
 
-	// if we're using the default base (meaning the default key), we need
-	// to include the bucket name in the path derivation.
-	key := &base.Key
-	if base.Default {
-		key, err = derivePathKeyComponent(key, bucket)
-		if err != nil {
-			return paths.Unencrypted{}, errs.Wrap(err)
-		}
+	// try every version of the key, because the path may have been encrypted
+	// before the key was rotated. order preserving encryption isn't
+	// authenticated, so a wrong key may decrypt a path to garbage.
+	candidates := base.CandidateKeys()
+	if *pathCipher == storj.EncPathOrderPreserving && len(candidates) > 1 {
+		return paths.Unencrypted{}, errOrderPreservingKeyVersions
 	}
 
-	decrypted, err := DecryptIterator(remaining, *pathCipher, key)
-	if err != nil {
-		return paths.Unencrypted{}, errs.Wrap(err)
+	var decrypted string
+	for i, candidate := range candidates {
+		// if we're using the default base (meaning the default key), we need
+		// to include the bucket name in the path derivation.
+		key := &candidate.Key
+		if base.Default {
+			key, err = derivePathKeyComponent(key, bucket)
+			if err != nil {
+				return paths.Unencrypted{}, errs.Wrap(err)
+			}
+		}
+
+		decrypted, err = DecryptIterator(remaining, *pathCipher, key)
+		if err == nil {
+			break
+		}
+		if i == len(base.PreviousKeys) {
+			return paths.Unencrypted{}, errs.Wrap(err)
+		}
 	}
 
 	var pb pathBuilder
@@ -256,24 +319,53 @@ func DerivePathKey(bucket string, path paths.Unencrypted, store *Store) (key *st
 	if base == nil {
 		return nil, ErrMissingEncryptionBase.New("%q/%q", bucket, path)
 	}
+	return derivePathKeyFromBase(bucket, remaining, base, &base.Key)
+}
 
-	// If asking for the key at the bucket, do that and return.
-	if !path.Valid() {
-		// if we're using the default base (meaning the default key), we need
-		// to include the bucket name in the path derivation.
-		key = &base.Key
-		if base.Default {
-			key, err = derivePathKeyComponent(&base.Key, bucket)
-			if err != nil {
-				return nil, errs.Wrap(err)
-			}
+// DerivePathKeyVersions returns the path keys derived from every version of
+// the appropriate base key, starting with the active one. The previous versions
+// can only be used for decrypting data written before a key rotation.
+func DerivePathKeyVersions(bucket string, path paths.Unencrypted, store *Store) (keys []KeyVersion, err error) {
+	_, remaining, base := store.LookupUnencrypted(bucket, path)
+	if base == nil {
+		return nil, ErrMissingEncryptionBase.New("%q/%q", bucket, path)
+	}
+
+	candidates := base.CandidateKeys()
+	keys = make([]KeyVersion, 0, len(candidates))
+	for _, candidate := range candidates {
+		key, err := derivePathKeyFromBase(bucket, remaining, base, &candidate.Key)
+		if err != nil {
+			return nil, err
 		}
-		return key, nil
+		keys = append(keys, KeyVersion{Version: candidate.Version, Key: *key})
 	}
+	return keys, nil
+}
 
+// DeriveContentKeyVersions returns the content keys derived from every version
+// of the appropriate base key, starting with the active one.
+func DeriveContentKeyVersions(bucket string, path paths.Unencrypted, store *Store) (keys []KeyVersion, err error) {
+	keys, err = DerivePathKeyVersions(bucket, path, store)
+	if err != nil {
+		return nil, errs.Wrap(err)
+	}
+	for i := range keys {
+		key, err := DeriveKey(&keys[i].Key, "content")
+		if err != nil {
+			return nil, errs.Wrap(err)
+		}
+		keys[i].Key = *key
+	}
+	return keys, nil
+}
+
+// derivePathKeyFromBase derives the path key from a version of the base key
+// and the remaining path components.
+func derivePathKeyFromBase(bucket string, remaining paths.Iterator, base *Base, baseKey *storj.Key) (key *storj.Key, err error) {
 	// if we're using the default base (meaning the default key), we need
 	// to include the bucket name in the path derivation.
-	key = &base.Key
+	key = baseKey
 	if base.Default {
 		key, err = derivePathKeyComponent(key, bucket)
 		if err != nil {
@@ -311,6 +403,10 @@ func encryptPathComponent(comp string, cipher storj.CipherSuite, key *storj.Key)
 		return string(decoded), nil
 	}
 
+	if cipher == storj.EncPathOrderPreserving {
+		return encryptOrderPreserving(comp, key), nil
+	}
+
 	// derive the key for the next path component. this is so that
 	// every encrypted component has a unique nonce.
 	derivedKey, err := derivePathKeyComponent(key, comp)
@@ -354,6 +450,10 @@ func decryptPathComponent(comp string, cipher storj.CipherSuite, key *storj.Key)
 		return base64.URLEncoding.EncodeToString([]byte(comp)), nil
 	}
 
+	if cipher == storj.EncPathOrderPreserving {
+		return decryptOrderPreserving(comp, key)
+	}
+
 	data, err := decodeSegment([]byte(comp))
 	if err != nil {
 		return "", Error.Wrap(err)
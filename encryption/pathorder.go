@@ -0,0 +1,130 @@
+// Copyright (C) 2026 Storj Labs, Inc.
+// See LICENSE for copying information.
+
+package encryption
+
+import (
+	"encoding/binary"
+
+	"storj.io/common/internal/hmacsha512"
+	"storj.io/common/storj"
+)
+
+// orderPreservingByteSize is the size of an encrypted byte when using
+// storj.EncPathOrderPreserving.
+const orderPreservingByteSize = 2
+
+// encryptOrderPreserving encrypts a path component such that the byte-wise
+// lexicographic order of encrypted components matches the order of the
+// unencrypted components.
+//
+// Every byte is mapped to two bytes with a strictly increasing function, which
+// is derived from the key and all of the preceding bytes. Hence components with
+// a common prefix have encryptions with a common prefix, and the first differing
+// byte decides the order of both. The encryption is deterministic and reveals
+// the order and the length of common prefixes of components encrypted with the
+// same key.
+//
+// The encoding of the component preserves the order, so encrypted components
+// can be compared as they are stored.
+func encryptOrderPreserving(comp string, key *storj.Key) string {
+	state := orderPreservingState(key)
+
+	data := make([]byte, 0, len(comp)*orderPreservingByteSize)
+	for i := 0; i < len(comp); i++ {
+		table := orderPreservingTable(&state)
+		data = binary.BigEndian.AppendUint16(data, table[comp[i]])
+		state = orderPreservingNext(&state, comp[i])
+	}
+
+	return string(encodeSegment(data))
+}
+
+// decryptOrderPreserving decrypts a path component encrypted with
+// encryptOrderPreserving.
+//
+// The encryption isn't authenticated. Decrypting with the wrong key only fails
+// when some value isn't in the table, yet every value is in it with a
+// probability of about 1/128, so short components may decrypt to garbage.
+func decryptOrderPreserving(comp string, key *storj.Key) (string, error) {
+	data, err := decodeSegment([]byte(comp))
+	if err != nil {
+		return "", Error.Wrap(err)
+	}
+	if len(data)%orderPreservingByteSize != 0 {
+		return "", ErrDecryptFailed.New("invalid order preserving component length")
+	}
+
+	state := orderPreservingState(key)
+
+	decrypted := make([]byte, 0, len(data)/orderPreservingByteSize)
+	for i := 0; i < len(data); i += orderPreservingByteSize {
+		table := orderPreservingTable(&state)
+		value := binary.BigEndian.Uint16(data[i:])
+
+		b, ok := orderPreservingInverse(&table, value)
+		if !ok {
+			return "", ErrDecryptFailed.New("invalid order preserving component")
+		}
+		decrypted = append(decrypted, b)
+		state = orderPreservingNext(&state, b)
+	}
+
+	return string(decrypted), nil
+}
+
+// orderPreservingState returns the state for the first byte of a component.
+func orderPreservingState(key *storj.Key) (state storj.Key) {
+	mac := hmacsha512.New(key[:])
+	mac.Write([]byte("order-preserving"))
+	sum := mac.SumAndReset()
+	copy(state[:], sum[:])
+	return state
+}
+
+// orderPreservingNext returns the state for the byte following b.
+func orderPreservingNext(state *storj.Key, b byte) (next storj.Key) {
+	mac := hmacsha512.New(state[:])
+	mac.Write([]byte{'n', b})
+	sum := mac.SumAndReset()
+	copy(next[:], sum[:])
+	return next
+}
+
+// orderPreservingTable returns the strictly increasing mapping of bytes to
+// 16-bit values for the state. Every step of the mapping is a pseudorandom
+// value between 1 and 256, hence the largest value fits into 16 bits.
+func orderPreservingTable(state *storj.Key) (table [256]uint16) {
+	mac := hmacsha512.New(state[:])
+
+	var steps [256]byte
+	for i := 0; i < len(steps); i += hmacsha512.Size {
+		mac.Write([]byte{'t', byte(i / hmacsha512.Size)})
+		sum := mac.SumAndReset()
+		copy(steps[i:], sum[:])
+	}
+
+	value := -1
+	for i, step := range steps {
+		value += 1 + int(step)
+		table[i] = uint16(value)
+	}
+	return table
+}
+
+// orderPreservingInverse finds the byte that the table maps to value.
+func orderPreservingInverse(table *[256]uint16, value uint16) (byte, bool) {
+	lo, hi := 0, len(table)
+	for lo < hi {
+		mid := (lo + hi) / 2
+		if table[mid] < value {
+			lo = mid + 1
+		} else {
+			hi = mid
+		}
+	}
+	if lo == len(table) || table[lo] != value {
+		return 0, false
+	}
+	return byte(lo), true
+}
@@ -0,0 +1,123 @@
+// Copyright (C) 2026 Storj Labs, Inc.
+// See LICENSE for copying information.
+
+package encryption
+
+import (
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"storj.io/common/paths"
+	"storj.io/common/storj"
+	"storj.io/common/testrand"
+)
+
+func TestOrderPreservingComponent(t *testing.T) {
+	key := testrand.Key()
+
+	components := []string{"", "\x00", "\x01", "-", ".", "/", "a", "a\x00", "ab", "abc", "abd", "b", "\xfe", "\xff", "\xff\xff"}
+	for i := 0; i < 100; i++ {
+		components = append(components, string(testrand.BytesInt(testrand.Intn(10))))
+	}
+	slices.Sort(components)
+	components = slices.Compact(components)
+
+	encrypted := make([]string, len(components))
+	for i, comp := range components {
+		encrypted[i] = encryptOrderPreserving(comp, &key)
+		require.NotContains(t, encrypted[i], "/")
+
+		decrypted, err := decryptOrderPreserving(encrypted[i], &key)
+		require.NoError(t, err)
+		require.Equal(t, comp, decrypted)
+
+		// encryption is deterministic.
+		require.Equal(t, encrypted[i], encryptOrderPreserving(comp, &key))
+	}
+	require.True(t, slices.IsSorted(encrypted), "encrypted components are not sorted")
+	require.Len(t, slices.Compact(slices.Clone(encrypted)), len(encrypted))
+
+	// prefixes of components encrypt to prefixes.
+	require.True(t, strings.HasPrefix(encryptOrderPreserving("abcdef", &key), encryptOrderPreserving("abc", &key)))
+
+	// other keys can't decrypt.
+	otherKey := testrand.Key()
+	_, err := decryptOrderPreserving(encryptOrderPreserving("some longer component", &key), &otherKey)
+	require.Error(t, err)
+}
+
+func TestStoreOrderPreserving(t *testing.T) {
+	bucket := "bucket"
+	defaultKey := testrand.Key()
+
+	store := NewStore()
+	store.SetDefaultKey(&defaultKey)
+	store.SetDefaultPathCipher(storj.EncAESGCM)
+	require.NoError(t, store.AddWithCipher(bucket, paths.NewUnencrypted("sorted"), paths.NewEncrypted("sorted"), testrand.Key(), storj.EncPathOrderPreserving))
+
+	var encrypted []string
+	for _, name := range []string{"sorted/a", "sorted/b", "sorted/b0", "sorted/c/d"} {
+		enc, err := EncryptPathWithStoreCipher(bucket, paths.NewUnencrypted(name), store)
+		require.NoError(t, err)
+		require.True(t, strings.HasPrefix(enc.Raw(), "sorted/"))
+		encrypted = append(encrypted, enc.Raw())
+
+		dec, err := DecryptPathWithStoreCipher(bucket, enc, store)
+		require.NoError(t, err)
+		require.Equal(t, name, dec.Raw())
+	}
+	require.True(t, slices.IsSorted(encrypted))
+
+	// the prefix of a directory is a prefix of the paths inside of it.
+	prefix, err := EncryptPrefixWithStoreCipher(bucket, paths.NewUnencrypted("sorted/c/"), store)
+	require.NoError(t, err)
+	require.True(t, strings.HasPrefix(encrypted[3], prefix.Raw()))
+
+	// paths outside the mapping use the default cipher.
+	_, _, base := store.LookupUnencrypted(bucket, paths.NewUnencrypted("other/a"))
+	require.Equal(t, storj.EncAESGCM, base.PathCipher)
+
+	// order preserving encryption must be selected explicitly.
+	store.SetDefaultPathCipher(storj.EncPathOrderPreserving)
+	_, err = EncryptPathWithStoreCipher(bucket, paths.NewUnencrypted("other/a"), store)
+	require.Error(t, err)
+	require.Error(t, store.Add(bucket, paths.NewUnencrypted("x"), paths.NewEncrypted("x"), testrand.Key()))
+
+	// it can't be used for data.
+	_, err = NewEncrypter(storj.EncPathOrderPreserving, &defaultKey, new(storj.Nonce), 1024)
+	require.Error(t, err)
+}
+
+func TestOrderPreservingWrongKey(t *testing.T) {
+	// decryption isn't authenticated, so short components sometimes decrypt with
+	// the wrong key.
+	key, otherKey, garbage := testrand.Key(), testrand.Key(), ""
+	for found := false; !found; {
+		otherKey = testrand.Key()
+
+		var err error
+		garbage, err = decryptOrderPreserving(encryptOrderPreserving("a", &key), &otherKey)
+		found = err == nil && garbage != "a"
+	}
+	require.Len(t, garbage, 1)
+
+	// hence the store never tries another version of the key.
+	bucket, unenc := "bucket", paths.NewUnencrypted("sorted")
+	store := NewStore()
+	require.Error(t, store.AddWithKeyVersions(bucket, unenc, paths.NewEncrypted("sorted"), storj.EncPathOrderPreserving,
+		KeyVersion{Version: 1, Key: otherKey}, KeyVersion{Version: 0, Key: key}))
+	require.NoError(t, store.AddWithCipher(bucket, unenc, paths.NewEncrypted("sorted"), key, storj.EncPathOrderPreserving))
+
+	enc, err := EncryptPathWithStoreCipher(bucket, paths.NewUnencrypted("sorted/a"), store)
+	require.NoError(t, err)
+
+	// rotating the key would make the active key decrypt the path to garbage.
+	require.True(t, ErrInvalidConfig.Has(store.RotateKey(bucket, unenc, otherKey)))
+
+	dec, err := DecryptPathWithStoreCipher(bucket, enc, store)
+	require.NoError(t, err)
+	require.Equal(t, "sorted/a", dec.Raw())
+}
@@ -5,6 +5,7 @@ package encryption
 
 import (
 	"maps"
+	"slices"
 
 	"github.com/zeebo/errs"
 
@@ -34,9 +35,11 @@ import (
 //	b1, u6/u7       => <{e8:u8}, [u7], <u6, e6, k6>>
 //	b2, u1          => <{}, [u1], <u1, e1', k1'>>
 type Store struct {
-	roots             map[string]*node
-	defaultKey        *storj.Key
-	defaultPathCipher storj.CipherSuite
+	roots               map[string]*node
+	defaultKey          *storj.Key
+	defaultKeyVersion   uint32
+	previousDefaultKeys []KeyVersion
+	defaultPathCipher   storj.CipherSuite
 
 	// EncryptionBypass makes it so we can interoperate with
 	// the network without having encryption keys. paths will be encrypted but
@@ -53,9 +56,11 @@ func (s *Store) Clone() *Store {
 	}
 
 	clone := &Store{
-		roots:             make(map[string]*node),
-		defaultPathCipher: s.defaultPathCipher,
-		EncryptionBypass:  s.EncryptionBypass,
+		roots:               make(map[string]*node),
+		defaultKeyVersion:   s.defaultKeyVersion,
+		previousDefaultKeys: slices.Clone(s.previousDefaultKeys),
+		defaultPathCipher:   s.defaultPathCipher,
+		EncryptionBypass:    s.EncryptionBypass,
 	}
 
 	// Deep copy the defaultKey if it's not nil
@@ -126,6 +131,12 @@ func (n *node) clone() *node {
 	return clone
 }
 
+// KeyVersion is a generation of a key. Versions increase with every rotation.
+type KeyVersion struct {
+	Version uint32
+	Key     storj.Key
+}
+
 // Base represents a key with which to derive further keys at some encrypted/unencrypted path.
 type Base struct {
 	Unencrypted paths.Unencrypted
@@ -133,18 +144,63 @@ type Base struct {
 	Key         storj.Key
 	PathCipher  storj.CipherSuite
 	Default     bool
+
+	// KeyVersion is the version of Key, which is the active key used for
+	// encrypting new data.
+	KeyVersion uint32
+	// PreviousKeys are keys from before rotations, most recent first. They
+	// are only used for decrypting existing data.
+	PreviousKeys []KeyVersion
+}
+
+// CandidateKeys returns the keys that should be tried for decryption,
+// starting with the active key.
+func (b *Base) CandidateKeys() []KeyVersion {
+	keys := make([]KeyVersion, 0, 1+len(b.PreviousKeys))
+	keys = append(keys, KeyVersion{Version: b.KeyVersion, Key: b.Key})
+	return append(keys, b.PreviousKeys...)
 }
 
-// clone returns a copy of the Base. The implementation can be simple because the
-// types of its fields do not contain any references.
+// clone returns a copy of the Base.
 func (b *Base) clone() *Base {
 	if b == nil {
 		return nil
 	}
 	bc := *b
+	bc.PreviousKeys = slices.Clone(b.PreviousKeys)
 	return &bc
 }
 
+// newBase creates a base from keys, where the first key is the active one.
+func newBase(keys []KeyVersion) (*Base, error) {
+	if len(keys) == 0 {
+		return nil, errs.New("missing key")
+	}
+	for i, key := range keys {
+		for _, other := range keys[:i] {
+			if key.Version == other.Version {
+				return nil, errs.New("duplicate key version %d", key.Version)
+			}
+		}
+	}
+	return &Base{
+		Key:          keys[0].Key,
+		KeyVersion:   keys[0].Version,
+		PreviousKeys: slices.Clone(keys[1:]),
+	}, nil
+}
+
+// rotate makes the key active with a version newer than any existing one and
+// keeps the current active key for decryption.
+func (b *Base) rotate(key storj.Key) {
+	version := b.KeyVersion
+	for _, previous := range b.PreviousKeys {
+		version = max(version, previous.Version)
+	}
+	b.PreviousKeys = slices.Insert(b.PreviousKeys, 0, KeyVersion{Version: b.KeyVersion, Key: b.Key})
+	b.Key, b.KeyVersion = key, version+1
+}
+
 // NewStore constructs a Store.
 func NewStore() *Store {
 	return &Store{roots: make(map[string]*node)}
@@ -161,8 +217,47 @@ func newNode() *node {
 }
 
 // SetDefaultKey adds a default key to be returned for any lookup that does not match a bucket.
+// It removes any previous versions of the default key.
 func (s *Store) SetDefaultKey(defaultKey *storj.Key) {
 	s.defaultKey = defaultKey
+	s.defaultKeyVersion = 0
+	s.previousDefaultKeys = nil
+}
+
+// SetDefaultKeyVersions sets the versions of the default key. The first key is
+// the active one, the rest are only used for decryption.
+func (s *Store) SetDefaultKeyVersions(keys ...KeyVersion) error {
+	base, err := newBase(keys)
+	if err != nil {
+		return err
+	}
+	s.defaultKey = &base.Key
+	s.defaultKeyVersion = base.KeyVersion
+	s.previousDefaultKeys = base.PreviousKeys
+	return nil
+}
+
+// GetDefaultKeyVersions returns the versions of the default key, starting with
+// the active one, or nil if none has been set.
+func (s *Store) GetDefaultKeyVersions() []KeyVersion {
+	if s.defaultKey == nil {
+		return nil
+	}
+	return s.defaultBase().CandidateKeys()
+}
+
+// RotateDefaultKey makes the key the active default key. The current default
+// key is kept for decrypting existing data.
+func (s *Store) RotateDefaultKey(key storj.Key) {
+	if s.defaultKey == nil {
+		s.SetDefaultKey(&key)
+		return
+	}
+	base := s.defaultBase()
+	base.rotate(key)
+	s.defaultKey = &base.Key
+	s.defaultKeyVersion = base.KeyVersion
+	s.previousDefaultKeys = base.PreviousKeys
 }
 
 // GetDefaultKey returns the default key, or nil if none has been set.
@@ -182,23 +277,49 @@ func (s *Store) GetDefaultPathCipher() storj.CipherSuite {
 
 // Add creates a mapping from the unencrypted path to the encrypted path and key. It uses the current default cipher.
 func (s *Store) Add(bucket string, unenc paths.Unencrypted, enc paths.Encrypted, key storj.Key) error {
+	if s.defaultPathCipher == storj.EncPathOrderPreserving {
+		return errOrderPreservingOptIn
+	}
 	return s.AddWithCipher(bucket, unenc, enc, key, s.defaultPathCipher)
 }
 
 // AddWithCipher creates a mapping from the unencrypted path to the encrypted path and key with the given cipher.
+//
+// This is the only way to opt in to storj.EncPathOrderPreserving, which is never
+// used for the default key. Paths below the mapping are encrypted such that the
+// encrypted components sort like the unencrypted ones. Encrypted path prefixes,
+// e.g. in api key caveats, work the same way as with other ciphers. Additionally
+// the encryption of a component is a prefix of the encryption of every component
+// it's a prefix of.
 func (s *Store) AddWithCipher(bucket string, unenc paths.Unencrypted, enc paths.Encrypted, key storj.Key, pathCipher storj.CipherSuite) error {
+	return s.AddWithKeyVersions(bucket, unenc, enc, pathCipher, KeyVersion{Key: key})
+}
+
+// AddWithKeyVersions creates a mapping from the unencrypted path to the encrypted path and
+// multiple versions of the key with the given cipher. The first key is the active one, the
+// rest are only used for decryption.
+//
+// Paths encrypted with storj.EncPathOrderPreserving aren't authenticated, so it
+// only supports a single key.
+func (s *Store) AddWithKeyVersions(bucket string, unenc paths.Unencrypted, enc paths.Encrypted, pathCipher storj.CipherSuite, keys ...KeyVersion) error {
+	if pathCipher == storj.EncPathOrderPreserving && len(keys) > 1 {
+		return errOrderPreservingKeyVersions
+	}
+	base, err := newBase(keys)
+	if err != nil {
+		return err
+	}
+	base.Unencrypted = unenc
+	base.Encrypted = enc
+	base.PathCipher = pathCipher
+
 	root, ok := s.roots[bucket]
 	if !ok {
 		root = newNode()
 	}
 
 	// Perform the addition starting at the root node.
-	if err := root.add(unenc.Iterator(), enc.Iterator(), &Base{
-		Unencrypted: unenc,
-		Encrypted:   enc,
-		Key:         key,
-		PathCipher:  pathCipher,
-	}); err != nil {
+	if err := root.add(unenc.Iterator(), enc.Iterator(), base); err != nil {
 		return err
 	}
 
@@ -207,6 +328,67 @@ func (s *Store) AddWithCipher(bucket string, unenc paths.Unencrypted, enc paths.
 	return nil
 }
 
+// RotateKey makes the key the active key of the mapping previously added for
+// exactly the unencrypted path. The current key is kept for decrypting existing data.
+// Mappings using storj.EncPathOrderPreserving can't be rotated.
+func (s *Store) RotateKey(bucket string, unenc paths.Unencrypted, key storj.Key) error {
+	base, err := s.exactBase(bucket, unenc)
+	if err != nil {
+		return err
+	}
+	if base.PathCipher == storj.EncPathOrderPreserving {
+		return errOrderPreservingKeyVersions
+	}
+	base.rotate(key)
+	return nil
+}
+
+// SetActiveKeyVersion makes an existing version of the key the active key of
+// the mapping previously added for exactly the unencrypted path. It can be
+// used to roll back a rotation.
+func (s *Store) SetActiveKeyVersion(bucket string, unenc paths.Unencrypted, version uint32) error {
+	base, err := s.exactBase(bucket, unenc)
+	if err != nil {
+		return err
+	}
+	if base.KeyVersion == version {
+		return nil
+	}
+
+	i := slices.IndexFunc(base.PreviousKeys, func(key KeyVersion) bool {
+		return key.Version == version
+	})
+	if i < 0 {
+		return errs.New("unknown key version %d for %q/%q", version, bucket, unenc)
+	}
+
+	active := base.PreviousKeys[i]
+	base.PreviousKeys[i] = KeyVersion{Version: base.KeyVersion, Key: base.Key}
+	base.Key, base.KeyVersion = active.Key, active.Version
+	return nil
+}
+
+// exactBase returns the base added for exactly the unencrypted path.
+func (s *Store) exactBase(bucket string, unenc paths.Unencrypted) (*Base, error) {
+	n, ok := s.roots[bucket]
+	for iter := unenc.Iterator(); ok && !iter.Done(); {
+		n, ok = n.unenc[iter.Next()]
+	}
+	if !ok || n.base == nil {
+		return nil, errs.New("no mapping for %q/%q", bucket, unenc)
+	}
+	return n.base, nil
+}
+
+// errOrderPreservingOptIn is returned when order preserving encryption would
+// be used without explicitly selecting it.
+var errOrderPreservingOptIn = ErrInvalidConfig.New("order preserving path encryption must be selected with AddWithCipher")
+
+// errOrderPreservingKeyVersions is returned when order preserving encryption
+// would be used with multiple versions of a key. Decrypting a path with a wrong
+// key often succeeds, so trying previous versions could return garbage.
+var errOrderPreservingKeyVersions = ErrInvalidConfig.New("order preserving path encryption does not support key versions")
+
 // add places the paths and base into the node tree structure.
 func (n *node) add(unenc, enc paths.Iterator, base *Base) error {
 	if unenc.Done() != enc.Done() {
@@ -297,9 +479,11 @@ func (s *Store) LookupEncrypted(bucket string, path paths.Encrypted) (
 
 func (s *Store) defaultBase() *Base {
 	return &Base{
-		Key:        *s.defaultKey,
-		PathCipher: s.defaultPathCipher,
-		Default:    true,
+		Key:          *s.defaultKey,
+		PathCipher:   s.defaultPathCipher,
+		Default:      true,
+		KeyVersion:   s.defaultKeyVersion,
+		PreviousKeys: slices.Clone(s.previousDefaultKeys),
 	}
 }
 
@@ -394,3 +578,34 @@ func (n *node) iterateWithCipher(fn func(string, paths.Unencrypted, paths.Encryp
 
 	return nil
 }
+
+// IterateWithKeyVersions executes the callback with every value that has been Added to the Store,
+// including all versions of the keys, starting with the active one.
+func (s *Store) IterateWithKeyVersions(fn func(string, paths.Unencrypted, paths.Encrypted, storj.CipherSuite, []KeyVersion) error) error {
+	for bucket, root := range s.roots {
+		if err := root.iterateWithKeyVersions(fn, bucket); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// iterateWithKeyVersions calls the callback if the node has a base, and recurses to its children.
+func (n *node) iterateWithKeyVersions(fn func(string, paths.Unencrypted, paths.Encrypted, storj.CipherSuite, []KeyVersion) error, bucket string) error {
+	if n.base != nil {
+		err := fn(bucket, n.base.Unencrypted, n.base.Encrypted, n.base.PathCipher, n.base.CandidateKeys())
+		if err != nil {
+			return err
+		}
+	}
+
+	// recurse down only the unenc map, as the enc map should be the same.
+	for _, child := range n.unenc {
+		err := child.iterateWithKeyVersions(fn, bucket)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
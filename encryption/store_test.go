@@ -317,3 +317,141 @@ func TestStoreCloneAllocations(t *testing.T) {
 		require.Less(t, allocs(i), 100*i, "should not have non-linear allocations")
 	}
 }
+
+func TestStoreKeyRotation(t *testing.T) {
+	oldKey, newKey := testrand.Key(), testrand.Key()
+	bucket, unenc := "bucket", paths.NewUnencrypted("u1/u2")
+
+	store := NewStore()
+	store.SetDefaultPathCipher(storj.EncAESGCM)
+	require.NoError(t, store.Add(bucket, paths.Unencrypted{}, paths.Encrypted{}, oldKey))
+
+	// encrypt a path with the old key.
+	enc, err := EncryptPathWithStoreCipher(bucket, unenc, store)
+	require.NoError(t, err)
+	oldPathKey, err := DerivePathKey(bucket, unenc, store)
+	require.NoError(t, err)
+
+	require.NoError(t, store.RotateKey(bucket, paths.Unencrypted{}, newKey))
+	require.Error(t, store.RotateKey(bucket, paths.NewUnencrypted("missing"), newKey))
+
+	_, _, base := store.LookupUnencrypted(bucket, unenc)
+	require.Equal(t, newKey, base.Key)
+	require.EqualValues(t, 1, base.KeyVersion)
+	require.Equal(t, []KeyVersion{{Version: 1, Key: newKey}, {Version: 0, Key: oldKey}}, base.CandidateKeys())
+
+	// new paths are encrypted with the new key.
+	newEnc, err := EncryptPathWithStoreCipher(bucket, unenc, store)
+	require.NoError(t, err)
+	require.NotEqual(t, enc, newEnc)
+
+	// paths encrypted with either key can be decrypted.
+	for _, enc := range []paths.Encrypted{enc, newEnc} {
+		dec, err := DecryptPathWithStoreCipher(bucket, enc, store)
+		require.NoError(t, err)
+		require.Equal(t, unenc, dec)
+	}
+
+	pathKeys, err := DerivePathKeyVersions(bucket, unenc, store)
+	require.NoError(t, err)
+	require.Len(t, pathKeys, 2)
+	require.Equal(t, *oldPathKey, pathKeys[1].Key)
+
+	// rolling back makes the old key active again.
+	require.NoError(t, store.SetActiveKeyVersion(bucket, paths.Unencrypted{}, 0))
+	require.Error(t, store.SetActiveKeyVersion(bucket, paths.Unencrypted{}, 5))
+	_, _, base = store.LookupUnencrypted(bucket, unenc)
+	require.Equal(t, oldKey, base.Key)
+	require.Equal(t, []KeyVersion{{Version: 1, Key: newKey}}, base.PreviousKeys)
+
+	// rotating again creates a new version.
+	require.NoError(t, store.RotateKey(bucket, paths.Unencrypted{}, testrand.Key()))
+	_, _, base = store.LookupUnencrypted(bucket, unenc)
+	require.EqualValues(t, 2, base.KeyVersion)
+
+	require.Error(t, store.AddWithKeyVersions(bucket, unenc, enc, storj.EncAESGCM))
+	require.Error(t, store.AddWithKeyVersions(bucket, unenc, enc, storj.EncAESGCM,
+		KeyVersion{Version: 1, Key: newKey}, KeyVersion{Version: 1, Key: oldKey}))
+}
+
+func TestStoreDefaultKeyRotation(t *testing.T) {
+	oldKey, newKey := testrand.Key(), testrand.Key()
+	bucket, unenc := "bucket", paths.NewUnencrypted("u1/u2")
+
+	store := NewStore()
+	store.SetDefaultPathCipher(storj.EncAESGCM)
+	store.SetDefaultKey(&oldKey)
+
+	enc, err := EncryptPathWithStoreCipher(bucket, unenc, store)
+	require.NoError(t, err)
+
+	store.RotateDefaultKey(newKey)
+	require.Equal(t, newKey, *store.GetDefaultKey())
+	require.Equal(t, []KeyVersion{{Version: 1, Key: newKey}, {Version: 0, Key: oldKey}}, store.GetDefaultKeyVersions())
+
+	dec, err := DecryptPathWithStoreCipher(bucket, enc, store)
+	require.NoError(t, err)
+	require.Equal(t, unenc, dec)
+
+	clone := store.Clone()
+	require.Equal(t, store.GetDefaultKeyVersions(), clone.GetDefaultKeyVersions())
+
+	// setting the default key removes previous versions.
+	store.SetDefaultKey(&newKey)
+	require.Equal(t, []KeyVersion{{Version: 0, Key: newKey}}, store.GetDefaultKeyVersions())
+	_, err = DecryptPathWithStoreCipher(bucket, enc, store)
+	require.Error(t, err)
+}
+
+func TestEncryptPathVersionsAfterRotation(t *testing.T) {
+	oldKey, newKey := testrand.Key(), testrand.Key()
+	bucket, name := "bucket", paths.NewUnencrypted("photos/cat.jpg")
+
+	for _, tt := range []struct {
+		name   string
+		setup  func(store *Store)
+		rotate func(store *Store)
+	}{
+		{
+			name:   "default key",
+			setup:  func(store *Store) { store.SetDefaultKey(&oldKey) },
+			rotate: func(store *Store) { store.RotateDefaultKey(newKey) },
+		},
+		{
+			name: "mapping",
+			setup: func(store *Store) {
+				require.NoError(t, store.Add(bucket, paths.NewUnencrypted("photos"), paths.NewEncrypted("photos"), oldKey))
+			},
+			rotate: func(store *Store) {
+				require.NoError(t, store.RotateKey(bucket, paths.NewUnencrypted("photos"), newKey))
+			},
+		},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			store := NewStore()
+			store.SetDefaultPathCipher(storj.EncAESGCM)
+			tt.setup(store)
+
+			// the object is written before the rotation.
+			written, err := EncryptPathWithStoreCipher(bucket, name, store)
+			require.NoError(t, err)
+
+			tt.rotate(store)
+
+			active, err := EncryptPathWithStoreCipher(bucket, name, store)
+			require.NoError(t, err)
+			require.NotEqual(t, written, active)
+
+			// looking up the object by name finds it with the previous version.
+			versions, err := EncryptPathVersionsWithStoreCipher(bucket, name, store)
+			require.NoError(t, err)
+			require.Equal(t, []EncryptedPathVersion{
+				{Version: 1, Path: active},
+				{Version: 0, Path: written},
+			}, versions)
+		})
+	}
+
+	_, err := EncryptPathVersionsWithStoreCipher(bucket, name, NewStore())
+	require.True(t, ErrMissingEncryptionBase.Has(err))
+}
@@ -4,6 +4,7 @@
 package encryption
 
 import (
+	"bufio"
 	"bytes"
 	"context"
 	"errors"
@@ -33,6 +34,12 @@ type transformedReader struct {
 	outbuf       []byte
 	expectedSize int64
 	bytesRead    int
+
+	// finalBlock is the number of the last block for FinalBlockTransformers,
+	// or -1 if it's found by peeking for the end of the reader.
+	finalBlock int64
+	peek       *bufio.Reader
+	sawFinal   bool
 }
 
 // NoopTransformer is a dummy Transformer that passes data through without modifying it.
@@ -57,13 +64,7 @@ func (t *NoopTransformer) Transform(out, in []byte, blockNum int64) ([]byte, err
 // probably be 0 unless you know you're already starting at a block offset.
 func TransformReader(r io.ReadCloser, t Transformer,
 	startingBlockNum int64) io.ReadCloser {
-	return &transformedReader{
-		r:        r,
-		t:        t,
-		blockNum: startingBlockNum,
-		inbuf:    make([]byte, t.InBlockSize()),
-		outbuf:   make([]byte, 0, t.OutBlockSize()),
-	}
+	return newTransformedReader(r, t, startingBlockNum, 0, -1)
 }
 
 // TransformReaderSize creates a TransformReader with expected size,
@@ -72,28 +73,47 @@ func TransformReader(r io.ReadCloser, t Transformer,
 // io.ErrUnexpectedEOF instead of io.EOF.
 func TransformReaderSize(r io.ReadCloser, t Transformer,
 	startingBlockNum int64, expectedSize int64) io.ReadCloser {
-	return &transformedReader{
+	return newTransformedReader(r, t, startingBlockNum, expectedSize, -1)
+}
+
+// newTransformedReader creates a transformedReader. finalBlock is the number of
+// the last block of the stream, or -1 if it's not known.
+func newTransformedReader(r io.ReadCloser, t Transformer,
+	startingBlockNum int64, expectedSize int64, finalBlock int64) *transformedReader {
+	tr := &transformedReader{
 		r:            r,
 		t:            t,
 		blockNum:     startingBlockNum,
 		inbuf:        make([]byte, t.InBlockSize()),
 		outbuf:       make([]byte, 0, t.OutBlockSize()),
 		expectedSize: expectedSize,
+		finalBlock:   finalBlock,
 	}
+	if _, ok := t.(FinalBlockTransformer); ok && finalBlock < 0 {
+		tr.peek = bufio.NewReader(r)
+	}
+	return tr
 }
 
 func (t *transformedReader) Read(p []byte) (n int, err error) {
 	if len(t.outbuf) == 0 {
 		// If there's no more buffered data left, let's fill the buffer with
 		// the next block
-		b, err := io.ReadFull(t.r, t.inbuf)
+		var r io.Reader = t.r
+		if t.peek != nil {
+			r = t.peek
+		}
+		b, err := io.ReadFull(r, t.inbuf)
 		t.bytesRead += b
 		if errors.Is(err, io.EOF) && int64(t.bytesRead) < t.expectedSize {
 			return 0, io.ErrUnexpectedEOF
+		} else if errors.Is(err, io.EOF) && t.peek != nil && !t.sawFinal {
+			// the stream ended without the final block, so it was truncated.
+			return 0, io.ErrUnexpectedEOF
 		} else if err != nil {
 			return 0, err
 		}
-		t.outbuf, err = t.t.Transform(t.outbuf, t.inbuf, t.blockNum)
+		t.outbuf, err = t.transform()
 		if err != nil {
 			if errors.Is(err, io.EOF) {
 				return 0, err
@@ -112,6 +132,30 @@ func (t *transformedReader) Read(p []byte) (n int, err error) {
 	return n, nil
 }
 
+// transform transforms the buffered block, using TransformFinal for the last
+// block of FinalBlockTransformers.
+func (t *transformedReader) transform() ([]byte, error) {
+	final, ok := t.t.(FinalBlockTransformer)
+	if !ok {
+		return t.t.Transform(t.outbuf, t.inbuf, t.blockNum)
+	}
+
+	isFinal := t.blockNum == t.finalBlock
+	if t.peek != nil {
+		_, err := t.peek.Peek(1)
+		if err != nil && !errors.Is(err, io.EOF) {
+			return nil, err
+		}
+		isFinal = errors.Is(err, io.EOF)
+	}
+	if !isFinal {
+		return final.Transform(t.outbuf, t.inbuf, t.blockNum)
+	}
+
+	t.sawFinal = true
+	return final.TransformFinal(t.outbuf, t.inbuf, t.blockNum)
+}
+
 func (t *transformedReader) Close() error {
 	return t.r.Close()
 }
@@ -171,7 +215,8 @@ func (t *transformedRanger) Range(ctx context.Context, offset, length int64) (_
 	if err != nil {
 		return nil, err
 	}
-	tr := TransformReaderSize(r, t.t, firstBlock, blockCount*int64(t.t.InBlockSize()))
+	finalBlock := t.rr.Size()/int64(t.t.InBlockSize()) - 1
+	tr := newTransformedReader(r, t.t, firstBlock, blockCount*int64(t.t.InBlockSize()), finalBlock)
 	// the range we got potentially includes more than we wanted. if the
 	// offset started past the beginning of the first block, we need to
 	// swallow the first few bytes
@@ -207,6 +252,7 @@ type transformedWriter struct {
 	inbuf    []byte
 	cursor   []byte
 	outbuf   []byte
+	closing  bool
 	closed   bool
 	err      error
 }
@@ -230,7 +276,12 @@ func (t *transformedWriter) Write(p []byte) (n int, err error) {
 		n += cn
 
 		if len(t.cursor) == 0 {
-			t.outbuf, err = t.t.Transform(t.outbuf[:0], t.inbuf, t.blockNum)
+			// the padding written by Close always ends with the last block.
+			if final, ok := t.t.(FinalBlockTransformer); ok && t.closing && len(p) == 0 {
+				t.outbuf, err = final.TransformFinal(t.outbuf[:0], t.inbuf, t.blockNum)
+			} else {
+				t.outbuf, err = t.t.Transform(t.outbuf[:0], t.inbuf, t.blockNum)
+			}
 			if err != nil {
 				return n, t.storeErr(Error.Wrap(err))
 			}
@@ -252,6 +303,7 @@ func (t *transformedWriter) Close() error {
 		return nil
 	}
 	padding := makePadding(int64(len(t.inbuf))-int64(len(t.cursor)), len(t.inbuf))
+	t.closing = true
 	if _, err := t.Write(padding); err != nil {
 		return t.storeErr(Error.Wrap(err))
 	} else if len(t.cursor) != len(t.inbuf) {
@@ -0,0 +1,191 @@
+// Copyright (C) 2026 Storj Labs, Inc.
+// See LICENSE for copying information.
+
+package encryption
+
+import (
+	"crypto/cipher"
+	"encoding/binary"
+
+	"golang.org/x/crypto/chacha20poly1305"
+
+	"storj.io/common/storj"
+	"storj.io/common/sync2/race2"
+)
+
+// A FinalBlockTransformer is a Transformer that authenticates whether a block
+// is the last block of the stream. TransformReader, Transform and
+// TransformWriterPadded use TransformFinal for the last block, which allows
+// detecting truncated streams.
+type FinalBlockTransformer interface {
+	Transformer
+	TransformFinal(out, in []byte, blockNum int64) ([]byte, error)
+}
+
+// streamAssociatedDataSize is the size of the associated data authenticated
+// with every block of the stream.
+const streamAssociatedDataSize = 9
+
+// streamAssociatedData returns the associated data of a block, which binds the
+// block to its position and tells whether it's the last block of the stream.
+func streamAssociatedData(blockNum int64, final bool) []byte {
+	var ad [streamAssociatedDataSize]byte
+	binary.BigEndian.PutUint64(ad[:8], uint64(blockNum))
+	if final {
+		ad[8] = 1
+	}
+	return ad[:]
+}
+
+type xchachaStreamEncrypter struct {
+	blockSize     int
+	startingNonce *storj.Nonce
+	aead          cipher.AEAD
+}
+
+// NewXChaCha20Poly1305StreamEncrypter returns a FinalBlockTransformer that
+// encrypts the data passing through with key using XChaCha20-Poly1305 in a
+// STREAM-like construction.
+//
+// The nonce of every block is calculated from startingNonce like in
+// NewSecretboxEncrypter. Additionally, the block number and whether the block
+// is the last one are authenticated, such that reordered, dropped and
+// truncated blocks fail to decrypt, even when only a range of the stream is
+// decrypted. The startingNonce must be unique for every stream encrypted with
+// the same key, e.g. for every segment.
+func NewXChaCha20Poly1305StreamEncrypter(key *storj.Key, startingNonce *storj.Nonce, encryptedBlockSize int) (FinalBlockTransformer, error) {
+	race2.ReadSlice(key[:])
+	race2.ReadSlice(startingNonce[:])
+
+	aead, err := chacha20poly1305.NewX(key[:])
+	if err != nil {
+		return nil, Error.Wrap(err)
+	}
+	if encryptedBlockSize <= aead.Overhead() {
+		return nil, ErrInvalidConfig.New("encrypted block size %d too small", encryptedBlockSize)
+	}
+	return &xchachaStreamEncrypter{
+		blockSize:     encryptedBlockSize - aead.Overhead(),
+		startingNonce: startingNonce,
+		aead:          aead,
+	}, nil
+}
+
+func (s *xchachaStreamEncrypter) InBlockSize() int {
+	return s.blockSize
+}
+
+func (s *xchachaStreamEncrypter) OutBlockSize() int {
+	return s.blockSize + s.aead.Overhead()
+}
+
+func (s *xchachaStreamEncrypter) Transform(out, in []byte, blockNum int64) ([]byte, error) {
+	return s.transform(out, in, blockNum, false)
+}
+
+func (s *xchachaStreamEncrypter) TransformFinal(out, in []byte, blockNum int64) ([]byte, error) {
+	return s.transform(out, in, blockNum, true)
+}
+
+func (s *xchachaStreamEncrypter) transform(out, in []byte, blockNum int64, final bool) ([]byte, error) {
+	race2.ReadSlice(in)
+	race2.WriteSlice(out)
+
+	nonce, err := calcNonce(s.startingNonce, blockNum)
+	if err != nil {
+		return nil, err
+	}
+
+	return s.aead.Seal(out, nonce[:], in, streamAssociatedData(blockNum, final)), nil
+}
+
+type xchachaStreamDecrypter struct {
+	blockSize     int
+	startingNonce *storj.Nonce
+	aead          cipher.AEAD
+}
+
+// NewXChaCha20Poly1305StreamDecrypter returns a FinalBlockTransformer that
+// decrypts the data passing through with key. See the comments for
+// NewXChaCha20Poly1305StreamEncrypter.
+func NewXChaCha20Poly1305StreamDecrypter(key *storj.Key, startingNonce *storj.Nonce, encryptedBlockSize int) (FinalBlockTransformer, error) {
+	race2.ReadSlice(key[:])
+	race2.ReadSlice(startingNonce[:])
+
+	aead, err := chacha20poly1305.NewX(key[:])
+	if err != nil {
+		return nil, Error.Wrap(err)
+	}
+	if encryptedBlockSize <= aead.Overhead() {
+		return nil, ErrInvalidConfig.New("encrypted block size %d too small", encryptedBlockSize)
+	}
+	return &xchachaStreamDecrypter{
+		blockSize:     encryptedBlockSize - aead.Overhead(),
+		startingNonce: startingNonce,
+		aead:          aead,
+	}, nil
+}
+
+func (s *xchachaStreamDecrypter) InBlockSize() int {
+	return s.blockSize + s.aead.Overhead()
+}
+
+func (s *xchachaStreamDecrypter) OutBlockSize() int {
+	return s.blockSize
+}
+
+func (s *xchachaStreamDecrypter) Transform(out, in []byte, blockNum int64) ([]byte, error) {
+	return s.transform(out, in, blockNum, false)
+}
+
+func (s *xchachaStreamDecrypter) TransformFinal(out, in []byte, blockNum int64) ([]byte, error) {
+	return s.transform(out, in, blockNum, true)
+}
+
+func (s *xchachaStreamDecrypter) transform(out, in []byte, blockNum int64, final bool) ([]byte, error) {
+	race2.ReadSlice(in)
+	race2.WriteSlice(out)
+
+	nonce, err := calcNonce(s.startingNonce, blockNum)
+	if err != nil {
+		return nil, err
+	}
+
+	rv, err := s.aead.Open(out, nonce[:], in, streamAssociatedData(blockNum, final))
+	if err != nil {
+		return nil, ErrDecryptFailed.Wrap(err)
+	}
+	return rv, nil
+}
+
+// EncryptXChaCha20Poly1305 encrypts byte data with a key and nonce as a stream
+// consisting of a single final block. The cipher data is returned.
+func EncryptXChaCha20Poly1305(data []byte, key *storj.Key, nonce *storj.Nonce) (cipherData []byte, err error) {
+	race2.ReadSlice(nonce[:])
+	race2.ReadSlice(key[:])
+	race2.ReadSlice(data)
+
+	aead, err := chacha20poly1305.NewX(key[:])
+	if err != nil {
+		return nil, Error.Wrap(err)
+	}
+	return aead.Seal(nil, nonce[:], data, streamAssociatedData(0, true)), nil
+}
+
+// DecryptXChaCha20Poly1305 decrypts byte data encrypted with
+// EncryptXChaCha20Poly1305. The plain data is returned.
+func DecryptXChaCha20Poly1305(cipherData []byte, key *storj.Key, nonce *storj.Nonce) (data []byte, err error) {
+	race2.ReadSlice(nonce[:])
+	race2.ReadSlice(key[:])
+	race2.ReadSlice(cipherData)
+
+	aead, err := chacha20poly1305.NewX(key[:])
+	if err != nil {
+		return nil, Error.Wrap(err)
+	}
+	data, err = aead.Open(nil, nonce[:], cipherData, streamAssociatedData(0, true))
+	if err != nil {
+		return nil, ErrDecryptFailed.Wrap(err)
+	}
+	return data, nil
+}
@@ -0,0 +1,134 @@
+// Copyright (C) 2026 Storj Labs, Inc.
+// See LICENSE for copying information.
+
+package encryption
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"storj.io/common/ranger"
+	"storj.io/common/storj"
+	"storj.io/common/testrand"
+)
+
+func TestXChaCha20Poly1305Stream(t *testing.T) {
+	key := testrand.Key()
+	firstNonce := testrand.Nonce()
+	const blockSize = 1024
+
+	encrypter, err := NewEncrypter(storj.EncXChaCha20Poly1305Stream, &key, &firstNonce, blockSize)
+	require.NoError(t, err)
+	decrypter, err := NewDecrypter(storj.EncXChaCha20Poly1305Stream, &key, &firstNonce, blockSize)
+	require.NoError(t, err)
+
+	data := testrand.BytesInt(encrypter.InBlockSize() * 10)
+
+	encrypted, err := io.ReadAll(TransformReader(io.NopCloser(bytes.NewReader(data)), encrypter, 0))
+	require.NoError(t, err)
+	require.Len(t, encrypted, 10*blockSize)
+
+	decrypt := func(encrypted []byte) ([]byte, error) {
+		return io.ReadAll(TransformReader(io.NopCloser(bytes.NewReader(encrypted)), decrypter, 0))
+	}
+
+	decrypted, err := decrypt(encrypted)
+	require.NoError(t, err)
+	require.Equal(t, data, decrypted)
+
+	// truncating the stream at a block boundary is detected.
+	_, err = decrypt(encrypted[:9*blockSize])
+	require.True(t, ErrDecryptFailed.Has(err), err)
+	_, err = decrypt(nil)
+	require.ErrorIs(t, err, io.ErrUnexpectedEOF)
+
+	// reordering blocks is detected.
+	reordered := bytes.Clone(encrypted)
+	copy(reordered[:blockSize], encrypted[blockSize:2*blockSize])
+	copy(reordered[blockSize:2*blockSize], encrypted[:blockSize])
+	_, err = decrypt(reordered)
+	require.True(t, ErrDecryptFailed.Has(err), err)
+
+	// ranges are verified, including the final block.
+	rr, err := Transform(ranger.ByteRanger(encrypted), decrypter)
+	require.NoError(t, err)
+	require.EqualValues(t, len(data), rr.Size())
+
+	for _, r := range []struct{ offset, length int64 }{
+		{0, 10},
+		{int64(decrypter.OutBlockSize()) + 5, 100},
+		{rr.Size() - 100, 100},
+	} {
+		reader, err := rr.Range(context.Background(), r.offset, r.length)
+		require.NoError(t, err)
+		got, err := io.ReadAll(reader)
+		require.NoError(t, err)
+		require.NoError(t, reader.Close())
+		require.Equal(t, data[r.offset:r.offset+r.length], got)
+	}
+
+	truncated, err := Transform(ranger.ByteRanger(encrypted[:9*blockSize]), decrypter)
+	require.NoError(t, err)
+	_, err = truncated.Range(context.Background(), truncated.Size()-10, 10)
+	require.True(t, ErrDecryptFailed.Has(err), err)
+}
+
+func TestXChaCha20Poly1305StreamWriter(t *testing.T) {
+	key := testrand.Key()
+	firstNonce := testrand.Nonce()
+	const blockSize = 256
+
+	parameters := storj.EncryptionParameters{CipherSuite: storj.EncXChaCha20Poly1305Stream, BlockSize: blockSize}
+
+	for _, size := range []int{0, 1, 100, 220, 239, 240, 1000} {
+		data := testrand.BytesInt(size)
+
+		encrypter, err := NewEncrypter(storj.EncXChaCha20Poly1305Stream, &key, &firstNonce, blockSize)
+		require.NoError(t, err)
+
+		var encrypted bytes.Buffer
+		w := TransformWriterPadded(&encrypted, encrypter)
+		_, err = w.Write(data)
+		require.NoError(t, err)
+		require.NoError(t, w.Close())
+
+		expectedSize, err := CalcEncryptedSize(int64(size), parameters)
+		require.NoError(t, err)
+		require.EqualValues(t, expectedSize, encrypted.Len())
+
+		decrypter, err := NewDecrypter(storj.EncXChaCha20Poly1305Stream, &key, &firstNonce, blockSize)
+		require.NoError(t, err)
+
+		rr, err := Transform(ranger.ByteRanger(encrypted.Bytes()), decrypter)
+		require.NoError(t, err)
+		rr, err = UnpadSlow(context.Background(), rr)
+		require.NoError(t, err)
+
+		reader, err := rr.Range(context.Background(), 0, rr.Size())
+		require.NoError(t, err)
+		got, err := io.ReadAll(reader)
+		require.NoError(t, err)
+		require.Equal(t, data, got)
+	}
+}
+
+func TestXChaCha20Poly1305(t *testing.T) {
+	key := testrand.Key()
+	nonce := testrand.Nonce()
+	data := testrand.BytesInt(100)
+
+	encrypted, err := Encrypt(data, storj.EncXChaCha20Poly1305Stream, &key, &nonce)
+	require.NoError(t, err)
+
+	decrypted, err := Decrypt(encrypted, storj.EncXChaCha20Poly1305Stream, &key, &nonce)
+	require.NoError(t, err)
+	require.Equal(t, data, decrypted)
+
+	encrypted[0] ^= 1
+	_, err = Decrypt(encrypted, storj.EncXChaCha20Poly1305Stream, &key, &nonce)
+	require.True(t, ErrDecryptFailed.Has(err), err)
+}
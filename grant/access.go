@@ -7,11 +7,13 @@ import (
 	"bytes"
 	"errors"
 	"fmt"
+	"math"
 
 	"storj.io/common/base58"
 	"storj.io/common/encryption"
 	"storj.io/common/grant/internal/pb"
 	"storj.io/common/macaroon"
+	"storj.io/common/memory"
 	"storj.io/common/paths"
 	"storj.io/common/storj"
 	"storj.io/picobuf"
@@ -31,7 +33,19 @@ type Access struct {
 //
 // This should be the main way to instantiate an access grant for opening a project.
 // See the note on RequestAccessWithPassphrase.
+//
+// For access grants with multiple satellites it returns the access for the
+// primary satellite. Use ParseMultiAccess to parse all of them.
 func ParseAccess(access string) (*Access, error) {
+	p, err := parseScope(access)
+	if err != nil {
+		return nil, err
+	}
+	return parseAccessFromProto(p.SatelliteAddr, p.ApiKey, p.EncryptionAccess)
+}
+
+// parseScope decodes the serialized access grant.
+func parseScope(access string) (*pb.Scope, error) {
 	data, version, err := base58.CheckDecode(access)
 	if err != nil || version != 0 {
 		return nil, errors.New("invalid access grant format")
@@ -41,24 +55,28 @@ func ParseAccess(access string) (*Access, error) {
 	if err := picobuf.Unmarshal(data, &p); err != nil {
 		return nil, fmt.Errorf("unable to unmarshal access grant: %w", err)
 	}
+	return &p, nil
+}
 
-	if len(p.SatelliteAddr) == 0 {
+// parseAccessFromProto parses the access for a single satellite.
+func parseAccessFromProto(satelliteAddr string, rawAPIKey []byte, p *pb.EncryptionAccess) (*Access, error) {
+	if len(satelliteAddr) == 0 {
 		return nil, errors.New("access grant is missing satellite address")
 	}
 
-	apiKey, err := macaroon.ParseRawAPIKey(p.ApiKey)
+	apiKey, err := macaroon.ParseRawAPIKey(rawAPIKey)
 	if err != nil {
 		return nil, fmt.Errorf("access grant has malformed api key: %w", err)
 	}
 
-	encAccess, err := parseEncryptionAccessFromProto(p.EncryptionAccess)
+	encAccess, err := parseEncryptionAccessFromProto(p)
 	if err != nil {
 		return nil, fmt.Errorf("access grant has malformed encryption access: %w", err)
 	}
 	encAccess.LimitTo(apiKey)
 
 	return &Access{
-		SatelliteAddress: p.SatelliteAddr,
+		SatelliteAddress: satelliteAddr,
 		APIKey:           apiKey,
 		EncAccess:        encAccess,
 	}, nil
@@ -67,13 +85,8 @@ func ParseAccess(access string) (*Access, error) {
 // Serialize serializes an access grant such that it can be used later with
 // ParseAccess or other tools.
 func (access *Access) Serialize() (string, error) {
-	switch {
-	case len(access.SatelliteAddress) == 0:
-		return "", errors.New("access grant is missing satellite address")
-	case access.APIKey == nil:
-		return "", errors.New("access grant is missing api key")
-	case access.EncAccess == nil:
-		return "", errors.New("access grant is missing encryption access")
+	if err := access.validate(); err != nil {
+		return "", err
 	}
 
 	enc, err := access.EncAccess.toProto()
@@ -81,11 +94,29 @@ func (access *Access) Serialize() (string, error) {
 		return "", err
 	}
 
-	data, err := picobuf.Marshal(&pb.Scope{
+	return serializeScope(&pb.Scope{
 		SatelliteAddr:    access.SatelliteAddress,
 		ApiKey:           access.APIKey.SerializeRaw(),
 		EncryptionAccess: enc,
 	})
+}
+
+// validate checks whether the access grant can be serialized.
+func (access *Access) validate() error {
+	switch {
+	case len(access.SatelliteAddress) == 0:
+		return errors.New("access grant is missing satellite address")
+	case access.APIKey == nil:
+		return errors.New("access grant is missing api key")
+	case access.EncAccess == nil:
+		return errors.New("access grant is missing encryption access")
+	}
+	return nil
+}
+
+// serializeScope encodes the access grant.
+func serializeScope(scope *pb.Scope) (string, error) {
+	data, err := picobuf.Marshal(scope)
 	if err != nil {
 		return "", fmt.Errorf("unable to marshal access grant: %w", err)
 	}
@@ -98,6 +129,11 @@ func (access *Access) Serialize() (string, error) {
 // encrypted and decrypted.
 type EncryptionAccess struct {
 	Store *encryption.Store
+
+	// KDFParameters are the parameters used for deriving the default key from
+	// a passphrase. It's nil when they are not known, e.g. for grants created
+	// before the parameters were stored, which use encryption.LegacyKDFParameters.
+	KDFParameters *encryption.KDFParameters
 }
 
 // NewEncryptionAccess creates an encryption access context.
@@ -115,6 +151,31 @@ func NewEncryptionAccessWithDefaultKey(defaultKey *storj.Key) *EncryptionAccess
 	return ec
 }
 
+// NewEncryptionAccessWithPassphrase creates an encryption access context with
+// a default key derived from the passphrase and the salt using the key
+// derivation parameters. The parameters are kept in the encryption access, so
+// the key can be derived again from a serialized access grant.
+func NewEncryptionAccessWithPassphrase(passphrase, salt []byte, params encryption.KDFParameters) (*EncryptionAccess, error) {
+	key, err := encryption.DeriveRootKeyWithParameters(passphrase, salt, "", params)
+	if err != nil {
+		return nil, err
+	}
+
+	ec := NewEncryptionAccessWithDefaultKey(key)
+	ec.KDFParameters = &params
+	return ec, nil
+}
+
+// DeriveKeyFromPassphrase derives the root key from the passphrase and the salt
+// using the key derivation parameters of the encryption access. It fails when
+// the parameters are not known.
+func (s *EncryptionAccess) DeriveKeyFromPassphrase(passphrase, salt []byte) (*storj.Key, error) {
+	if s.KDFParameters == nil {
+		return nil, errors.New("unknown key derivation parameters")
+	}
+	return encryption.DeriveRootKeyWithParameters(passphrase, salt, "", *s.KDFParameters)
+}
+
 // Clone returns a deep copy of EncrytionAccess.
 func (s *EncryptionAccess) Clone() *EncryptionAccess {
 	if s == nil {
@@ -124,6 +185,10 @@ func (s *EncryptionAccess) Clone() *EncryptionAccess {
 	clone := &EncryptionAccess{
 		Store: s.Store.Clone(),
 	}
+	if s.KDFParameters != nil {
+		params := *s.KDFParameters
+		clone.KDFParameters = &params
+	}
 
 	return clone
 }
@@ -184,6 +249,19 @@ func collapsePrefixes(mac *macaroon.Macaroon) ([]*macaroon.Caveat_Path, bool, er
 			groups = append(groups, cav.AllowedPaths)
 			prefixes = append(prefixes, cav.AllowedPaths...)
 		}
+		// the objects matching a pattern are within its literal prefix.
+		if len(cav.AllowedPathPatterns) > 0 {
+			group := make([]*macaroon.Caveat_Path, 0, len(cav.AllowedPathPatterns))
+			for _, pattern := range cav.AllowedPathPatterns {
+				encryptedPathPrefix, _ := pattern.SplitPrefix()
+				group = append(group, &macaroon.Caveat_Path{
+					Bucket:              pattern.Bucket,
+					EncryptedPathPrefix: encryptedPathPrefix,
+				})
+			}
+			groups = append(groups, group)
+			prefixes = append(prefixes, group...)
+		}
 	}
 
 	// if we have no groups/prefixes, then there are no path restrictions.
@@ -242,7 +320,7 @@ func (s *EncryptionAccess) limitTo(apiKey *macaroon.APIKey) (*encryption.Store,
 		if err != nil {
 			continue
 		}
-		key, err := encryption.DerivePathKey(bucket, unencPath, s.Store)
+		keys, err := encryption.DerivePathKeyVersions(bucket, unencPath, s.Store)
 		if err != nil {
 			continue
 		}
@@ -254,7 +332,7 @@ func (s *EncryptionAccess) limitTo(apiKey *macaroon.APIKey) (*encryption.Store,
 			continue // this should not happen given Decrypt succeeded, but whatever
 		}
 
-		if err := store.AddWithCipher(bucket, unencPath, encPath, *key, base.PathCipher); err != nil {
+		if err := store.AddWithKeyVersions(bucket, unencPath, encPath, base.PathCipher, keys...); err != nil {
 			continue
 		}
 	}
@@ -264,13 +342,15 @@ func (s *EncryptionAccess) limitTo(apiKey *macaroon.APIKey) (*encryption.Store,
 
 func (s *EncryptionAccess) toProto() (*pb.EncryptionAccess, error) {
 	var storeEntries []*pb.EncryptionAccess_StoreEntry
-	err := s.Store.IterateWithCipher(func(bucket string, unenc paths.Unencrypted, enc paths.Encrypted, key storj.Key, pathCipher storj.CipherSuite) error {
+	err := s.Store.IterateWithKeyVersions(func(bucket string, unenc paths.Unencrypted, enc paths.Encrypted, pathCipher storj.CipherSuite, keys []encryption.KeyVersion) error {
 		storeEntries = append(storeEntries, &pb.EncryptionAccess_StoreEntry{
 			Bucket:          []byte(bucket),
 			UnencryptedPath: []byte(unenc.Raw()),
 			EncryptedPath:   []byte(enc.Raw()),
-			Key:             key[:],
+			Key:             keys[0].Key[:],
 			PathCipher:      pb.CipherSuite(pathCipher),
+			KeyVersion:      keys[0].Version,
+			PreviousKeys:    keyVersionsToProto(keys[1:]),
 		})
 		return nil
 	})
@@ -279,26 +359,102 @@ func (s *EncryptionAccess) toProto() (*pb.EncryptionAccess, error) {
 	}
 
 	var defaultKey []byte
-	if key := s.Store.GetDefaultKey(); key != nil {
-		defaultKey = key[:]
+	var defaultKeyVersion uint32
+	var previousDefaultKeys []*pb.EncryptionAccess_KeyVersion
+	if keys := s.Store.GetDefaultKeyVersions(); len(keys) > 0 {
+		defaultKey = keys[0].Key[:]
+		defaultKeyVersion = keys[0].Version
+		previousDefaultKeys = keyVersionsToProto(keys[1:])
 	}
 
 	return &pb.EncryptionAccess{
-		DefaultKey:        defaultKey,
-		StoreEntries:      storeEntries,
-		DefaultPathCipher: pb.CipherSuite(s.Store.GetDefaultPathCipher()),
+		DefaultKey:          defaultKey,
+		StoreEntries:        storeEntries,
+		DefaultPathCipher:   pb.CipherSuite(s.Store.GetDefaultPathCipher()),
+		DefaultKeyVersion:   defaultKeyVersion,
+		PreviousDefaultKeys: previousDefaultKeys,
+		KdfParameters:       kdfParametersToProto(s.KDFParameters),
+	}, nil
+}
+
+func kdfParametersToProto(params *encryption.KDFParameters) *pb.KDFParameters {
+	if params == nil {
+		return nil
+	}
+	return &pb.KDFParameters{
+		Version:        params.Version,
+		Algorithm:      pb.KDFAlgorithm(params.Algorithm),
+		Memory:         uint64(params.Memory),
+		Iterations:     params.Iterations,
+		Parallelism:    uint32(params.Parallelism),
+		SaltDerivation: pb.SaltDerivation(params.SaltDerivation),
+	}
+}
+
+// kdfParametersFromProto converts the parameters without validating them,
+// such that grants with parameters from newer versions can still be parsed.
+func kdfParametersFromProto(p *pb.KDFParameters) (*encryption.KDFParameters, error) {
+	if p == nil {
+		return nil, nil
+	}
+	if p.Parallelism > math.MaxUint8 || p.Memory > math.MaxInt64 ||
+		p.Algorithm < 0 || p.Algorithm > math.MaxUint8 ||
+		p.SaltDerivation < 0 || p.SaltDerivation > math.MaxUint8 {
+		return nil, errors.New("invalid kdf parameters in encryption access")
+	}
+	return &encryption.KDFParameters{
+		Version:        p.Version,
+		Algorithm:      encryption.KDFAlgorithm(p.Algorithm),
+		Memory:         memory.Size(p.Memory),
+		Iterations:     p.Iterations,
+		Parallelism:    uint8(p.Parallelism),
+		SaltDerivation: encryption.SaltDerivation(p.SaltDerivation),
 	}, nil
 }
 
+func keyVersionsToProto(keys []encryption.KeyVersion) []*pb.EncryptionAccess_KeyVersion {
+	var versions []*pb.EncryptionAccess_KeyVersion
+	for _, key := range keys {
+		versions = append(versions, &pb.EncryptionAccess_KeyVersion{
+			Version: key.Version,
+			Key:     key.Key[:],
+		})
+	}
+	return versions
+}
+
+// keyVersionsFromProto returns the active key followed by the previous keys.
+func keyVersionsFromProto(key []byte, version uint32, previous []*pb.EncryptionAccess_KeyVersion) ([]encryption.KeyVersion, error) {
+	keys := make([]encryption.KeyVersion, 0, 1+len(previous))
+	add := func(key []byte, version uint32) error {
+		if len(key) != len(storj.Key{}) {
+			return errors.New("invalid key")
+		}
+		keys = append(keys, encryption.KeyVersion{Version: version, Key: storj.Key(key)})
+		return nil
+	}
+
+	if err := add(key, version); err != nil {
+		return nil, err
+	}
+	for _, p := range previous {
+		if err := add(p.Key, p.Version); err != nil {
+			return nil, err
+		}
+	}
+	return keys, nil
+}
+
 func parseEncryptionAccessFromProto(p *pb.EncryptionAccess) (*EncryptionAccess, error) {
 	access := NewEncryptionAccess()
 	if len(p.DefaultKey) > 0 {
-		if len(p.DefaultKey) != len(storj.Key{}) {
+		keys, err := keyVersionsFromProto(p.DefaultKey, p.DefaultKeyVersion, p.PreviousDefaultKeys)
+		if err != nil {
 			return nil, errors.New("invalid default key in encryption access")
 		}
-		var defaultKey storj.Key
-		copy(defaultKey[:], p.DefaultKey)
-		access.SetDefaultKey(&defaultKey)
+		if err := access.Store.SetDefaultKeyVersions(keys...); err != nil {
+			return nil, fmt.Errorf("invalid default key in encryption access: %w", err)
+		}
 	}
 
 	access.SetDefaultPathCipher(storj.CipherSuite(p.DefaultPathCipher))
@@ -308,19 +464,24 @@ func parseEncryptionAccessFromProto(p *pb.EncryptionAccess) (*EncryptionAccess,
 		access.SetDefaultPathCipher(storj.EncAESGCM)
 	}
 
+	kdfParameters, err := kdfParametersFromProto(p.KdfParameters)
+	if err != nil {
+		return nil, err
+	}
+	access.KDFParameters = kdfParameters
+
 	for _, entry := range p.StoreEntries {
-		if len(entry.Key) != len(storj.Key{}) {
+		keys, err := keyVersionsFromProto(entry.Key, entry.KeyVersion, entry.PreviousKeys)
+		if err != nil {
 			return nil, errors.New("invalid key in encryption access entry")
 		}
-		var key storj.Key
-		copy(key[:], entry.Key)
 
-		err := access.Store.AddWithCipher(
+		err = access.Store.AddWithKeyVersions(
 			string(entry.Bucket),
 			paths.NewUnencrypted(string(entry.UnencryptedPath)),
 			paths.NewEncrypted(string(entry.EncryptedPath)),
-			key,
 			storj.CipherSuite(entry.PathCipher),
+			keys...,
 		)
 		if err != nil {
 			return nil, fmt.Errorf("invalid encryption access entry: %w", err)
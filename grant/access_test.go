@@ -10,7 +10,9 @@ import (
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 
+	"storj.io/common/encryption"
 	"storj.io/common/macaroon"
+	"storj.io/common/memory"
 	"storj.io/common/paths"
 	"storj.io/common/storj"
 	"storj.io/common/testrand"
@@ -131,3 +133,122 @@ func TestEncryptionAccessClone(t *testing.T) {
 	assert.NotSame(t, encAccess, clone)
 	assert.Equal(t, encAccess, clone)
 }
+
+func TestEncryptionAccessKeyVersions(t *testing.T) {
+	oldKey, newKey := testrand.Key(), testrand.Key()
+
+	encAccess := NewEncryptionAccessWithDefaultKey(&oldKey)
+	encAccess.SetDefaultPathCipher(storj.EncAESGCM)
+	encAccess.Store.RotateDefaultKey(newKey)
+	require.NoError(t, encAccess.Store.AddWithKeyVersions("bucket", paths.NewUnencrypted("a"), paths.NewEncrypted("b"), storj.EncAESGCM,
+		encryption.KeyVersion{Version: 3, Key: newKey}, encryption.KeyVersion{Version: 2, Key: oldKey}))
+
+	apiKey, err := macaroon.NewAPIKey(nil)
+	require.NoError(t, err)
+
+	access := &Access{
+		SatelliteAddress: "1SYXsAycDPUu4z2ZksJD5fh5nTDcH3vCFHnpcVye5XuL1NrYV@127.0.0.1:7777",
+		APIKey:           apiKey,
+		EncAccess:        encAccess,
+	}
+	serialized, err := access.Serialize()
+	require.NoError(t, err)
+
+	parsed, err := ParseAccess(serialized)
+	require.NoError(t, err)
+	require.Equal(t, encAccess.Store.GetDefaultKeyVersions(), parsed.EncAccess.Store.GetDefaultKeyVersions())
+
+	_, _, base := parsed.EncAccess.Store.LookupUnencrypted("bucket", paths.NewUnencrypted("a"))
+	require.NotNil(t, base)
+	require.Equal(t, []encryption.KeyVersion{{Version: 3, Key: newKey}, {Version: 2, Key: oldKey}}, base.CandidateKeys())
+
+	// restricted accesses keep the previous keys of the shared prefixes.
+	restricted, err := access.Restrict(Permission{AllowDownload: true}, SharePrefix{Bucket: "bucket", Prefix: "a/c"})
+	require.NoError(t, err)
+	_, _, base = restricted.EncAccess.Store.LookupUnencrypted("bucket", paths.NewUnencrypted("a/c"))
+	require.NotNil(t, base)
+	require.Len(t, base.CandidateKeys(), 2)
+}
+
+func TestEncryptionAccessKDFParameters(t *testing.T) {
+	defaultKey := testrand.Key()
+	params := encryption.LegacyKDFParameters(4)
+	params.Memory = 256 * memory.MiB
+	params.Iterations = 3
+
+	encAccess := NewEncryptionAccessWithDefaultKey(&defaultKey)
+	encAccess.SetDefaultPathCipher(storj.EncAESGCM)
+	encAccess.KDFParameters = &params
+
+	apiKey, err := macaroon.NewAPIKey(nil)
+	require.NoError(t, err)
+
+	access := &Access{
+		SatelliteAddress: "1SYXsAycDPUu4z2ZksJD5fh5nTDcH3vCFHnpcVye5XuL1NrYV@127.0.0.1:7777",
+		APIKey:           apiKey,
+		EncAccess:        encAccess,
+	}
+	serialized, err := access.Serialize()
+	require.NoError(t, err)
+
+	parsed, err := ParseAccess(serialized)
+	require.NoError(t, err)
+	require.Equal(t, &params, parsed.EncAccess.KDFParameters)
+
+	clone := parsed.EncAccess.Clone()
+	require.Equal(t, parsed.EncAccess.KDFParameters, clone.KDFParameters)
+	require.NotSame(t, parsed.EncAccess.KDFParameters, clone.KDFParameters)
+
+	// grants without parameters don't have them after parsing.
+	access.EncAccess.KDFParameters = nil
+	serialized, err = access.Serialize()
+	require.NoError(t, err)
+	parsed, err = ParseAccess(serialized)
+	require.NoError(t, err)
+	require.Nil(t, parsed.EncAccess.KDFParameters)
+}
+
+func TestEncryptionAccessWithPassphrase(t *testing.T) {
+	passphrase, salt := []byte("passphrase"), testrand.Bytes(32)
+
+	// use cheap parameters, which differ from the legacy ones.
+	params := encryption.LegacyKDFParameters(2)
+	params.Memory = memory.MiB
+	params.Iterations = 2
+
+	encAccess, err := NewEncryptionAccessWithPassphrase(passphrase, salt, params)
+	require.NoError(t, err)
+	encAccess.SetDefaultPathCipher(storj.EncAESGCM)
+
+	expected, err := encryption.DeriveRootKeyWithParameters(passphrase, salt, "", params)
+	require.NoError(t, err)
+	require.Equal(t, expected, encAccess.Store.GetDefaultKey())
+
+	apiKey, err := macaroon.NewAPIKey(nil)
+	require.NoError(t, err)
+
+	serialized, err := (&Access{
+		SatelliteAddress: "1SYXsAycDPUu4z2ZksJD5fh5nTDcH3vCFHnpcVye5XuL1NrYV@127.0.0.1:7777",
+		APIKey:           apiKey,
+		EncAccess:        encAccess,
+	}).Serialize()
+	require.NoError(t, err)
+
+	parsed, err := ParseAccess(serialized)
+	require.NoError(t, err)
+	require.Equal(t, &params, parsed.EncAccess.KDFParameters)
+
+	// the passphrase derives the same key with the parsed parameters.
+	key, err := parsed.EncAccess.DeriveKeyFromPassphrase(passphrase, salt)
+	require.NoError(t, err)
+	require.Equal(t, parsed.EncAccess.Store.GetDefaultKey(), key)
+
+	other, err := parsed.EncAccess.DeriveKeyFromPassphrase([]byte("other"), salt)
+	require.NoError(t, err)
+	require.NotEqual(t, key, other)
+
+	_, err = NewEncryptionAccessWithPassphrase(passphrase, salt, encryption.KDFParameters{})
+	require.Error(t, err)
+	_, err = NewEncryptionAccess().DeriveKeyFromPassphrase(passphrase, salt)
+	require.Error(t, err)
+}
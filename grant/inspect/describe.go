@@ -0,0 +1,498 @@
+// Copyright (C) 2026 Storj Labs, Inc.
+// See LICENSE for copying information.
+
+package inspect
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	"storj.io/common/encryption"
+	"storj.io/common/grant"
+	"storj.io/common/macaroon"
+	"storj.io/common/paths"
+	"storj.io/common/storj"
+)
+
+// Description is a human readable description of an access grant or an API key.
+type Description struct {
+	// SatelliteAddress is empty when describing an API key.
+	SatelliteAddress string                 `json:"satellite_address,omitempty"`
+	APIKey           APIKey                 `json:"api_key"`
+	Permissions      Permissions            `json:"permissions"`
+	Encryption       *EncryptionDescription `json:"encryption,omitempty"`
+}
+
+// APIKey describes the API key and the caveats in the order they were added.
+type APIKey struct {
+	// Version is the serialization format version of the macaroon.
+	Version int `json:"version"`
+	// HeadFingerprint identifies the root API key the key was derived from.
+	// The head and the tail aren't included, because together with the
+	// caveats they are enough to rebuild a working API key.
+	HeadFingerprint string   `json:"head_fingerprint"`
+	Caveats         []Caveat `json:"caveats,omitempty"`
+}
+
+// Caveat describes a single caveat of an API key.
+type Caveat struct {
+	Disallowed          []string            `json:"disallowed,omitempty"`
+	AllowedPaths        []Prefix            `json:"allowed_paths,omitempty"`
+	AllowedPathPatterns []PathPattern       `json:"allowed_path_patterns,omitempty"`
+	NotBefore           *time.Time          `json:"not_before,omitempty"`
+	NotAfter            *time.Time          `json:"not_after,omitempty"`
+	MaxObjectTTL        *time.Duration      `json:"max_object_ttl,omitempty"`
+	AllowedNetworks     []string            `json:"allowed_networks,omitempty"`
+	MaxObjectSize       int64               `json:"max_object_size,omitempty"`
+	MaxUploadSize       int64               `json:"max_upload_size,omitempty"`
+	RateLimit           *macaroon.RateLimit `json:"rate_limit,omitempty"`
+	Nonce               string              `json:"nonce,omitempty"`
+}
+
+// Prefix describes a bucket and an object key prefix.
+type Prefix struct {
+	Bucket string `json:"bucket"`
+	// EncryptedPrefix is the encrypted prefix with every path component
+	// encoded with URL safe base64.
+	EncryptedPrefix string `json:"encrypted_prefix,omitempty"`
+	// Prefix is the unencrypted prefix. It's only set when the prefix could
+	// be decrypted with the encryption access of the access grant.
+	Prefix *string `json:"prefix,omitempty"`
+}
+
+// PathPattern describes a path pattern as the literal prefix and the pattern
+// the rest of the object key has to match.
+type PathPattern struct {
+	Prefix
+	Pattern string `json:"pattern,omitempty"`
+}
+
+// String returns the path pattern formatted as bucket/prefix/pattern.
+func (pattern PathPattern) String() string {
+	prefix := pattern.Prefix.String()
+	if pattern.Pattern == "" {
+		return prefix
+	}
+	if !strings.HasSuffix(prefix, "/") {
+		prefix += "/"
+	}
+	return prefix + pattern.Pattern
+}
+
+// String returns the prefix formatted as bucket/prefix.
+func (prefix Prefix) String() string {
+	switch {
+	case prefix.Prefix != nil:
+		return prefix.Bucket + "/" + *prefix.Prefix
+	case prefix.EncryptedPrefix != "":
+		return prefix.Bucket + "/" + prefix.EncryptedPrefix + " (encrypted)"
+	default:
+		return prefix.Bucket + "/"
+	}
+}
+
+// Permissions are the effective permissions of all caveats combined.
+type Permissions struct {
+	// Allowed lists the operations that are allowed by all caveats.
+	Allowed []string `json:"allowed"`
+	// Prefixes lists the only prefixes that can be accessed. Nil means that
+	// access isn't restricted to any buckets or prefixes.
+	Prefixes  []Prefix   `json:"prefixes,omitempty"`
+	NotBefore *time.Time `json:"not_before,omitempty"`
+	NotAfter  *time.Time `json:"not_after,omitempty"`
+	// AllowedNetworks lists the network restrictions of every caveat. The
+	// client address must be within one of the networks of every entry.
+	AllowedNetworks [][]string          `json:"allowed_networks,omitempty"`
+	MaxObjectTTL    *time.Duration      `json:"max_object_ttl,omitempty"`
+	MaxObjectSize   int64               `json:"max_object_size,omitempty"`
+	MaxUploadSize   int64               `json:"max_upload_size,omitempty"`
+	RateLimit       *macaroon.RateLimit `json:"rate_limit,omitempty"`
+}
+
+// EncryptionDescription describes the encryption access of an access grant.
+// It never contains any keys.
+type EncryptionDescription struct {
+	HasDefaultKey     bool         `json:"has_default_key"`
+	DefaultPathCipher string       `json:"default_path_cipher"`
+	Entries           []StoreEntry `json:"entries,omitempty"`
+	EncryptionBypass  bool         `json:"encryption_bypass,omitempty"`
+}
+
+// StoreEntry describes a single entry in the encryption store.
+type StoreEntry struct {
+	Bucket          string `json:"bucket"`
+	UnencryptedPath string `json:"unencrypted_path"`
+	EncryptedPath   string `json:"encrypted_path"`
+	PathCipher      string `json:"path_cipher"`
+}
+
+// operations lists all operations that a caveat can disallow.
+var operations = []struct {
+	name       string
+	disallowed func(cav *macaroon.Caveat) bool
+}{
+	{"read", func(cav *macaroon.Caveat) bool { return cav.DisallowReads }},
+	{"write", func(cav *macaroon.Caveat) bool { return cav.DisallowWrites }},
+	{"list", func(cav *macaroon.Caveat) bool { return cav.DisallowLists }},
+	{"delete", func(cav *macaroon.Caveat) bool { return cav.DisallowDeletes }},
+	{"lock", func(cav *macaroon.Caveat) bool { return cav.DisallowLocks }},
+	{"put_retention", func(cav *macaroon.Caveat) bool { return cav.DisallowPutRetention }},
+	{"get_retention", func(cav *macaroon.Caveat) bool { return cav.DisallowGetRetention }},
+	{"put_legal_hold", func(cav *macaroon.Caveat) bool { return cav.DisallowPutLegalHold }},
+	{"get_legal_hold", func(cav *macaroon.Caveat) bool { return cav.DisallowGetLegalHold }},
+	{"bypass_governance_retention", func(cav *macaroon.Caveat) bool { return cav.DisallowBypassGovernanceRetention }},
+	{"put_bucket_object_lock_configuration", func(cav *macaroon.Caveat) bool { return cav.DisallowPutBucketObjectLockConfiguration }},
+	{"get_bucket_object_lock_configuration", func(cav *macaroon.Caveat) bool { return cav.DisallowGetBucketObjectLockConfiguration }},
+	{"put_bucket_notification_configuration", func(cav *macaroon.Caveat) bool { return cav.DisallowPutBucketNotificationConfiguration }},
+	{"get_bucket_notification_configuration", func(cav *macaroon.Caveat) bool { return cav.DisallowGetBucketNotificationConfiguration }},
+}
+
+// Parse parses a serialized access grant or API key and describes it.
+func Parse(serialized string) (*Description, error) {
+	serialized = strings.TrimSpace(serialized)
+
+	access, accessErr := grant.ParseAccess(serialized)
+	if accessErr == nil {
+		return DescribeAccess(access)
+	}
+
+	apiKey, apiKeyErr := macaroon.ParseAPIKey(serialized)
+	if apiKeyErr == nil {
+		return DescribeAPIKey(apiKey)
+	}
+
+	return nil, fmt.Errorf("not an access grant (%w) nor an api key (%w)", accessErr, apiKeyErr)
+}
+
+// DescribeAccess describes an access grant.
+func DescribeAccess(access *grant.Access) (*Description, error) {
+	if access.APIKey == nil {
+		return nil, errors.New("access grant is missing api key")
+	}
+
+	var store *encryption.Store
+	if access.EncAccess != nil {
+		store = access.EncAccess.Store
+	}
+
+	desc, err := describe(access.APIKey, store)
+	if err != nil {
+		return nil, err
+	}
+	desc.SatelliteAddress = access.SatelliteAddress
+
+	if store != nil {
+		desc.Encryption, err = describeStore(store)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return desc, nil
+}
+
+// DescribeAPIKey describes an API key.
+func DescribeAPIKey(apiKey *macaroon.APIKey) (*Description, error) {
+	return describe(apiKey, nil)
+}
+
+func describe(apiKey *macaroon.APIKey, store *encryption.Store) (*Description, error) {
+	raw := apiKey.SerializeRaw()
+	mac, err := macaroon.ParseMacaroon(raw)
+	if err != nil {
+		return nil, err
+	}
+
+	desc := &Description{
+		APIKey: APIKey{
+			Version:         int(raw[0]),
+			HeadFingerprint: headFingerprint(mac.Head()),
+		},
+	}
+
+	var caveats []*macaroon.Caveat
+	for i, data := range mac.Caveats() {
+		cav, err := macaroon.ParseCaveat(data)
+		if err != nil {
+			return nil, fmt.Errorf("invalid caveat %d: %w", i, err)
+		}
+		caveats = append(caveats, cav)
+		desc.APIKey.Caveats = append(desc.APIKey.Caveats, describeCaveat(cav, store))
+	}
+
+	desc.Permissions, err = effectivePermissions(apiKey, caveats, store)
+	if err != nil {
+		return nil, err
+	}
+
+	return desc, nil
+}
+
+// headFingerprint returns a short hash of the head, which can't be used to
+// recover the head.
+func headFingerprint(head []byte) string {
+	sum := sha256.Sum256(head)
+	return hex.EncodeToString(sum[:8])
+}
+
+func describeCaveat(cav *macaroon.Caveat, store *encryption.Store) Caveat {
+	desc := Caveat{
+		NotBefore:       cav.NotBefore,
+		NotAfter:        cav.NotAfter,
+		MaxObjectTTL:    cav.MaxObjectTtl,
+		AllowedNetworks: cav.AllowedNetworks,
+		MaxObjectSize:   cav.MaxObjectSize,
+		MaxUploadSize:   cav.MaxUploadSize,
+		Nonce:           hex.EncodeToString(cav.Nonce),
+	}
+	for _, op := range operations {
+		if op.disallowed(cav) {
+			desc.Disallowed = append(desc.Disallowed, op.name)
+		}
+	}
+	for _, path := range cav.AllowedPaths {
+		desc.AllowedPaths = append(desc.AllowedPaths, describePrefix(path, store))
+	}
+	for _, pattern := range cav.AllowedPathPatterns {
+		prefix, rest := pattern.SplitPrefix()
+		desc.AllowedPathPatterns = append(desc.AllowedPathPatterns, PathPattern{
+			Prefix: describePrefix(&macaroon.Caveat_Path{
+				Bucket:              pattern.Bucket,
+				EncryptedPathPrefix: prefix,
+			}, store),
+			Pattern: string(rest),
+		})
+	}
+	if cav.RateLimit != nil {
+		desc.RateLimit = &macaroon.RateLimit{Requests: cav.RateLimit.Requests}
+		if cav.RateLimit.Period != nil {
+			desc.RateLimit.Period = *cav.RateLimit.Period
+		}
+	}
+	return desc
+}
+
+// effectivePermissions combines the caveats the same way as APIKey.Check.
+func effectivePermissions(apiKey *macaroon.APIKey, caveats []*macaroon.Caveat, store *encryption.Store) (perms Permissions, err error) {
+	for _, op := range operations {
+		allowed := true
+		for _, cav := range caveats {
+			if op.disallowed(cav) {
+				allowed = false
+				break
+			}
+		}
+		if allowed {
+			perms.Allowed = append(perms.Allowed, op.name)
+		}
+	}
+
+	var groups [][]*macaroon.Caveat_Path
+	for _, cav := range caveats {
+		if cav.NotBefore != nil && (perms.NotBefore == nil || cav.NotBefore.After(*perms.NotBefore)) {
+			perms.NotBefore = cav.NotBefore
+		}
+		if cav.NotAfter != nil && (perms.NotAfter == nil || cav.NotAfter.Before(*perms.NotAfter)) {
+			perms.NotAfter = cav.NotAfter
+		}
+		if len(cav.AllowedNetworks) > 0 {
+			perms.AllowedNetworks = append(perms.AllowedNetworks, cav.AllowedNetworks)
+		}
+		if len(cav.AllowedPaths) > 0 {
+			groups = append(groups, cav.AllowedPaths)
+		}
+	}
+
+	ctx := context.Background()
+	if perms.MaxObjectTTL, err = apiKey.GetMaxObjectTTL(ctx); err != nil {
+		return Permissions{}, err
+	}
+	if perms.MaxObjectSize, err = apiKey.GetMaxObjectSize(ctx); err != nil {
+		return Permissions{}, err
+	}
+	if perms.MaxUploadSize, err = apiKey.GetMaxUploadSize(ctx); err != nil {
+		return Permissions{}, err
+	}
+	if perms.RateLimit, err = apiKey.GetRateLimit(ctx); err != nil {
+		return Permissions{}, err
+	}
+
+	if len(groups) > 0 {
+		perms.Prefixes = []Prefix{}
+		for _, path := range collapsePrefixes(groups) {
+			perms.Prefixes = append(perms.Prefixes, describePrefix(path, store))
+		}
+	}
+
+	return perms, nil
+}
+
+// collapsePrefixes returns the prefixes that are allowed by every group.
+func collapsePrefixes(groups [][]*macaroon.Caveat_Path) []*macaroon.Caveat_Path {
+	allowedByGroup := func(path *macaroon.Caveat_Path, group []*macaroon.Caveat_Path) bool {
+		for _, other := range group {
+			if string(path.Bucket) == string(other.Bucket) &&
+				strings.HasPrefix(string(path.EncryptedPathPrefix), string(other.EncryptedPathPrefix)) {
+				return true
+			}
+		}
+		return false
+	}
+
+	var prefixes []*macaroon.Caveat_Path
+	seen := map[string]bool{}
+next:
+	for _, group := range groups {
+		for _, path := range group {
+			for _, other := range groups {
+				if !allowedByGroup(path, other) {
+					continue next
+				}
+			}
+			key := string(path.Bucket) + "\x00" + string(path.EncryptedPathPrefix)
+			if !seen[key] {
+				seen[key] = true
+				prefixes = append(prefixes, path)
+			}
+		}
+	}
+	return prefixes
+}
+
+func describePrefix(path *macaroon.Caveat_Path, store *encryption.Store) Prefix {
+	prefix := Prefix{
+		Bucket:          string(path.Bucket),
+		EncryptedPrefix: encodeEncryptedPath(string(path.EncryptedPathPrefix)),
+	}
+	if store != nil {
+		unenc, err := encryption.DecryptPathWithStoreCipher(prefix.Bucket, paths.NewEncrypted(string(path.EncryptedPathPrefix)), store)
+		if err == nil {
+			raw := unenc.Raw()
+			prefix.Prefix = &raw
+		}
+	}
+	return prefix
+}
+
+// encodeEncryptedPath encodes every component of the encrypted path with URL safe base64.
+func encodeEncryptedPath(raw string) string {
+	if raw == "" {
+		return ""
+	}
+	encoded, err := encryption.DecryptPathRaw(raw, storj.EncNullBase64URL, &storj.Key{})
+	if err != nil {
+		return hex.EncodeToString([]byte(raw))
+	}
+	return encoded
+}
+
+func describeStore(store *encryption.Store) (*EncryptionDescription, error) {
+	desc := &EncryptionDescription{
+		HasDefaultKey:     store.GetDefaultKey() != nil,
+		DefaultPathCipher: store.GetDefaultPathCipher().String(),
+		EncryptionBypass:  store.EncryptionBypass,
+	}
+
+	err := store.IterateWithCipher(func(bucket string, unenc paths.Unencrypted, enc paths.Encrypted, _ storj.Key, pathCipher storj.CipherSuite) error {
+		desc.Entries = append(desc.Entries, StoreEntry{
+			Bucket:          bucket,
+			UnencryptedPath: unenc.Raw(),
+			EncryptedPath:   encodeEncryptedPath(enc.Raw()),
+			PathCipher:      pathCipher.String(),
+		})
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	slices.SortFunc(desc.Entries, func(a, b StoreEntry) int {
+		return strings.Compare(a.Bucket+"/"+a.UnencryptedPath, b.Bucket+"/"+b.UnencryptedPath)
+	})
+
+	return desc, nil
+}
+
+// String returns the description formatted as indented text.
+func (desc *Description) String() string {
+	var b strings.Builder
+	line := func(indent int, format string, args ...any) {
+		b.WriteString(strings.Repeat("  ", indent))
+		fmt.Fprintf(&b, format, args...)
+		b.WriteByte('\n')
+	}
+
+	if desc.SatelliteAddress != "" {
+		line(0, "satellite: %s", desc.SatelliteAddress)
+	}
+	line(0, "api key: version %d, head fingerprint %s", desc.APIKey.Version, desc.APIKey.HeadFingerprint)
+	for i, cav := range desc.APIKey.Caveats {
+		line(1, "caveat %d:", i)
+		if len(cav.Disallowed) > 0 {
+			line(2, "disallowed: %s", strings.Join(cav.Disallowed, ", "))
+		}
+		if len(cav.AllowedPaths) > 0 {
+			line(2, "allowed paths: %s", formatPrefixes(cav.AllowedPaths))
+		}
+		if len(cav.AllowedPathPatterns) > 0 {
+			patterns := make([]string, len(cav.AllowedPathPatterns))
+			for i, pattern := range cav.AllowedPathPatterns {
+				patterns[i] = pattern.String()
+			}
+			line(2, "allowed path patterns: %s", strings.Join(patterns, ", "))
+		}
+		if cav.NotBefore != nil {
+			line(2, "not before: %s", formatTime(cav.NotBefore))
+		}
+		if cav.NotAfter != nil {
+			line(2, "not after: %s", formatTime(cav.NotAfter))
+		}
+		if cav.MaxObjectTTL != nil {
+			line(2, "max object ttl: %s", formatDuration(cav.MaxObjectTTL))
+		}
+		if len(cav.AllowedNetworks) > 0 {
+			line(2, "allowed networks: %s", strings.Join(cav.AllowedNetworks, ", "))
+		}
+		if cav.MaxObjectSize != 0 {
+			line(2, "max object size: %s", formatSize(cav.MaxObjectSize))
+		}
+		if cav.MaxUploadSize != 0 {
+			line(2, "max upload size: %s", formatSize(cav.MaxUploadSize))
+		}
+		if cav.RateLimit != nil {
+			line(2, "rate limit: %s", formatRateLimit(cav.RateLimit))
+		}
+	}
+
+	perms := desc.Permissions
+	line(0, "permissions:")
+	line(1, "allowed: %s", strings.Join(perms.Allowed, ", "))
+	line(1, "prefixes: %s", formatPrefixes(perms.Prefixes))
+	line(1, "not before: %s", formatTime(perms.NotBefore))
+	line(1, "not after: %s", formatTime(perms.NotAfter))
+	line(1, "allowed networks: %s", formatNetworks(perms.AllowedNetworks))
+	line(1, "max object ttl: %s", formatDuration(perms.MaxObjectTTL))
+	line(1, "max object size: %s", formatSize(perms.MaxObjectSize))
+	line(1, "max upload size: %s", formatSize(perms.MaxUploadSize))
+	line(1, "rate limit: %s", formatRateLimit(perms.RateLimit))
+
+	if enc := desc.Encryption; enc != nil {
+		line(0, "encryption:")
+		line(1, "default key: %t", enc.HasDefaultKey)
+		line(1, "default path cipher: %s", enc.DefaultPathCipher)
+		if enc.EncryptionBypass {
+			line(1, "encryption bypass: true")
+		}
+		for _, entry := range enc.Entries {
+			line(1, "entry: %s/%s => %s (%s)", entry.Bucket, entry.UnencryptedPath, entry.EncryptedPath, entry.PathCipher)
+		}
+	}
+
+	return b.String()
+}
@@ -0,0 +1,332 @@
+// Copyright (C) 2026 Storj Labs, Inc.
+// See LICENSE for copying information.
+
+package inspect
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	"storj.io/common/macaroon"
+)
+
+// Restriction tells how a difference changes what can be done with a grant.
+type Restriction int
+
+const (
+	// Unrelated differences don't change what can be done, e.g. a different
+	// satellite address or a different encryption store entry.
+	Unrelated Restriction = iota
+	// MoreRestrictive differences allow less than before.
+	MoreRestrictive
+	// LessRestrictive differences allow more than before.
+	LessRestrictive
+)
+
+// String returns the name of the restriction.
+func (r Restriction) String() string {
+	switch r {
+	case MoreRestrictive:
+		return "more restrictive"
+	case LessRestrictive:
+		return "less restrictive"
+	default:
+		return "unrelated"
+	}
+}
+
+// MarshalText marshals the restriction as its name.
+func (r Restriction) MarshalText() ([]byte, error) {
+	return []byte(r.String()), nil
+}
+
+// Difference is a single difference between two descriptions.
+type Difference struct {
+	Field       string      `json:"field"`
+	From        string      `json:"from"`
+	To          string      `json:"to"`
+	Restriction Restriction `json:"restriction"`
+}
+
+// String returns the difference formatted as a single line.
+func (d Difference) String() string {
+	s := fmt.Sprintf("%s: %s -> %s", d.Field, d.From, d.To)
+	if d.Restriction != Unrelated {
+		s += " (" + d.Restriction.String() + ")"
+	}
+	return s
+}
+
+// Diff returns the differences between the effective permissions and the
+// encryption access of two descriptions, explaining why one is more
+// restrictive than the other.
+func Diff(from, to *Description) []Difference {
+	var diffs []Difference
+	add := func(field, fromValue, toValue string, restriction Restriction) {
+		if fromValue != toValue {
+			diffs = append(diffs, Difference{
+				Field:       field,
+				From:        fromValue,
+				To:          toValue,
+				Restriction: restriction,
+			})
+		}
+	}
+
+	add("satellite address", formatString(from.SatelliteAddress), formatString(to.SatelliteAddress), Unrelated)
+	add("api key head fingerprint", from.APIKey.HeadFingerprint, to.APIKey.HeadFingerprint, Unrelated)
+
+	fp, tp := from.Permissions, to.Permissions
+	for _, op := range operations {
+		fromAllowed := slices.Contains(fp.Allowed, op.name)
+		toAllowed := slices.Contains(tp.Allowed, op.name)
+		add("allow "+op.name, fmt.Sprint(fromAllowed), fmt.Sprint(toAllowed), restrictionOf(fromAllowed, toAllowed))
+	}
+
+	diffs = append(diffs, diffPrefixes(fp.Prefixes, tp.Prefixes)...)
+
+	add("not before", formatTime(fp.NotBefore), formatTime(tp.NotBefore),
+		restrictionOf(isLater(tp.NotBefore, fp.NotBefore, false), isLater(fp.NotBefore, tp.NotBefore, false)))
+	add("not after", formatTime(fp.NotAfter), formatTime(tp.NotAfter),
+		restrictionOf(isLater(fp.NotAfter, tp.NotAfter, true), isLater(tp.NotAfter, fp.NotAfter, true)))
+
+	add("allowed networks", formatNetworks(fp.AllowedNetworks), formatNetworks(tp.AllowedNetworks),
+		networksRestriction(fp.AllowedNetworks, tp.AllowedNetworks))
+
+	add("max object ttl", formatDuration(fp.MaxObjectTTL), formatDuration(tp.MaxObjectTTL),
+		restrictionOf(isSmaller(limitOfDuration(tp.MaxObjectTTL), limitOfDuration(fp.MaxObjectTTL)),
+			isSmaller(limitOfDuration(fp.MaxObjectTTL), limitOfDuration(tp.MaxObjectTTL))))
+	add("max object size", formatSize(fp.MaxObjectSize), formatSize(tp.MaxObjectSize),
+		restrictionOf(isSmaller(tp.MaxObjectSize, fp.MaxObjectSize), isSmaller(fp.MaxObjectSize, tp.MaxObjectSize)))
+	add("max upload size", formatSize(fp.MaxUploadSize), formatSize(tp.MaxUploadSize),
+		restrictionOf(isSmaller(tp.MaxUploadSize, fp.MaxUploadSize), isSmaller(fp.MaxUploadSize, tp.MaxUploadSize)))
+	add("rate limit", formatRateLimit(fp.RateLimit), formatRateLimit(tp.RateLimit),
+		restrictionOf(isSlower(tp.RateLimit, fp.RateLimit), isSlower(fp.RateLimit, tp.RateLimit)))
+
+	diffs = append(diffs, diffEncryption(from.Encryption, to.Encryption)...)
+
+	return diffs
+}
+
+// restrictionOf returns MoreRestrictive when only the source allows more than
+// the target, and LessRestrictive when only the target allows more.
+func restrictionOf(fromWider, toWider bool) Restriction {
+	switch {
+	case fromWider && !toWider:
+		return MoreRestrictive
+	case toWider && !fromWider:
+		return LessRestrictive
+	default:
+		return Unrelated
+	}
+}
+
+func diffPrefixes(from, to []Prefix) []Difference {
+	// the prefixes are compared by their encrypted form, because they can't
+	// be decrypted without the encryption access.
+	if (from == nil) == (to == nil) && prefixesWithin(from, to) && prefixesWithin(to, from) {
+		return nil
+	}
+
+	var restriction Restriction
+	switch {
+	case from == nil:
+		restriction = MoreRestrictive
+	case to == nil:
+		restriction = LessRestrictive
+	default:
+		restriction = restrictionOf(prefixesWithin(to, from), prefixesWithin(from, to))
+	}
+
+	return []Difference{{
+		Field:       "prefixes",
+		From:        formatPrefixes(from),
+		To:          formatPrefixes(to),
+		Restriction: restriction,
+	}}
+}
+
+// prefixesWithin returns true if all prefixes are within one of the outer prefixes.
+func prefixesWithin(prefixes, outer []Prefix) bool {
+	for _, prefix := range prefixes {
+		within := false
+		for _, other := range outer {
+			if prefix.Bucket == other.Bucket && strings.HasPrefix(prefix.EncryptedPrefix, other.EncryptedPrefix) {
+				within = true
+				break
+			}
+		}
+		if !within {
+			return false
+		}
+	}
+	return true
+}
+
+// networksRestriction only tells whether network restrictions were added or
+// removed, because comparing sets of networks isn't worth the complexity.
+func networksRestriction(from, to [][]string) Restriction {
+	switch {
+	case len(from) == 0:
+		return MoreRestrictive
+	case len(to) == 0:
+		return LessRestrictive
+	default:
+		return Unrelated
+	}
+}
+
+func diffEncryption(from, to *EncryptionDescription) []Difference {
+	if from == nil || to == nil {
+		return nil
+	}
+
+	var diffs []Difference
+	if from.HasDefaultKey != to.HasDefaultKey {
+		diffs = append(diffs, Difference{
+			Field:       "default key",
+			From:        fmt.Sprint(from.HasDefaultKey),
+			To:          fmt.Sprint(to.HasDefaultKey),
+			Restriction: restrictionOf(from.HasDefaultKey, to.HasDefaultKey),
+		})
+	}
+	if from.DefaultPathCipher != to.DefaultPathCipher {
+		diffs = append(diffs, Difference{
+			Field: "default path cipher",
+			From:  from.DefaultPathCipher,
+			To:    to.DefaultPathCipher,
+		})
+	}
+
+	format := func(entry StoreEntry) string {
+		return entry.Bucket + "/" + entry.UnencryptedPath + " (" + entry.PathCipher + ")"
+	}
+	fromEntries, toEntries := map[string]bool{}, map[string]bool{}
+	for _, entry := range from.Entries {
+		fromEntries[format(entry)] = true
+	}
+	for _, entry := range to.Entries {
+		toEntries[format(entry)] = true
+	}
+	for _, entry := range from.Entries {
+		if !toEntries[format(entry)] {
+			diffs = append(diffs, Difference{Field: "encryption entry", From: format(entry), To: "-"})
+		}
+	}
+	for _, entry := range to.Entries {
+		if !fromEntries[format(entry)] {
+			diffs = append(diffs, Difference{Field: "encryption entry", From: "-", To: format(entry)})
+		}
+	}
+
+	return diffs
+}
+
+// isLater compares optional times, where missing time is treated as the
+// infinite past or, if missingIsInfinite, as the infinite future.
+func isLater(a, b *time.Time, missingIsInfinite bool) bool {
+	switch {
+	case a == nil && b == nil:
+		return false
+	case a == nil:
+		return missingIsInfinite
+	case b == nil:
+		return !missingIsInfinite
+	default:
+		return a.After(*b)
+	}
+}
+
+// isSmaller compares limits, where zero means no limit.
+func isSmaller(a, b int64) bool {
+	switch {
+	case a == 0:
+		return false
+	case b == 0:
+		return true
+	default:
+		return a < b
+	}
+}
+
+func limitOfDuration(d *time.Duration) int64 {
+	if d == nil {
+		return 0
+	}
+	return int64(*d)
+}
+
+// isSlower returns true when a allows fewer requests than b.
+func isSlower(a, b *macaroon.RateLimit) bool {
+	switch {
+	case a == nil:
+		return false
+	case b == nil:
+		return true
+	default:
+		return a.PerSecond() < b.PerSecond()
+	}
+}
+
+func formatString(s string) string {
+	if s == "" {
+		return "-"
+	}
+	return s
+}
+
+func formatTime(t *time.Time) string {
+	if t == nil {
+		return "-"
+	}
+	return t.UTC().Format(time.RFC3339)
+}
+
+func formatDuration(d *time.Duration) string {
+	if d == nil {
+		return "-"
+	}
+	return d.String()
+}
+
+func formatSize(size int64) string {
+	if size == 0 {
+		return "-"
+	}
+	return fmt.Sprint(size)
+}
+
+func formatRateLimit(limit *macaroon.RateLimit) string {
+	if limit == nil {
+		return "-"
+	}
+	return fmt.Sprintf("%d per %s", limit.Requests, limit.Period)
+}
+
+func formatNetworks(networks [][]string) string {
+	if len(networks) == 0 {
+		return "-"
+	}
+	groups := make([]string, len(networks))
+	for i, group := range networks {
+		groups[i] = "[" + strings.Join(group, ", ") + "]"
+	}
+	return strings.Join(groups, " and ")
+}
+
+func formatPrefixes(prefixes []Prefix) string {
+	if prefixes == nil {
+		return "all"
+	}
+	if len(prefixes) == 0 {
+		return "none"
+	}
+	formatted := make([]string, len(prefixes))
+	for i, prefix := range prefixes {
+		formatted[i] = prefix.String()
+	}
+	slices.Sort(formatted)
+	return strings.Join(formatted, ", ")
+}
@@ -0,0 +1,6 @@
+// Copyright (C) 2026 Storj Labs, Inc.
+// See LICENSE for copying information.
+
+// Package inspect describes serialized access grants and API keys in a human
+// readable form and explains the differences between them.
+package inspect
@@ -0,0 +1,180 @@
+// Copyright (C) 2026 Storj Labs, Inc.
+// See LICENSE for copying information.
+
+package inspect_test
+
+import (
+	"encoding/hex"
+	"encoding/json"
+	"net/netip"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"storj.io/common/grant"
+	"storj.io/common/grant/inspect"
+	"storj.io/common/macaroon"
+	"storj.io/common/storj"
+	"storj.io/common/testrand"
+)
+
+func newAccess(t *testing.T) *grant.Access {
+	secret, err := macaroon.NewSecret()
+	require.NoError(t, err)
+
+	apiKey, err := macaroon.NewAPIKey(secret)
+	require.NoError(t, err)
+
+	defaultKey := testrand.Key()
+	encAccess := grant.NewEncryptionAccessWithDefaultKey(&defaultKey)
+	encAccess.SetDefaultPathCipher(storj.EncAESGCM)
+
+	return &grant.Access{
+		SatelliteAddress: "1SYXsAycDPUu4z2ZksJD5fh5nTDcH3vCFHnpcVye5XuL1NrYV@127.0.0.1:7777",
+		APIKey:           apiKey,
+		EncAccess:        encAccess,
+	}
+}
+
+func TestParse(t *testing.T) {
+	access := newAccess(t)
+
+	notAfter := time.Date(2030, 1, 1, 0, 0, 0, 0, time.UTC)
+	restricted, err := access.Restrict(grant.Permission{
+		AllowDownload: true,
+		AllowList:     true,
+		NotAfter:      notAfter,
+		MaxObjectSize: 1000,
+	}, grant.SharePrefix{Bucket: "bucket", Prefix: "photos/"})
+	require.NoError(t, err)
+	restricted, err = restricted.RestrictNetworks(netip.MustParsePrefix("192.0.2.0/24"))
+	require.NoError(t, err)
+
+	serialized, err := restricted.Serialize()
+	require.NoError(t, err)
+
+	desc, err := inspect.Parse(serialized)
+	require.NoError(t, err)
+
+	require.Equal(t, access.SatelliteAddress, desc.SatelliteAddress)
+	require.Equal(t, 2, desc.APIKey.Version)
+	require.Len(t, desc.APIKey.Caveats, 2)
+	require.Contains(t, desc.APIKey.Caveats[0].Disallowed, "write")
+	require.Equal(t, []string{"192.0.2.0/24"}, desc.APIKey.Caveats[1].AllowedNetworks)
+
+	perms := desc.Permissions
+	require.Equal(t, []string{"read", "list"}, perms.Allowed)
+	require.Len(t, perms.Prefixes, 1)
+	require.Equal(t, "bucket", perms.Prefixes[0].Bucket)
+	require.NotNil(t, perms.Prefixes[0].Prefix)
+	require.Equal(t, "photos", *perms.Prefixes[0].Prefix)
+	require.NotEmpty(t, perms.Prefixes[0].EncryptedPrefix)
+	require.Equal(t, notAfter, perms.NotAfter.UTC())
+	require.Nil(t, perms.NotBefore)
+	require.Equal(t, [][]string{{"192.0.2.0/24"}}, perms.AllowedNetworks)
+	require.EqualValues(t, 1000, perms.MaxObjectSize)
+
+	require.NotNil(t, desc.Encryption)
+	require.False(t, desc.Encryption.HasDefaultKey)
+	require.Len(t, desc.Encryption.Entries, 1)
+	require.Equal(t, "photos", desc.Encryption.Entries[0].UnencryptedPath)
+
+	// the description must never contain keys.
+	data, err := json.Marshal(desc)
+	require.NoError(t, err)
+	require.NotContains(t, string(data), "key\":\"")
+
+	// nor the head or the tail of the api key, which rebuild a working key.
+	mac, err := macaroon.ParseMacaroon(restricted.APIKey.SerializeRaw())
+	require.NoError(t, err)
+	for _, secret := range [][]byte{mac.Head(), mac.Tail()} {
+		require.NotContains(t, string(data), hex.EncodeToString(secret))
+		require.NotContains(t, desc.String(), hex.EncodeToString(secret))
+	}
+	require.Len(t, desc.APIKey.HeadFingerprint, 16)
+
+	require.Contains(t, desc.String(), "prefixes: bucket/photos")
+
+	// api keys can be described without the encryption access.
+	keyDesc, err := inspect.Parse(restricted.APIKey.Serialize())
+	require.NoError(t, err)
+	require.Empty(t, keyDesc.SatelliteAddress)
+	require.Nil(t, keyDesc.Encryption)
+	require.Equal(t, desc.APIKey.HeadFingerprint, keyDesc.APIKey.HeadFingerprint)
+	require.Equal(t, desc.Permissions.Prefixes[0].EncryptedPrefix, keyDesc.Permissions.Prefixes[0].EncryptedPrefix)
+	require.Nil(t, keyDesc.Permissions.Prefixes[0].Prefix)
+
+	_, err = inspect.Parse("invalid")
+	require.Error(t, err)
+}
+
+func TestParse_Unrestricted(t *testing.T) {
+	access := newAccess(t)
+
+	serialized, err := access.Serialize()
+	require.NoError(t, err)
+
+	desc, err := inspect.Parse(serialized)
+	require.NoError(t, err)
+	require.Empty(t, desc.APIKey.Caveats)
+	require.Nil(t, desc.Permissions.Prefixes)
+	require.Contains(t, desc.Permissions.Allowed, "write")
+	require.True(t, desc.Encryption.HasDefaultKey)
+}
+
+func TestParse_PathPatterns(t *testing.T) {
+	access := newAccess(t)
+
+	restricted, err := access.Restrict(grant.Permission{AllowDownload: true},
+		grant.SharePrefix{Bucket: "bucket", Prefix: "photos/", Pattern: "2024/*"})
+	require.NoError(t, err)
+
+	desc, err := inspect.DescribeAccess(restricted)
+	require.NoError(t, err)
+
+	require.Len(t, desc.APIKey.Caveats, 1)
+	patterns := desc.APIKey.Caveats[0].AllowedPathPatterns
+	require.Len(t, patterns, 1)
+	require.NotNil(t, patterns[0].Prefix.Prefix)
+	require.Equal(t, "photos/2024", *patterns[0].Prefix.Prefix)
+	require.Equal(t, "*", patterns[0].Pattern)
+	require.Contains(t, desc.String(), "allowed path patterns: bucket/photos/2024/*")
+}
+
+func TestDiff(t *testing.T) {
+	access := newAccess(t)
+
+	from, err := inspect.DescribeAccess(access)
+	require.NoError(t, err)
+	require.Empty(t, inspect.Diff(from, from))
+
+	restricted, err := access.Restrict(grant.Permission{
+		AllowDownload: true,
+		AllowUpload:   true,
+		RateLimit:     &macaroon.RateLimit{Requests: 10, Period: time.Second},
+	}, grant.SharePrefix{Bucket: "bucket"})
+	require.NoError(t, err)
+
+	to, err := inspect.DescribeAccess(restricted)
+	require.NoError(t, err)
+
+	restrictions := map[string]inspect.Restriction{}
+	for _, diff := range inspect.Diff(from, to) {
+		restrictions[diff.Field] = diff.Restriction
+	}
+	require.Equal(t, inspect.MoreRestrictive, restrictions["allow list"])
+	require.Equal(t, inspect.MoreRestrictive, restrictions["allow delete"])
+	require.Equal(t, inspect.MoreRestrictive, restrictions["prefixes"])
+	require.Equal(t, inspect.MoreRestrictive, restrictions["rate limit"])
+	require.Equal(t, inspect.MoreRestrictive, restrictions["default key"])
+	require.NotContains(t, restrictions, "allow read")
+	require.NotContains(t, restrictions, "satellite address")
+
+	for _, diff := range inspect.Diff(to, from) {
+		if diff.Field == "encryption entry" {
+			continue
+		}
+		require.Equal(t, inspect.LessRestrictive, diff.Restriction, diff.String())
+	}
+}
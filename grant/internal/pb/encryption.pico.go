@@ -15,10 +15,12 @@ import (
 type CipherSuite int32
 
 const (
-	CipherSuite_ENC_UNSPECIFIED CipherSuite = 0
-	CipherSuite_ENC_NULL        CipherSuite = 1
-	CipherSuite_ENC_AESGCM      CipherSuite = 2
-	CipherSuite_ENC_SECRETBOX   CipherSuite = 3
+	CipherSuite_ENC_UNSPECIFIED              CipherSuite = 0
+	CipherSuite_ENC_NULL                     CipherSuite = 1
+	CipherSuite_ENC_AESGCM                   CipherSuite = 2
+	CipherSuite_ENC_SECRETBOX                CipherSuite = 3
+	CipherSuite_ENC_XCHACHA20POLY1305_STREAM CipherSuite = 5
+	CipherSuite_ENC_PATH_ORDER_PRESERVING    CipherSuite = 6
 )
 
 func (m CipherSuite) String() string {
@@ -31,11 +33,51 @@ func (m CipherSuite) String() string {
 		return "ENC_AESGCM"
 	case CipherSuite_ENC_SECRETBOX:
 		return "ENC_SECRETBOX"
+	case CipherSuite_ENC_XCHACHA20POLY1305_STREAM:
+		return "ENC_XCHACHA20POLY1305_STREAM"
+	case CipherSuite_ENC_PATH_ORDER_PRESERVING:
+		return "ENC_PATH_ORDER_PRESERVING"
 	default:
 		return "CipherSuite(" + strconv.Itoa(int(m)) + ")"
 	}
 }
 
+type KDFAlgorithm int32
+
+const (
+	KDFAlgorithm_KDF_UNSPECIFIED KDFAlgorithm = 0
+	KDFAlgorithm_KDF_ARGON2ID    KDFAlgorithm = 1
+)
+
+func (m KDFAlgorithm) String() string {
+	switch m {
+	case KDFAlgorithm_KDF_UNSPECIFIED:
+		return "KDF_UNSPECIFIED"
+	case KDFAlgorithm_KDF_ARGON2ID:
+		return "KDF_ARGON2ID"
+	default:
+		return "KDFAlgorithm(" + strconv.Itoa(int(m)) + ")"
+	}
+}
+
+type SaltDerivation int32
+
+const (
+	SaltDerivation_SALT_UNSPECIFIED SaltDerivation = 0
+	SaltDerivation_SALT_HMAC_SHA256 SaltDerivation = 1
+)
+
+func (m SaltDerivation) String() string {
+	switch m {
+	case SaltDerivation_SALT_UNSPECIFIED:
+		return "SALT_UNSPECIFIED"
+	case SaltDerivation_SALT_HMAC_SHA256:
+		return "SALT_HMAC_SHA256"
+	default:
+		return "SaltDerivation(" + strconv.Itoa(int(m)) + ")"
+	}
+}
+
 type EncryptionParameters struct {
 	CipherSuite CipherSuite `json:"cipher_suite,omitempty"`
 	BlockSize   int64       `json:"block_size,omitempty"`
@@ -57,3 +99,37 @@ func (m *EncryptionParameters) Decode(c *picobuf.Decoder) {
 	c.Int32(1, (*int32)(&m.CipherSuite))
 	c.Int64(2, &m.BlockSize)
 }
+
+type KDFParameters struct {
+	Version        uint32         `json:"version,omitempty"`
+	Algorithm      KDFAlgorithm   `json:"algorithm,omitempty"`
+	Memory         uint64         `json:"memory,omitempty"`
+	Iterations     uint32         `json:"iterations,omitempty"`
+	Parallelism    uint32         `json:"parallelism,omitempty"`
+	SaltDerivation SaltDerivation `json:"salt_derivation,omitempty"`
+}
+
+func (m *KDFParameters) Encode(c *picobuf.Encoder) bool {
+	if m == nil {
+		return false
+	}
+	c.Uint32(1, &m.Version)
+	c.Int32(2, (*int32)(&m.Algorithm))
+	c.Uint64(3, &m.Memory)
+	c.Uint32(4, &m.Iterations)
+	c.Uint32(5, &m.Parallelism)
+	c.Int32(6, (*int32)(&m.SaltDerivation))
+	return true
+}
+
+func (m *KDFParameters) Decode(c *picobuf.Decoder) {
+	if m == nil {
+		return
+	}
+	c.Uint32(1, &m.Version)
+	c.Int32(2, (*int32)(&m.Algorithm))
+	c.Uint64(3, &m.Memory)
+	c.Uint32(4, &m.Iterations)
+	c.Uint32(5, &m.Parallelism)
+	c.Int32(6, (*int32)(&m.SaltDerivation))
+}
@@ -16,6 +16,9 @@ type EncryptionAccess struct {
 	StoreEntries                []*EncryptionAccess_StoreEntry `json:"store_entries,omitempty"`
 	DefaultPathCipher           CipherSuite                    `json:"default_path_cipher,omitempty"`
 	DefaultEncryptionParameters *EncryptionParameters          `json:"default_encryption_parameters,omitempty"`
+	DefaultKeyVersion           uint32                         `json:"default_key_version,omitempty"`
+	PreviousDefaultKeys         []*EncryptionAccess_KeyVersion `json:"previous_default_keys,omitempty"`
+	KdfParameters               *KDFParameters                 `json:"kdf_parameters,omitempty"`
 }
 
 func (m *EncryptionAccess) Encode(c *picobuf.Encoder) bool {
@@ -28,6 +31,11 @@ func (m *EncryptionAccess) Encode(c *picobuf.Encoder) bool {
 	}
 	c.Int32(3, (*int32)(&m.DefaultPathCipher))
 	c.Message(4, m.DefaultEncryptionParameters.Encode)
+	c.Uint32(5, &m.DefaultKeyVersion)
+	for _, x := range m.PreviousDefaultKeys {
+		c.AlwaysMessage(6, x.Encode)
+	}
+	c.Message(7, m.KdfParameters.Encode)
 	return true
 }
 
@@ -48,15 +56,29 @@ func (m *EncryptionAccess) Decode(c *picobuf.Decoder) {
 		}
 		m.DefaultEncryptionParameters.Decode(c)
 	})
+	c.Uint32(5, &m.DefaultKeyVersion)
+	c.RepeatedMessage(6, func(c *picobuf.Decoder) {
+		x := new(EncryptionAccess_KeyVersion)
+		c.Loop(x.Decode)
+		m.PreviousDefaultKeys = append(m.PreviousDefaultKeys, x)
+	})
+	c.Message(7, func(c *picobuf.Decoder) {
+		if m.KdfParameters == nil {
+			m.KdfParameters = new(KDFParameters)
+		}
+		m.KdfParameters.Decode(c)
+	})
 }
 
 type EncryptionAccess_StoreEntry struct {
-	Bucket               []byte                `json:"bucket,omitempty"`
-	UnencryptedPath      []byte                `json:"unencrypted_path,omitempty"`
-	EncryptedPath        []byte                `json:"encrypted_path,omitempty"`
-	Key                  []byte                `json:"key,omitempty"`
-	PathCipher           CipherSuite           `json:"path_cipher,omitempty"`
-	EncryptionParameters *EncryptionParameters `json:"encryption_parameters,omitempty"`
+	Bucket               []byte                         `json:"bucket,omitempty"`
+	UnencryptedPath      []byte                         `json:"unencrypted_path,omitempty"`
+	EncryptedPath        []byte                         `json:"encrypted_path,omitempty"`
+	Key                  []byte                         `json:"key,omitempty"`
+	PathCipher           CipherSuite                    `json:"path_cipher,omitempty"`
+	EncryptionParameters *EncryptionParameters          `json:"encryption_parameters,omitempty"`
+	KeyVersion           uint32                         `json:"key_version,omitempty"`
+	PreviousKeys         []*EncryptionAccess_KeyVersion `json:"previous_keys,omitempty"`
 }
 
 func (m *EncryptionAccess_StoreEntry) Encode(c *picobuf.Encoder) bool {
@@ -69,6 +91,10 @@ func (m *EncryptionAccess_StoreEntry) Encode(c *picobuf.Encoder) bool {
 	c.Bytes(4, &m.Key)
 	c.Int32(5, (*int32)(&m.PathCipher))
 	c.Message(6, m.EncryptionParameters.Encode)
+	c.Uint32(7, &m.KeyVersion)
+	for _, x := range m.PreviousKeys {
+		c.AlwaysMessage(8, x.Encode)
+	}
 	return true
 }
 
@@ -87,4 +113,32 @@ func (m *EncryptionAccess_StoreEntry) Decode(c *picobuf.Decoder) {
 		}
 		m.EncryptionParameters.Decode(c)
 	})
+	c.Uint32(7, &m.KeyVersion)
+	c.RepeatedMessage(8, func(c *picobuf.Decoder) {
+		x := new(EncryptionAccess_KeyVersion)
+		c.Loop(x.Decode)
+		m.PreviousKeys = append(m.PreviousKeys, x)
+	})
+}
+
+type EncryptionAccess_KeyVersion struct {
+	Version uint32 `json:"version,omitempty"`
+	Key     []byte `json:"key,omitempty"`
+}
+
+func (m *EncryptionAccess_KeyVersion) Encode(c *picobuf.Encoder) bool {
+	if m == nil {
+		return false
+	}
+	c.Uint32(1, &m.Version)
+	c.Bytes(2, &m.Key)
+	return true
+}
+
+func (m *EncryptionAccess_KeyVersion) Decode(c *picobuf.Decoder) {
+	if m == nil {
+		return
+	}
+	c.Uint32(1, &m.Version)
+	c.Bytes(2, &m.Key)
 }
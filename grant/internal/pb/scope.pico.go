@@ -12,12 +12,51 @@ import (
 )
 
 type Scope struct {
+	SatelliteAddr        string             `json:"satellite_addr,omitempty"`
+	ApiKey               []byte             `json:"api_key,omitempty"`
+	EncryptionAccess     *EncryptionAccess  `json:"encryption_access,omitempty"`
+	AdditionalSatellites []*Scope_Satellite `json:"additional_satellites,omitempty"`
+}
+
+func (m *Scope) Encode(c *picobuf.Encoder) bool {
+	if m == nil {
+		return false
+	}
+	c.String(1, &m.SatelliteAddr)
+	c.Bytes(2, &m.ApiKey)
+	c.Message(3, m.EncryptionAccess.Encode)
+	for _, x := range m.AdditionalSatellites {
+		c.AlwaysMessage(4, x.Encode)
+	}
+	return true
+}
+
+func (m *Scope) Decode(c *picobuf.Decoder) {
+	if m == nil {
+		return
+	}
+	c.String(1, &m.SatelliteAddr)
+	c.Bytes(2, &m.ApiKey)
+	c.Message(3, func(c *picobuf.Decoder) {
+		if m.EncryptionAccess == nil {
+			m.EncryptionAccess = new(EncryptionAccess)
+		}
+		m.EncryptionAccess.Decode(c)
+	})
+	c.RepeatedMessage(4, func(c *picobuf.Decoder) {
+		x := new(Scope_Satellite)
+		c.Loop(x.Decode)
+		m.AdditionalSatellites = append(m.AdditionalSatellites, x)
+	})
+}
+
+type Scope_Satellite struct {
 	SatelliteAddr    string            `json:"satellite_addr,omitempty"`
 	ApiKey           []byte            `json:"api_key,omitempty"`
 	EncryptionAccess *EncryptionAccess `json:"encryption_access,omitempty"`
 }
 
-func (m *Scope) Encode(c *picobuf.Encoder) bool {
+func (m *Scope_Satellite) Encode(c *picobuf.Encoder) bool {
 	if m == nil {
 		return false
 	}
@@ -27,7 +66,7 @@ func (m *Scope) Encode(c *picobuf.Encoder) bool {
 	return true
 }
 
-func (m *Scope) Decode(c *picobuf.Decoder) {
+func (m *Scope_Satellite) Decode(c *picobuf.Decoder) {
 	if m == nil {
 		return
 	}
@@ -0,0 +1,159 @@
+// Copyright (C) 2026 Storj Labs, Inc.
+// See LICENSE for copying information.
+
+package grant
+
+import (
+	"errors"
+	"fmt"
+
+	"storj.io/common/grant/internal/pb"
+	"storj.io/common/storj"
+)
+
+// MultiAccess is an access grant for several satellites, e.g. for projects
+// whose data is replicated between satellites.
+//
+// It's serialized such that ParseAccess, including older versions of it,
+// returns the access for the primary satellite.
+type MultiAccess struct {
+	// Accesses contains the access for every satellite. The first one is the
+	// access for the primary satellite.
+	//
+	// Accesses that share the EncAccess of the primary access are serialized
+	// without a copy of it. All of them share the EncAccess again when parsed,
+	// which is limited to the api key of the primary access only.
+	Accesses []*Access
+}
+
+// ParseMultiAccess parses a serialized access grant string for one or more
+// satellites.
+func ParseMultiAccess(access string) (*MultiAccess, error) {
+	p, err := parseScope(access)
+	if err != nil {
+		return nil, err
+	}
+
+	primary, err := parseAccessFromProto(p.SatelliteAddr, p.ApiKey, p.EncryptionAccess)
+	if err != nil {
+		return nil, err
+	}
+
+	multi := &MultiAccess{Accesses: []*Access{primary}}
+	for i, satellite := range p.AdditionalSatellites {
+		if satellite.EncryptionAccess == nil {
+			entry, err := parseAccessFromProto(satellite.SatelliteAddr, satellite.ApiKey, p.EncryptionAccess)
+			if err != nil {
+				return nil, fmt.Errorf("satellite %d: %w", i+1, err)
+			}
+			entry.EncAccess = primary.EncAccess
+			multi.Accesses = append(multi.Accesses, entry)
+			continue
+		}
+
+		entry, err := parseAccessFromProto(satellite.SatelliteAddr, satellite.ApiKey, satellite.EncryptionAccess)
+		if err != nil {
+			return nil, fmt.Errorf("satellite %d: %w", i+1, err)
+		}
+		multi.Accesses = append(multi.Accesses, entry)
+	}
+
+	return multi, nil
+}
+
+// Serialize serializes the access grant such that it can be used later with
+// ParseMultiAccess or ParseAccess.
+func (multi *MultiAccess) Serialize() (string, error) {
+	if len(multi.Accesses) == 0 {
+		return "", errors.New("access grant has no satellites")
+	}
+
+	seen := map[string]struct{}{}
+	for _, access := range multi.Accesses {
+		if err := access.validate(); err != nil {
+			return "", err
+		}
+		if _, ok := seen[access.SatelliteAddress]; ok {
+			return "", fmt.Errorf("access grant has duplicate satellite %q", access.SatelliteAddress)
+		}
+		seen[access.SatelliteAddress] = struct{}{}
+	}
+
+	primary := multi.Accesses[0]
+	enc, err := primary.EncAccess.toProto()
+	if err != nil {
+		return "", err
+	}
+
+	scope := &pb.Scope{
+		SatelliteAddr:    primary.SatelliteAddress,
+		ApiKey:           primary.APIKey.SerializeRaw(),
+		EncryptionAccess: enc,
+	}
+
+	for _, access := range multi.Accesses[1:] {
+		satellite := &pb.Scope_Satellite{
+			SatelliteAddr: access.SatelliteAddress,
+			ApiKey:        access.APIKey.SerializeRaw(),
+		}
+		if access.EncAccess != primary.EncAccess {
+			satellite.EncryptionAccess, err = access.EncAccess.toProto()
+			if err != nil {
+				return "", err
+			}
+		}
+		scope.AdditionalSatellites = append(scope.AdditionalSatellites, satellite)
+	}
+
+	return serializeScope(scope)
+}
+
+// Select returns the access for the satellite.
+//
+// Satellites are matched by their node ID, when both the satellite and the
+// access have one, and by their address otherwise.
+func (multi *MultiAccess) Select(satellite storj.NodeURL) (*Access, error) {
+	for _, access := range multi.Accesses {
+		url, err := storj.ParseNodeURL(access.SatelliteAddress)
+		if err != nil {
+			continue
+		}
+
+		if !satellite.ID.IsZero() && !url.ID.IsZero() {
+			if satellite.ID == url.ID {
+				return access, nil
+			}
+			continue
+		}
+
+		if satellite.Address == url.Address {
+			return access, nil
+		}
+	}
+
+	return nil, fmt.Errorf("access grant has no access for satellite %q", satellite.String())
+}
+
+// Restrict creates a new access grant with specific permissions for every
+// satellite. See (*Access).Restrict for details.
+func (multi *MultiAccess) Restrict(permission Permission, prefixes ...SharePrefix) (*MultiAccess, error) {
+	if len(multi.Accesses) == 0 {
+		return nil, errors.New("access grant has no satellites")
+	}
+
+	primary := multi.Accesses[0]
+
+	restricted := &MultiAccess{}
+	for _, access := range multi.Accesses {
+		entry, err := access.Restrict(permission, prefixes...)
+		if err != nil {
+			return nil, err
+		}
+		if access != primary && access.EncAccess == primary.EncAccess {
+			entry.EncAccess = restricted.Accesses[0].EncAccess
+		}
+		restricted.Accesses = append(restricted.Accesses, entry)
+	}
+
+	return restricted, nil
+}
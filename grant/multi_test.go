@@ -0,0 +1,92 @@
+// Copyright (C) 2026 Storj Labs, Inc.
+// See LICENSE for copying information.
+
+package grant
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"storj.io/common/macaroon"
+	"storj.io/common/storj"
+	"storj.io/common/testrand"
+)
+
+func TestMultiAccess(t *testing.T) {
+	newAPIKey := func() *macaroon.APIKey {
+		secret, err := macaroon.NewSecret()
+		require.NoError(t, err)
+		apiKey, err := macaroon.NewAPIKey(secret)
+		require.NoError(t, err)
+		return apiKey
+	}
+
+	defaultKey := testrand.Key()
+	shared := NewEncryptionAccessWithDefaultKey(&defaultKey)
+
+	otherKey := testrand.Key()
+	own := NewEncryptionAccessWithDefaultKey(&otherKey)
+
+	satellite1 := storj.NodeURL{ID: testrand.NodeID(), Address: "us1.example.test:7777"}
+	satellite2 := storj.NodeURL{ID: testrand.NodeID(), Address: "eu1.example.test:7777"}
+	satellite3 := storj.NodeURL{ID: testrand.NodeID(), Address: "ap1.example.test:7777"}
+
+	multi := &MultiAccess{Accesses: []*Access{
+		{SatelliteAddress: satellite1.String(), APIKey: newAPIKey(), EncAccess: shared},
+		{SatelliteAddress: satellite2.String(), APIKey: newAPIKey(), EncAccess: shared},
+		{SatelliteAddress: satellite3.String(), APIKey: newAPIKey(), EncAccess: own},
+	}}
+
+	serialized, err := multi.Serialize()
+	require.NoError(t, err)
+
+	parsed, err := ParseMultiAccess(serialized)
+	require.NoError(t, err)
+	require.Len(t, parsed.Accesses, 3)
+	for i, access := range parsed.Accesses {
+		require.Equal(t, multi.Accesses[i].SatelliteAddress, access.SatelliteAddress)
+		require.Equal(t, multi.Accesses[i].APIKey.SerializeRaw(), access.APIKey.SerializeRaw())
+	}
+	require.Same(t, parsed.Accesses[0].EncAccess, parsed.Accesses[1].EncAccess)
+	require.Equal(t, &defaultKey, parsed.Accesses[1].EncAccess.Store.GetDefaultKey())
+	require.Equal(t, &otherKey, parsed.Accesses[2].EncAccess.Store.GetDefaultKey())
+
+	// single satellite parsers get the primary satellite.
+	primary, err := ParseAccess(serialized)
+	require.NoError(t, err)
+	require.Equal(t, satellite1.String(), primary.SatelliteAddress)
+
+	// single satellite grants are parsed as multi satellite grants.
+	single, err := multi.Accesses[1].Serialize()
+	require.NoError(t, err)
+	parsedSingle, err := ParseMultiAccess(single)
+	require.NoError(t, err)
+	require.Len(t, parsedSingle.Accesses, 1)
+	require.Equal(t, satellite2.String(), parsedSingle.Accesses[0].SatelliteAddress)
+
+	// select by node id or by address.
+	selected, err := parsed.Select(satellite2)
+	require.NoError(t, err)
+	require.Equal(t, satellite2.String(), selected.SatelliteAddress)
+
+	selected, err = parsed.Select(storj.NodeURL{Address: satellite3.Address})
+	require.NoError(t, err)
+	require.Equal(t, satellite3.String(), selected.SatelliteAddress)
+
+	_, err = parsed.Select(storj.NodeURL{ID: testrand.NodeID(), Address: satellite3.Address})
+	require.Error(t, err)
+
+	// restricting keeps the encryption access shared.
+	restricted, err := parsed.Restrict(Permission{AllowDownload: true}, SharePrefix{Bucket: "bucket"})
+	require.NoError(t, err)
+	require.Len(t, restricted.Accesses, 3)
+	require.Same(t, restricted.Accesses[0].EncAccess, restricted.Accesses[1].EncAccess)
+	require.NotSame(t, restricted.Accesses[0].EncAccess, restricted.Accesses[2].EncAccess)
+
+	// invalid grants are not serialized.
+	_, err = (&MultiAccess{}).Serialize()
+	require.Error(t, err)
+	_, err = (&MultiAccess{Accesses: []*Access{multi.Accesses[0], multi.Accesses[0]}}).Serialize()
+	require.Error(t, err)
+}
@@ -5,12 +5,16 @@ package grant
 
 import (
 	"errors"
+	"fmt"
+	"net/netip"
+	"slices"
 	"strings"
 	"time"
 
 	"storj.io/common/encryption"
 	"storj.io/common/macaroon"
 	"storj.io/common/paths"
+	"storj.io/common/storj"
 )
 
 // SharePrefix defines a prefix that will be shared.
@@ -23,6 +27,28 @@ type SharePrefix struct {
 	// included in the resulting access grant to decrypt any key that shares
 	// the same prefix up until the last slash.
 	Prefix string
+	// Pattern optionally restricts the shared objects to the ones whose key
+	// relative to Prefix matches the pattern, e.g. "*.jpg" shares only the
+	// JPEG files directly within Prefix. Prefix is treated as a directory.
+	//
+	// Components of the pattern are separated by forward slashes. A "**"
+	// component matches any number of components, the other components use
+	// the syntax of path.Match.
+	//
+	// The satellite matches patterns against the encrypted object keys.
+	// Hence, when the path cipher used for Prefix encrypts the object keys,
+	// patterns may only consist of literal components followed by "*" and
+	// "**" components. Other wildcards, e.g. for file extensions, require
+	// the path cipher to be storj.EncNull.
+	//
+	// When any of the prefixes has a pattern, the prefixes without one are
+	// shared as if their pattern was "**", which matches object keys at
+	// path component boundaries only.
+	//
+	// Content types can't be restricted, because the object metadata is
+	// encrypted, so file extensions are the way to restrict the kind of
+	// shared objects.
+	Pattern string
 }
 
 // Permission defines what actions can be used to share.
@@ -92,6 +118,14 @@ type Permission struct {
 	// If objects are uploaded with an explicit expiration time, the upload
 	// will be successful only if it is shorter than the MaxObjectTTL period.
 	MaxObjectTTL *time.Duration
+	// MaxObjectSize restricts the maximum size of new objects in bytes.
+	MaxObjectSize int64
+	// MaxUploadSize restricts the maximum number of bytes in a single
+	// upload request.
+	MaxUploadSize int64
+	// RateLimit restricts the number of requests that can be made with the
+	// resulting access grant within a period.
+	RateLimit *macaroon.RateLimit
 }
 
 // Restrict creates a new access grant with specific permissions.
@@ -123,6 +157,22 @@ func (access *Access) Restrict(permission Permission, prefixes ...SharePrefix) (
 		return nil, errors.New("non-positive ttl period")
 	}
 
+	if permission.MaxObjectSize < 0 || permission.MaxUploadSize < 0 {
+		return nil, errors.New("negative size limit")
+	}
+
+	var rateLimit *macaroon.Caveat_RateLimit
+	if permission.RateLimit != nil {
+		if permission.RateLimit.Requests < 0 || permission.RateLimit.Period <= 0 {
+			return nil, errors.New("invalid rate limit")
+		}
+		period := permission.RateLimit.Period
+		rateLimit = &macaroon.Caveat_RateLimit{
+			Requests: permission.RateLimit.Requests,
+			Period:   &period,
+		}
+	}
+
 	caveat := macaroon.WithNonce(macaroon.Caveat{
 		DisallowReads:                              !permission.AllowDownload,
 		DisallowWrites:                             !permission.AllowUpload,
@@ -141,6 +191,13 @@ func (access *Access) Restrict(permission Permission, prefixes ...SharePrefix) (
 		NotBefore:                                  notBefore,
 		NotAfter:                                   notAfter,
 		MaxObjectTtl:                               permission.MaxObjectTTL,
+		MaxObjectSize:                              permission.MaxObjectSize,
+		MaxUploadSize:                              permission.MaxUploadSize,
+		RateLimit:                                  rateLimit,
+	})
+
+	hasPatterns := slices.ContainsFunc(prefixes, func(prefix SharePrefix) bool {
+		return prefix.Pattern != ""
 	})
 
 	for _, prefix := range prefixes {
@@ -155,10 +212,30 @@ func (access *Access) Restrict(permission Permission, prefixes ...SharePrefix) (
 			return nil, err
 		}
 
+		// The prefix is restricted even when there's a pattern, such that
+		// satellites which don't know about patterns restrict the access too.
 		caveat.AllowedPaths = append(caveat.AllowedPaths, &macaroon.Caveat_Path{
 			Bucket:              []byte(prefix.Bucket),
 			EncryptedPathPrefix: []byte(encPath.Raw()),
 		})
+
+		if !hasPatterns {
+			continue
+		}
+
+		pattern := prefix.Pattern
+		if pattern == "" {
+			pattern = "**"
+		}
+		encPattern, err := encryptPattern(access.EncAccess.Store, prefix.Bucket, unencPath.Raw(), pattern)
+		if err != nil {
+			return nil, err
+		}
+
+		caveat.AllowedPathPatterns = append(caveat.AllowedPathPatterns, &macaroon.Caveat_PathPattern{
+			Bucket:           []byte(prefix.Bucket),
+			EncryptedPattern: []byte(encPattern),
+		})
 	}
 
 	restrictedAPIKey, err := access.APIKey.Restrict(caveat)
@@ -175,3 +252,90 @@ func (access *Access) Restrict(permission Permission, prefixes ...SharePrefix) (
 		EncAccess:        encAccess,
 	}, nil
 }
+
+// RestrictNetworks creates a new access grant, which only works for requests
+// coming from within one of the networks.
+//
+// Like Restrict, the resulting access grant only allows for the intersection
+// of the networks of all previous RestrictNetworks calls.
+func (access *Access) RestrictNetworks(networks ...netip.Prefix) (*Access, error) {
+	if len(networks) == 0 {
+		return nil, errors.New("no networks")
+	}
+
+	allowedNetworks := make([]string, 0, len(networks))
+	for _, network := range networks {
+		if !network.IsValid() {
+			return nil, errors.New("invalid network")
+		}
+		allowedNetworks = append(allowedNetworks, network.Masked().String())
+	}
+
+	restrictedAPIKey, err := access.APIKey.Restrict(macaroon.WithNonce(macaroon.Caveat{
+		AllowedNetworks: allowedNetworks,
+	}))
+	if err != nil {
+		return nil, err
+	}
+
+	encAccess := access.EncAccess.Clone()
+	encAccess.LimitTo(restrictedAPIKey)
+
+	return &Access{
+		SatelliteAddress: access.SatelliteAddress,
+		APIKey:           restrictedAPIKey,
+		EncAccess:        encAccess,
+	}, nil
+}
+
+// encryptPattern returns the pattern for the encrypted object keys in the
+// bucket, which matches the object keys relative to prefix that match pattern.
+func encryptPattern(store *encryption.Store, bucket, prefix, pattern string) (string, error) {
+	if err := macaroon.ValidatePathPattern(pattern); err != nil {
+		return "", err
+	}
+
+	var literals []string
+	if prefix != "" {
+		literals = strings.Split(prefix, "/")
+	}
+
+	// The components preceding the first wildcard are encrypted like a prefix.
+	components := strings.Split(pattern, "/")
+	for len(components) > 0 {
+		literal, ok := macaroon.UnescapePathPattern(components[0])
+		if !ok {
+			break
+		}
+		literals = append(literals, literal)
+		components = components[1:]
+	}
+
+	unencPath := paths.NewUnencrypted(strings.Join(literals, "/"))
+	encPath, err := encryption.EncryptPathWithStoreCipher(bucket, unencPath, store)
+	if err != nil {
+		return "", err
+	}
+
+	// The remaining components can only be matched against the unencrypted
+	// object keys when the path cipher leaves them unencrypted.
+	_, _, base := store.LookupUnencrypted(bucket, unencPath)
+	encrypted := store.EncryptionBypass || base == nil || base.PathCipher != storj.EncNull
+	if encrypted {
+		for _, component := range components {
+			if component != "*" && component != "**" {
+				return "", fmt.Errorf("pattern %q can't be matched against encrypted object keys: only \"*\" and \"**\" are supported after the first wildcard", pattern)
+			}
+		}
+	}
+
+	var encPattern []string
+	if encPath.Valid() {
+		for _, component := range strings.Split(encPath.Raw(), "/") {
+			encPattern = append(encPattern, macaroon.EscapePathPattern(component))
+		}
+	}
+	encPattern = append(encPattern, components...)
+
+	return strings.Join(encPattern, "/"), nil
+}
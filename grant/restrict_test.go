@@ -5,12 +5,14 @@ package grant
 
 import (
 	"context"
+	"net/netip"
 	"testing"
 	"time"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 
+	"storj.io/common/encryption"
 	"storj.io/common/macaroon"
 	"storj.io/common/paths"
 	"storj.io/common/storj"
@@ -128,3 +130,171 @@ func TestRestrict(t *testing.T) {
 	_, _, base = restricted.EncAccess.Store.LookupEncrypted("bucket", paths.NewEncrypted("prefix2/path2"))
 	assert.Nil(t, base)
 }
+
+func TestRestrict_AllowedNetworks(t *testing.T) {
+	ctx := context.Background()
+
+	secret, err := macaroon.NewSecret()
+	require.NoError(t, err)
+
+	apiKey, err := macaroon.NewAPIKey(secret)
+	require.NoError(t, err)
+
+	defaultKey := testrand.Key()
+	access := Access{
+		APIKey:    apiKey,
+		EncAccess: NewEncryptionAccessWithDefaultKey(&defaultKey),
+	}
+
+	restricted, err := access.Restrict(Permission{AllowDownload: true})
+	require.NoError(t, err)
+	restricted, err = restricted.RestrictNetworks(netip.MustParsePrefix("192.0.2.17/24"))
+	require.NoError(t, err)
+
+	action := macaroon.Action{
+		Op:            macaroon.ActionRead,
+		Time:          time.Now(),
+		Bucket:        []byte("bucket"),
+		EncryptedPath: []byte("path"),
+		ClientAddr:    netip.MustParseAddr("192.0.2.1"),
+	}
+	require.NoError(t, restricted.APIKey.Check(ctx, secret, macaroon.APIKeyVersionObjectLock, action, nil))
+
+	action.ClientAddr = netip.MustParseAddr("198.51.100.1")
+	require.Error(t, restricted.APIKey.Check(ctx, secret, macaroon.APIKeyVersionObjectLock, action, nil))
+
+	// network restrictions don't grant any other permissions.
+	action.Op = macaroon.ActionWrite
+	action.ClientAddr = netip.MustParseAddr("192.0.2.1")
+	require.Error(t, restricted.APIKey.Check(ctx, secret, macaroon.APIKeyVersionObjectLock, action, nil))
+
+	networksOnly, err := access.RestrictNetworks(netip.MustParsePrefix("192.0.2.0/24"))
+	require.NoError(t, err)
+	require.NoError(t, networksOnly.APIKey.Check(ctx, secret, macaroon.APIKeyVersionObjectLock, action, nil))
+
+	_, err = access.RestrictNetworks()
+	require.Error(t, err)
+	_, err = access.RestrictNetworks(netip.Prefix{})
+	require.Error(t, err)
+}
+
+func TestRestrict_Quotas(t *testing.T) {
+	ctx := context.Background()
+
+	secret, err := macaroon.NewSecret()
+	require.NoError(t, err)
+
+	apiKey, err := macaroon.NewAPIKey(secret)
+	require.NoError(t, err)
+
+	defaultKey := testrand.Key()
+	access := Access{
+		APIKey:    apiKey,
+		EncAccess: NewEncryptionAccessWithDefaultKey(&defaultKey),
+	}
+
+	restricted, err := access.Restrict(Permission{
+		MaxObjectSize: 1000,
+		MaxUploadSize: 100,
+		RateLimit:     &macaroon.RateLimit{Requests: 10, Period: time.Second},
+	})
+	require.NoError(t, err)
+
+	size, err := restricted.APIKey.GetMaxObjectSize(ctx)
+	require.NoError(t, err)
+	require.EqualValues(t, 1000, size)
+
+	size, err = restricted.APIKey.GetMaxUploadSize(ctx)
+	require.NoError(t, err)
+	require.EqualValues(t, 100, size)
+
+	limit, err := restricted.APIKey.GetRateLimit(ctx)
+	require.NoError(t, err)
+	require.Equal(t, &macaroon.RateLimit{Requests: 10, Period: time.Second}, limit)
+
+	_, err = access.Restrict(Permission{MaxObjectSize: -1})
+	require.Error(t, err)
+
+	_, err = access.Restrict(Permission{RateLimit: &macaroon.RateLimit{Requests: 10}})
+	require.Error(t, err)
+}
+
+func TestRestrict_PathPatterns(t *testing.T) {
+	ctx := context.Background()
+	now := time.Now()
+
+	secret, err := macaroon.NewSecret()
+	require.NoError(t, err)
+
+	apiKey, err := macaroon.NewAPIKey(secret)
+	require.NoError(t, err)
+
+	defaultKey := testrand.Key()
+
+	// paths are encrypted with the unrestricted store, such that the
+	// restricted api key is checked for paths it can't decrypt too.
+	check := func(restricted, access *Access, bucket, path string) error {
+		encPath, err := encryption.EncryptPathWithStoreCipher(bucket, paths.NewUnencrypted(path), access.EncAccess.Store)
+		require.NoError(t, err)
+		return restricted.APIKey.Check(ctx, secret, macaroon.APIKeyVersionObjectLock, macaroon.Action{
+			Op:            macaroon.ActionRead,
+			Time:          now,
+			Bucket:        []byte(bucket),
+			EncryptedPath: []byte(encPath.Raw()),
+		}, nil)
+	}
+
+	t.Run("unencrypted", func(t *testing.T) {
+		encAccess := NewEncryptionAccessWithDefaultKey(&defaultKey)
+		encAccess.SetDefaultPathCipher(storj.EncNull)
+		access := &Access{APIKey: apiKey, EncAccess: encAccess}
+
+		restricted, err := access.Restrict(Permission{AllowDownload: true},
+			SharePrefix{Bucket: "bucket", Prefix: "photos/", Pattern: "**/*.jpg"},
+			SharePrefix{Bucket: "other", Prefix: "docs"},
+		)
+		require.NoError(t, err)
+
+		assert.NoError(t, check(restricted, access, "bucket", "photos/a.jpg"))
+		assert.NoError(t, check(restricted, access, "bucket", "photos/2024/a.jpg"))
+		assert.Error(t, check(restricted, access, "bucket", "photos/a.png"))
+		assert.Error(t, check(restricted, access, "bucket", "videos/a.jpg"))
+		assert.NoError(t, check(access, access, "bucket", "videos/a.jpg"))
+
+		// prefixes without a pattern match at component boundaries.
+		assert.NoError(t, check(restricted, access, "other", "docs/a.txt"))
+		assert.Error(t, check(restricted, access, "other", "docsx/a.txt"))
+
+		_, err = access.Restrict(Permission{AllowDownload: true}, SharePrefix{Bucket: "bucket", Pattern: "[a-"})
+		require.Error(t, err)
+	})
+
+	t.Run("encrypted", func(t *testing.T) {
+		encAccess := NewEncryptionAccessWithDefaultKey(&defaultKey)
+		encAccess.SetDefaultPathCipher(storj.EncAESGCM)
+		access := &Access{APIKey: apiKey, EncAccess: encAccess}
+
+		// partial matches of encrypted components are not possible.
+		_, err := access.Restrict(Permission{AllowDownload: true}, SharePrefix{Bucket: "bucket", Prefix: "photos/", Pattern: "*.jpg"})
+		require.Error(t, err)
+		_, err = access.Restrict(Permission{AllowDownload: true}, SharePrefix{Bucket: "bucket", Prefix: "photos/", Pattern: "*/a"})
+		require.Error(t, err)
+
+		restricted, err := access.Restrict(Permission{AllowDownload: true}, SharePrefix{Bucket: "bucket", Prefix: "photos/", Pattern: "2024/*"})
+		require.NoError(t, err)
+
+		assert.NoError(t, check(access, access, "bucket", "photos/2024/a.jpg"))
+		assert.NoError(t, check(restricted, access, "bucket", "photos/2024/a.jpg"))
+		assert.Error(t, check(restricted, access, "bucket", "photos/2024/x/a.jpg"))
+
+		// the encryption access is limited to the literal prefix of the pattern.
+		encPath, err := encryption.EncryptPathWithStoreCipher("bucket", paths.NewUnencrypted("photos/2025/a.jpg"), access.EncAccess.Store)
+		require.NoError(t, err)
+		_, _, base := restricted.EncAccess.Store.LookupEncrypted("bucket", encPath)
+		assert.Nil(t, base)
+
+		_, _, base = restricted.EncAccess.Store.LookupUnencrypted("bucket", paths.NewUnencrypted("photos/2024/a.jpg"))
+		require.NotNil(t, base)
+		assert.Equal(t, "photos/2024", base.Unencrypted.Raw())
+	})
+}
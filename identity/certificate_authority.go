@@ -250,20 +250,33 @@ func (caS CASetupConfig) FullConfig() FullCAConfig {
 	}
 }
 
-// Load loads a CA from the given configuration.
+// Load loads a CA from the given configuration. The key path may be a
+// reference to a key with a scheme registered with RegisterKeyScheme.
 func (fc FullCAConfig) Load() (*FullCertificateAuthority, error) {
 	p, err := fc.PeerConfig().Load()
 	if err != nil {
 		return nil, err
 	}
 
-	kb, err := os.ReadFile(fc.KeyPath)
-	if err != nil {
-		return nil, peertls.ErrNotExist.Wrap(err)
-	}
-	k, err := pkcrypto.PrivateKeyFromPEM(kb)
-	if err != nil {
-		return nil, err
+	var k crypto.PrivateKey
+	if IsKeyRef(fc.KeyPath) {
+		signer, err := OpenKeyRef(fc.KeyPath)
+		if err != nil {
+			return nil, err
+		}
+		if !pkcrypto.PublicKeyEqual(signer.Public(), p.Cert.PublicKey) {
+			return nil, ErrKeyRef.New("signer doesn't match the CA certificate")
+		}
+		k = signer
+	} else {
+		kb, err := os.ReadFile(fc.KeyPath)
+		if err != nil {
+			return nil, peertls.ErrNotExist.Wrap(err)
+		}
+		k, err = pkcrypto.PrivateKeyFromPEM(kb)
+		if err != nil {
+			return nil, err
+		}
 	}
 
 	return &FullCertificateAuthority{
@@ -292,7 +305,13 @@ func (fc FullCAConfig) Save(ca *FullCertificateAuthority) error {
 		return writeErrs.Err()
 	}
 
-	if fc.KeyPath != "" {
+	if IsKeyRef(fc.KeyPath) {
+		// NB: referenced keys are stored outside of the CA files.
+		if err := checkSavableToKeyRef(fc.KeyPath, ca.Key); err != nil {
+			writeErrs.Add(err)
+			return writeErrs.Err()
+		}
+	} else if fc.KeyPath != "" {
 		if err := pkcrypto.WritePrivateKeyPEM(&keyData, ca.Key); err != nil {
 			writeErrs.Add(err)
 			return writeErrs.Err()
@@ -319,12 +319,25 @@ func (is SetupConfig) FullConfig() Config {
 	}
 }
 
-// Load loads a FullIdentity from the config.
+// Load loads a FullIdentity from the config. The key path may be a reference
+// to a key with a scheme registered with RegisterKeyScheme.
 func (ic Config) Load() (*FullIdentity, error) {
 	c, err := os.ReadFile(ic.CertPath)
 	if err != nil {
 		return nil, peertls.ErrNotExist.Wrap(err)
 	}
+	if IsKeyRef(ic.KeyPath) {
+		signer, err := OpenKeyRef(ic.KeyPath)
+		if err != nil {
+			return nil, err
+		}
+		fi, err := FullIdentityFromSigner(c, signer)
+		if err != nil {
+			return nil, errs.New("failed to load identity %#v, %#v: %v",
+				ic.CertPath, ic.KeyPath, err)
+		}
+		return fi, nil
+	}
 	k, err := os.ReadFile(ic.KeyPath)
 	if err != nil {
 		return nil, peertls.ErrNotExist.Wrap(err)
@@ -352,7 +365,10 @@ func (ic Config) Save(fi *FullIdentity) error {
 		writeChainDataErr = writeChainData(ic.CertPath, certData.Bytes())
 	}
 
-	if ic.KeyPath != "" {
+	if IsKeyRef(ic.KeyPath) {
+		// NB: referenced keys are stored outside of the identity files.
+		writeKeyErr = checkSavableToKeyRef(ic.KeyPath, fi.Key)
+	} else if ic.KeyPath != "" {
 		writeKeyErr = pkcrypto.WritePrivateKeyPEM(&keyData, fi.Key)
 		writeKeyDataErr = writeKeyData(ic.KeyPath, keyData.Bytes())
 	}
@@ -376,6 +392,16 @@ func (ic Config) SaveBackup(fi *FullIdentity) error {
 	}.Save(fi)
 }
 
+// SaveRotated saves an identity returned by ManageableFullIdentity.Rotate.
+// The previous identity is saved with a timestamped filename first, so it's
+// available when saving the rotated identity fails.
+func (ic Config) SaveRotated(rotated, previous *FullIdentity) error {
+	if err := ic.SaveBackup(previous); err != nil {
+		return err
+	}
+	return ic.Save(rotated)
+}
+
 // PeerConfig converts a Config to a PeerConfig.
 func (ic Config) PeerConfig() *PeerConfig {
 	return &PeerConfig{
@@ -494,6 +520,36 @@ func (manageableIdent *ManageableFullIdentity) Revoke() error {
 	return nil
 }
 
+// Rotate replaces the leaf certificate and key of the identity with a new leaf
+// issued by the CA. The new leaf contains a revocation extension for the
+// previous leaf, which takes effect after the grace period, such that peers
+// accept both leaves until then. It returns the previous identity, which can
+// be saved alongside the rotated one with Config.SaveRotated.
+func (manageableIdent *ManageableFullIdentity) Rotate(gracePeriod time.Duration) (previous *FullIdentity, err error) {
+	ext, err := extensions.NewRevocationExtWithGracePeriod(manageableIdent.CA.Key, manageableIdent.Leaf, gracePeriod)
+	if err != nil {
+		return nil, err
+	}
+
+	rotated, err := manageableIdent.CA.NewIdentity(ext)
+	if err != nil {
+		return nil, err
+	}
+
+	previous = &FullIdentity{
+		RestChain: manageableIdent.RestChain,
+		CA:        manageableIdent.FullIdentity.CA,
+		Leaf:      manageableIdent.Leaf,
+		ID:        manageableIdent.ID,
+		Key:       manageableIdent.Key,
+	}
+
+	manageableIdent.Leaf = rotated.Leaf
+	manageableIdent.Key = rotated.Key
+
+	return previous, nil
+}
+
 func backupPath(path string) string {
 	pathExt := filepath.Ext(path)
 	base := strings.TrimSuffix(path, pathExt)
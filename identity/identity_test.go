@@ -12,8 +12,10 @@ import (
 	"encoding/asn1"
 	"fmt"
 	"os"
+	"path/filepath"
 	"runtime"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
@@ -305,6 +307,59 @@ func TestManageableFullIdentity_Revoke(t *testing.T) {
 	require.NoError(t, err)
 }
 
+func TestManageableFullIdentity_Rotate(t *testing.T) {
+	ctx := testcontext.New(t)
+
+	manageableFullIdentity, err := testidentity.NewTestManageableFullIdentity(ctx)
+	require.NoError(t, err)
+
+	oldLeaf := manageableFullIdentity.Leaf
+	oldKey := manageableFullIdentity.Key
+
+	_, err = manageableFullIdentity.Rotate(-time.Hour)
+	require.Error(t, err)
+
+	previous, err := manageableFullIdentity.Rotate(time.Hour)
+	require.NoError(t, err)
+
+	assert.Equal(t, oldLeaf, previous.Leaf)
+	assert.Equal(t, oldKey, previous.Key)
+	assert.Equal(t, manageableFullIdentity.ID, previous.ID)
+
+	assert.NotEqual(t, oldLeaf.PublicKey, manageableFullIdentity.Leaf.PublicKey)
+	publicKey, err := pkcrypto.PublicKeyFromPrivate(manageableFullIdentity.Key)
+	require.NoError(t, err)
+	assert.Equal(t, publicKey, manageableFullIdentity.Leaf.PublicKey)
+	require.NoError(t, manageableFullIdentity.Leaf.CheckSignatureFrom(manageableFullIdentity.CA.Cert))
+
+	// peers without support for grace periods ignore the revocation.
+	extMap := tlsopts.NewExtensionsMap(manageableFullIdentity.Leaf)
+	assert.NotContains(t, extMap, extensions.RevocationExtID.String())
+
+	revocationExt := extMap[extensions.RevocationGracePeriodExtID.String()]
+	var rev extensions.Revocation
+	require.NoError(t, rev.Unmarshal(revocationExt.Value))
+	require.NoError(t, rev.Verify(manageableFullIdentity.CA.Cert))
+	assert.LessOrEqual(t, rev.Timestamp, time.Now().Unix())
+	assert.False(t, rev.InEffect(time.Now()))
+	assert.True(t, rev.InEffect(time.Now().Add(time.Hour)))
+
+	// both identities are saved.
+	config := identity.Config{
+		CertPath: ctx.File("identity", "identity.cert"),
+		KeyPath:  ctx.File("identity", "identity.key"),
+	}
+	require.NoError(t, config.SaveRotated(manageableFullIdentity.FullIdentity, previous))
+
+	loaded, err := config.Load()
+	require.NoError(t, err)
+	assert.Equal(t, manageableFullIdentity.Leaf.Raw, loaded.Leaf.Raw)
+
+	backups, err := filepath.Glob(ctx.File("identity", "identity.*.cert"))
+	require.NoError(t, err)
+	require.Len(t, backups, 1)
+}
+
 func TestEncodeDecodePeerIdentity(t *testing.T) {
 	ctx := testcontext.New(t)
 
@@ -0,0 +1,338 @@
+// Copyright (C) 2026 Storj Labs, Inc.
+// See LICENSE for copying information.
+
+package issuancelog_test
+
+import (
+	"context"
+	"crypto/sha256"
+	"crypto/x509"
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"storj.io/common/identity/issuancelog"
+	"storj.io/common/identity/testidentity"
+	"storj.io/common/signing"
+	"storj.io/common/storj"
+	"storj.io/common/testcontext"
+)
+
+// refHash is the merkle tree hash of RFC 9162, section 2.1.1.
+func refHash(leaves []issuancelog.Hash) issuancelog.Hash {
+	switch len(leaves) {
+	case 0:
+		return sha256.Sum256(nil)
+	case 1:
+		return leaves[0]
+	}
+	k := refSplit(len(leaves))
+	return refNode(refHash(leaves[:k]), refHash(leaves[k:]))
+}
+
+func refNode(left, right issuancelog.Hash) issuancelog.Hash {
+	return sha256.Sum256(append(append([]byte{1}, left[:]...), right[:]...))
+}
+
+func refSplit(n int) int {
+	k := 1
+	for k*2 < n {
+		k *= 2
+	}
+	return k
+}
+
+// refPath is the merkle audit path of RFC 9162, section 2.1.3.1.
+func refPath(m int, leaves []issuancelog.Hash) []issuancelog.Hash {
+	if len(leaves) <= 1 {
+		return nil
+	}
+	k := refSplit(len(leaves))
+	if m < k {
+		return append(refPath(m, leaves[:k]), refHash(leaves[k:]))
+	}
+	return append(refPath(m-k, leaves[k:]), refHash(leaves[:k]))
+}
+
+// refSubProof is the merkle consistency proof of RFC 9162, section 2.1.4.1.
+func refSubProof(m int, leaves []issuancelog.Hash, complete bool) []issuancelog.Hash {
+	if m == len(leaves) {
+		if complete {
+			return nil
+		}
+		return []issuancelog.Hash{refHash(leaves)}
+	}
+	k := refSplit(len(leaves))
+	if m <= k {
+		return append(refSubProof(m, leaves[:k], complete), refHash(leaves[k:]))
+	}
+	return append(refSubProof(m-k, leaves[k:], false), refHash(leaves[:k]))
+}
+
+func TestProofs(t *testing.T) {
+	ctx := testcontext.New(t)
+
+	log := issuancelog.New(signing.SignerFromFullIdentity(testidentity.MustPregeneratedIdentity(0, storj.DefaultIDVersion())))
+
+	var leaves []issuancelog.Hash
+	roots := []issuancelog.Hash{sha256.Sum256(nil)}
+	for i := 0; i < 20; i++ {
+		data := []byte(fmt.Sprint("entry ", i))
+		index, err := log.Append(ctx, data)
+		require.NoError(t, err)
+		require.EqualValues(t, i, index)
+
+		leaves = append(leaves, issuancelog.LeafHash(data))
+		roots = append(roots, refHash(leaves))
+	}
+
+	for size := uint64(0); size <= 20; size++ {
+		root, err := log.RootHash(size)
+		require.NoError(t, err)
+		require.Equal(t, roots[size], root, size)
+
+		for index := uint64(0); index < size; index++ {
+			gotIndex, proof, err := log.InclusionProof(ctx, leaves[index], size)
+			require.NoError(t, err)
+			require.Equal(t, index, gotIndex)
+			require.Equal(t, refPath(int(index), leaves[:size]), proof)
+			require.NoError(t, issuancelog.VerifyInclusion(leaves[index], index, size, proof, root), "%d/%d", index, size)
+
+			// other leaves, indexes and roots don't verify.
+			require.Error(t, issuancelog.VerifyInclusion(leaves[(index+1)%20], index, size, proof, root))
+			if size > 1 {
+				require.Error(t, issuancelog.VerifyInclusion(leaves[index], (index+1)%size, size, proof, root))
+				require.Error(t, issuancelog.VerifyInclusion(leaves[index], index, size, proof[1:], root))
+			}
+			require.Error(t, issuancelog.VerifyInclusion(leaves[index], index, size, proof, roots[size-1]))
+		}
+
+		for first := uint64(0); first <= size; first++ {
+			proof, err := log.ConsistencyProof(ctx, first, size)
+			require.NoError(t, err)
+			if first > 0 {
+				require.Equal(t, refSubProof(int(first), leaves[:size], true), proof)
+			}
+			require.NoError(t, issuancelog.VerifyConsistency(first, size, roots[first], root, proof), "%d/%d", first, size)
+
+			// other roots don't verify.
+			if first > 0 && first < size {
+				require.Error(t, issuancelog.VerifyConsistency(first, size, roots[first-1], root, proof))
+				require.Error(t, issuancelog.VerifyConsistency(first, size, roots[first], roots[size-1], proof))
+			}
+		}
+	}
+
+	_, _, err := log.InclusionProof(ctx, issuancelog.LeafHash([]byte("missing")), 20)
+	require.True(t, issuancelog.ErrNotFound.Has(err), err)
+	_, _, err = log.InclusionProof(ctx, leaves[10], 10)
+	require.True(t, issuancelog.ErrNotFound.Has(err), err)
+	_, err = log.ConsistencyProof(ctx, 5, 21)
+	require.Error(t, err)
+}
+
+func TestSignedTreeHead(t *testing.T) {
+	ctx := testcontext.New(t)
+
+	for _, version := range storj.IDVersions {
+		ident := testidentity.MustPregeneratedIdentity(0, version)
+		log := issuancelog.New(signing.SignerFromFullIdentity(ident))
+		_, err := log.Append(ctx, []byte("entry"))
+		require.NoError(t, err)
+
+		sth, err := log.SignedTreeHead(ctx)
+		require.NoError(t, err)
+		require.Equal(t, ident.ID, sth.LogID)
+		require.EqualValues(t, 1, sth.TreeSize)
+
+		signee := signing.SigneeFromPeerIdentity(ident.PeerIdentity())
+		require.NoError(t, issuancelog.VerifySignedTreeHead(ctx, signee, sth))
+
+		unmarshaled, err := issuancelog.UnmarshalSignedTreeHead(sth.Marshal())
+		require.NoError(t, err)
+		require.Equal(t, sth, unmarshaled)
+		require.NoError(t, issuancelog.VerifySignedTreeHead(ctx, signee, unmarshaled))
+
+		// modified tree heads don't verify.
+		unmarshaled.TreeSize++
+		require.True(t, issuancelog.ErrVerify.Has(issuancelog.VerifySignedTreeHead(ctx, signee, unmarshaled)))
+
+		// nor do tree heads of other logs.
+		other := signing.SigneeFromPeerIdentity(testidentity.MustPregeneratedIdentity(1, version).PeerIdentity())
+		require.True(t, issuancelog.ErrVerify.Has(issuancelog.VerifySignedTreeHead(ctx, other, sth)))
+
+		_, err = issuancelog.UnmarshalSignedTreeHead(sth.Marshal()[:10])
+		require.Error(t, err)
+	}
+}
+
+func TestOpen(t *testing.T) {
+	ctx := testcontext.New(t)
+
+	signer := signing.SignerFromFullIdentity(testidentity.MustPregeneratedIdentity(0, storj.DefaultIDVersion()))
+	path := filepath.Join(ctx.Dir("log"), "issuance.log")
+
+	log, err := issuancelog.Open(path, signer)
+	require.NoError(t, err)
+	for i := 0; i < 5; i++ {
+		_, err := log.Append(ctx, []byte(fmt.Sprint("entry ", i)))
+		require.NoError(t, err)
+	}
+	root, err := log.RootHash(5)
+	require.NoError(t, err)
+	require.NoError(t, log.Close())
+
+	// simulate a failed append.
+	file, err := os.OpenFile(path, os.O_WRONLY|os.O_APPEND, 0644)
+	require.NoError(t, err)
+	_, err = file.Write([]byte{0, 0, 0, 10, 'e'})
+	require.NoError(t, err)
+	require.NoError(t, file.Close())
+
+	log, err = issuancelog.Open(path, signer)
+	require.NoError(t, err)
+	require.EqualValues(t, 5, log.Size())
+	reopenedRoot, err := log.RootHash(5)
+	require.NoError(t, err)
+	require.Equal(t, root, reopenedRoot)
+
+	index, err := log.Append(ctx, []byte("entry 5"))
+	require.NoError(t, err)
+	require.EqualValues(t, 5, index)
+	require.NoError(t, log.Close())
+
+	log, err = issuancelog.Open(path, signer)
+	require.NoError(t, err)
+	defer ctx.Check(log.Close)
+	require.EqualValues(t, 6, log.Size())
+	index, _, err = log.InclusionProof(ctx, issuancelog.LeafHash([]byte("entry 5")), 6)
+	require.NoError(t, err)
+	require.EqualValues(t, 5, index)
+}
+
+// switchable is a source, which can be switched to another log.
+type switchable struct {
+	issuancelog.Source
+}
+
+func TestVerifier(t *testing.T) {
+	ctx := testcontext.New(t)
+
+	logIdent := testidentity.MustPregeneratedIdentity(0, storj.DefaultIDVersion())
+	ca := testidentity.NewPregeneratedSigner(storj.DefaultIDVersion())
+
+	log := issuancelog.New(signing.SignerFromFullIdentity(logIdent))
+	verifier := issuancelog.NewVerifier(log, signing.SigneeFromPeerIdentity(logIdent.PeerIdentity()), issuancelog.VerifierConfig{})
+	verifyPeer := verifier.VerifyPeer()
+
+	logged, err := log.NewIdentity(ctx, ca)
+	require.NoError(t, err)
+	unlogged, err := ca.NewIdentity()
+	require.NoError(t, err)
+
+	chain := func(ident interface{ Chain() []*x509.Certificate }) [][]*x509.Certificate {
+		return [][]*x509.Certificate{ident.Chain()}
+	}
+
+	require.NoError(t, verifyPeer(nil, chain(logged)))
+	require.True(t, issuancelog.ErrNotFound.Has(verifyPeer(nil, chain(unlogged))))
+	require.EqualValues(t, 1, verifier.TrustedTreeHead().TreeSize)
+
+	// certificates appended later are found after the log grew consistently.
+	for i := 0; i < 3; i++ {
+		_, err := log.NewIdentity(ctx, ca)
+		require.NoError(t, err)
+	}
+	_, err = log.AppendCertificate(ctx, unlogged.Leaf)
+	require.NoError(t, err)
+	require.NoError(t, verifyPeer(nil, chain(unlogged)))
+	require.NoError(t, verifyPeer(nil, chain(logged)))
+	require.EqualValues(t, 5, verifier.TrustedTreeHead().TreeSize)
+
+	// a log signed by another key isn't trusted.
+	other := issuancelog.New(signing.SignerFromFullIdentity(testidentity.MustPregeneratedIdentity(1, storj.DefaultIDVersion())))
+	_, err = other.AppendCertificate(ctx, logged.Leaf)
+	require.NoError(t, err)
+	err = issuancelog.NewVerifier(other, signing.SigneeFromPeerIdentity(logIdent.PeerIdentity()), issuancelog.VerifierConfig{}).Verify(ctx, logged.Leaf)
+	require.True(t, issuancelog.ErrVerify.Has(err), err)
+
+	// a fork of the log with the same key is detected.
+	forked := issuancelog.New(signing.SignerFromFullIdentity(logIdent))
+	for i := 0; i < 6; i++ {
+		_, err := forked.NewIdentity(ctx, ca)
+		require.NoError(t, err)
+	}
+	_, err = forked.AppendCertificate(ctx, logged.Leaf)
+	require.NoError(t, err)
+
+	source := &switchable{log}
+	forkVerifier := issuancelog.NewVerifier(source, signing.SigneeFromPeerIdentity(logIdent.PeerIdentity()), issuancelog.VerifierConfig{})
+	require.NoError(t, forkVerifier.Verify(ctx, logged.Leaf))
+
+	source.Source = forked
+	err = forkVerifier.Verify(ctx, logged.Leaf)
+	require.True(t, issuancelog.ErrVerify.Has(err), err)
+	require.EqualValues(t, 5, forkVerifier.TrustedTreeHead().TreeSize)
+
+	// tree heads of the fork alone are fine.
+	require.NoError(t, issuancelog.NewVerifier(forked, signing.SigneeFromPeerIdentity(logIdent.PeerIdentity()), issuancelog.VerifierConfig{}).Verify(ctx, logged.Leaf))
+}
+
+// countingSource counts the requested tree heads and blocks the requests
+// while blocked is set.
+type countingSource struct {
+	issuancelog.Source
+	treeHeads int
+	blocked   bool
+}
+
+func (source *countingSource) SignedTreeHead(ctx context.Context) (*issuancelog.SignedTreeHead, error) {
+	source.treeHeads++
+	if source.blocked {
+		<-ctx.Done()
+		return nil, ctx.Err()
+	}
+	return source.Source.SignedTreeHead(ctx)
+}
+
+func TestVerifier_FreshTreeHead(t *testing.T) {
+	ctx := testcontext.New(t)
+
+	logIdent := testidentity.MustPregeneratedIdentity(0, storj.DefaultIDVersion())
+	ca := testidentity.NewPregeneratedSigner(storj.DefaultIDVersion())
+
+	log := issuancelog.New(signing.SignerFromFullIdentity(logIdent))
+	source := &countingSource{Source: log}
+	verifier := issuancelog.NewVerifier(source, signing.SigneeFromPeerIdentity(logIdent.PeerIdentity()), issuancelog.VerifierConfig{
+		Timeout:        10 * time.Millisecond,
+		MaxTreeHeadAge: time.Hour,
+	})
+	verifyPeer := verifier.VerifyPeer()
+
+	first, err := log.NewIdentity(ctx, ca)
+	require.NoError(t, err)
+	require.NoError(t, verifyPeer(nil, [][]*x509.Certificate{first.Chain()}))
+	require.Equal(t, 1, source.treeHeads)
+
+	// the fresh tree head is reused.
+	require.NoError(t, verifyPeer(nil, [][]*x509.Certificate{first.Chain()}))
+	require.Equal(t, 1, source.treeHeads)
+
+	// certificates, which were logged afterwards, require the current tree head.
+	second, err := log.NewIdentity(ctx, ca)
+	require.NoError(t, err)
+	require.NoError(t, verifyPeer(nil, [][]*x509.Certificate{second.Chain()}))
+	require.Equal(t, 2, source.treeHeads)
+	require.EqualValues(t, 2, verifier.TrustedTreeHead().TreeSize)
+
+	// requests to an unresponsive log time out.
+	source.blocked = true
+	third, err := log.NewIdentity(ctx, ca)
+	require.NoError(t, err)
+	require.ErrorIs(t, verifyPeer(nil, [][]*x509.Certificate{third.Chain()}), context.DeadlineExceeded)
+	require.NoError(t, verifyPeer(nil, [][]*x509.Certificate{first.Chain()}))
+}
@@ -0,0 +1,279 @@
+// Copyright (C) 2026 Storj Labs, Inc.
+// See LICENSE for copying information.
+
+// Package issuancelog implements an append-only log of issued certificates,
+// similar to certificate transparency logs.
+//
+// The log is a merkle tree as described in RFC 9162. It provides inclusion
+// proofs, which prove that a certificate is in the log, and consistency
+// proofs, which prove that the log only has been appended to. Tree heads are
+// signed by the log, such that peers can detect logs presenting different
+// views of the issued certificates.
+package issuancelog
+
+import (
+	"bufio"
+	"context"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/binary"
+	"errors"
+	"io"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/spacemonkeygo/monkit/v3"
+	"github.com/zeebo/errs"
+
+	"storj.io/common/identity"
+	"storj.io/common/signing"
+)
+
+var (
+	mon = monkit.Package()
+
+	// Error is the error class for the issuance log.
+	Error = errs.Class("issuancelog")
+
+	// ErrNotFound is returned when an entry isn't in the log.
+	ErrNotFound = errs.Class("issuancelog: not found")
+
+	// ErrVerify is returned when a signature or a proof is invalid.
+	ErrVerify = errs.Class("issuancelog: verification failed")
+)
+
+// maxEntrySize limits the size of log entries.
+const maxEntrySize = 1 << 20
+
+// Log is an append-only log of issued certificates.
+type Log struct {
+	signer signing.Signer
+
+	mu     sync.Mutex
+	tree   tree
+	index  map[Hash]uint64
+	file   *os.File
+	offset int64
+}
+
+// New returns an empty log, which is kept in memory.
+func New(signer signing.Signer) *Log {
+	return &Log{
+		signer: signer,
+		index:  map[Hash]uint64{},
+	}
+}
+
+// Open opens the log stored in the file, which is created when it doesn't
+// exist. Entries are appended to the file.
+func Open(path string, signer signing.Signer) (_ *Log, err error) {
+	file, err := os.OpenFile(path, os.O_RDWR|os.O_CREATE, 0644)
+	if err != nil {
+		return nil, Error.Wrap(err)
+	}
+	defer func() {
+		if err != nil {
+			err = errs.Combine(err, file.Close())
+		}
+	}()
+
+	log := New(signer)
+	log.file = file
+
+	r := bufio.NewReader(file)
+	for {
+		var header [4]byte
+		if _, err := io.ReadFull(r, header[:]); err != nil {
+			if errors.Is(err, io.EOF) || errors.Is(err, io.ErrUnexpectedEOF) {
+				break
+			}
+			return nil, Error.Wrap(err)
+		}
+
+		size := binary.BigEndian.Uint32(header[:])
+		if size > maxEntrySize {
+			return nil, Error.New("invalid entry size %d at offset %d", size, log.offset)
+		}
+		data := make([]byte, size)
+		if _, err := io.ReadFull(r, data); err != nil {
+			if errors.Is(err, io.EOF) || errors.Is(err, io.ErrUnexpectedEOF) {
+				break
+			}
+			return nil, Error.Wrap(err)
+		}
+
+		log.add(LeafHash(data))
+		log.offset += int64(len(header)) + int64(size)
+	}
+
+	// NB: an incomplete entry at the end is the result of a failed append.
+	if err := file.Truncate(log.offset); err != nil {
+		return nil, Error.Wrap(err)
+	}
+	if _, err := file.Seek(log.offset, io.SeekStart); err != nil {
+		return nil, Error.Wrap(err)
+	}
+
+	return log, nil
+}
+
+// Close closes the file of the log.
+func (log *Log) Close() error {
+	log.mu.Lock()
+	defer log.mu.Unlock()
+
+	if log.file == nil {
+		return nil
+	}
+	err := log.file.Close()
+	log.file = nil
+	return Error.Wrap(err)
+}
+
+// add adds the leaf hash to the tree.
+func (log *Log) add(leaf Hash) {
+	if _, ok := log.index[leaf]; !ok {
+		log.index[leaf] = log.tree.size()
+	}
+	log.tree.append(leaf)
+}
+
+// Append appends the entry to the log and returns its index.
+func (log *Log) Append(ctx context.Context, data []byte) (index uint64, err error) {
+	defer mon.Task()(&ctx)(&err)
+
+	if len(data) > maxEntrySize {
+		return 0, Error.New("entry too large: %d", len(data))
+	}
+
+	log.mu.Lock()
+	defer log.mu.Unlock()
+
+	if log.file != nil {
+		record := binary.BigEndian.AppendUint32(make([]byte, 0, 4+len(data)), uint32(len(data)))
+		record = append(record, data...)
+
+		_, err := log.file.Write(record)
+		if err == nil {
+			err = log.file.Sync()
+		}
+		if err != nil {
+			// NB: the entry mustn't be partially written, when the next one
+			// is appended.
+			_, seekErr := log.file.Seek(log.offset, io.SeekStart)
+			return 0, Error.Wrap(errs.Combine(err, log.file.Truncate(log.offset), seekErr))
+		}
+		log.offset += int64(len(record))
+	}
+
+	index = log.tree.size()
+	log.add(LeafHash(data))
+	return index, nil
+}
+
+// AppendCertificate appends the certificate to the log and returns its index.
+func (log *Log) AppendCertificate(ctx context.Context, cert *x509.Certificate) (index uint64, err error) {
+	return log.Append(ctx, cert.Raw)
+}
+
+// NewIdentity issues a new identity with the certificate authority, see
+// (*identity.FullCertificateAuthority).NewIdentity, and appends its leaf
+// certificate to the log.
+func (log *Log) NewIdentity(ctx context.Context, ca *identity.FullCertificateAuthority, exts ...pkix.Extension) (_ *identity.FullIdentity, err error) {
+	defer mon.Task()(&ctx)(&err)
+
+	ident, err := ca.NewIdentity(exts...)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := log.AppendCertificate(ctx, ident.Leaf); err != nil {
+		return nil, err
+	}
+	return ident, nil
+}
+
+// Sign signs the certificate with the certificate authority, see
+// (*identity.FullCertificateAuthority).Sign, and appends the signed
+// certificate to the log.
+func (log *Log) Sign(ctx context.Context, ca *identity.FullCertificateAuthority, cert *x509.Certificate) (_ *x509.Certificate, err error) {
+	defer mon.Task()(&ctx)(&err)
+
+	signed, err := ca.Sign(cert)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := log.AppendCertificate(ctx, signed); err != nil {
+		return nil, err
+	}
+	return signed, nil
+}
+
+// Size returns the number of entries in the log.
+func (log *Log) Size() uint64 {
+	log.mu.Lock()
+	defer log.mu.Unlock()
+
+	return log.tree.size()
+}
+
+// RootHash returns the root hash of the log at the size.
+func (log *Log) RootHash(size uint64) (Hash, error) {
+	log.mu.Lock()
+	defer log.mu.Unlock()
+
+	if size > log.tree.size() {
+		return Hash{}, Error.New("tree size %d larger than log size %d", size, log.tree.size())
+	}
+	return log.tree.hash(0, size), nil
+}
+
+// SignedTreeHead returns the signed tree head of the log at its current size.
+func (log *Log) SignedTreeHead(ctx context.Context) (_ *SignedTreeHead, err error) {
+	defer mon.Task()(&ctx)(&err)
+
+	log.mu.Lock()
+	size := log.tree.size()
+	root := log.tree.hash(0, size)
+	log.mu.Unlock()
+
+	return signTreeHead(ctx, log.signer, size, root, time.Now())
+}
+
+// InclusionProof returns the index of the entry with the leaf hash and the
+// proof of its inclusion in the log at the tree size.
+func (log *Log) InclusionProof(ctx context.Context, leaf Hash, treeSize uint64) (index uint64, proof []Hash, err error) {
+	defer mon.Task()(&ctx)(&err)
+
+	log.mu.Lock()
+	defer log.mu.Unlock()
+
+	if treeSize > log.tree.size() {
+		return 0, nil, Error.New("tree size %d larger than log size %d", treeSize, log.tree.size())
+	}
+
+	index, ok := log.index[leaf]
+	if !ok || index >= treeSize {
+		return 0, nil, ErrNotFound.New("entry not in log at tree size %d", treeSize)
+	}
+
+	return index, log.tree.inclusionProof(index, 0, treeSize), nil
+}
+
+// ConsistencyProof returns the proof that the log at the first tree size is a
+// prefix of the log at the second tree size.
+func (log *Log) ConsistencyProof(ctx context.Context, first, second uint64) (_ []Hash, err error) {
+	defer mon.Task()(&ctx)(&err)
+
+	log.mu.Lock()
+	defer log.mu.Unlock()
+
+	if first > second || second > log.tree.size() {
+		return nil, Error.New("invalid tree sizes %d and %d for log size %d", first, second, log.tree.size())
+	}
+	if first == 0 {
+		return nil, nil
+	}
+
+	return log.tree.consistencyProof(first, 0, second, true), nil
+}
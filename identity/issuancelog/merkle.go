@@ -0,0 +1,207 @@
+// Copyright (C) 2026 Storj Labs, Inc.
+// See LICENSE for copying information.
+
+package issuancelog
+
+import (
+	"crypto/sha256"
+	"math/bits"
+)
+
+// Hash is the hash of a node in the merkle tree of a log.
+type Hash [sha256.Size]byte
+
+// LeafHash returns the hash of a log entry.
+func LeafHash(data []byte) Hash {
+	h := sha256.New()
+	_, _ = h.Write([]byte{0})
+	_, _ = h.Write(data)
+
+	var hash Hash
+	h.Sum(hash[:0])
+	return hash
+}
+
+// nodeHash returns the hash of an inner node.
+func nodeHash(left, right Hash) Hash {
+	h := sha256.New()
+	_, _ = h.Write([]byte{1})
+	_, _ = h.Write(left[:])
+	_, _ = h.Write(right[:])
+
+	var hash Hash
+	h.Sum(hash[:0])
+	return hash
+}
+
+// splitPoint returns the largest power of two smaller than n, n > 1.
+func splitPoint(n uint64) uint64 {
+	return 1 << (bits.Len64(n-1) - 1)
+}
+
+// tree is an append-only merkle tree as described in RFC 9162. It keeps the
+// hashes of all complete subtrees, such that hashes and proofs only need a
+// logarithmic number of hash computations.
+type tree struct {
+	// levels[h][i] is the hash of the complete subtree with the leaves
+	// [i*2^h, (i+1)*2^h).
+	levels [][]Hash
+}
+
+// size returns the number of leaves.
+func (t *tree) size() uint64 {
+	if len(t.levels) == 0 {
+		return 0
+	}
+	return uint64(len(t.levels[0]))
+}
+
+// append adds a leaf hash to the tree.
+func (t *tree) append(leaf Hash) {
+	if len(t.levels) == 0 {
+		t.levels = append(t.levels, nil)
+	}
+	t.levels[0] = append(t.levels[0], leaf)
+
+	for h := 0; len(t.levels[h])%2 == 0; h++ {
+		n := len(t.levels[h])
+		parent := nodeHash(t.levels[h][n-2], t.levels[h][n-1])
+		if len(t.levels) == h+1 {
+			t.levels = append(t.levels, nil)
+		}
+		t.levels[h+1] = append(t.levels[h+1], parent)
+	}
+}
+
+// hash returns the hash of the subtree with the leaves [start, start+size).
+func (t *tree) hash(start, size uint64) Hash {
+	switch {
+	case size == 0:
+		return sha256.Sum256(nil)
+	case size&(size-1) == 0 && start%size == 0:
+		h := bits.TrailingZeros64(size)
+		return t.levels[h][start>>h]
+	}
+
+	k := splitPoint(size)
+	return nodeHash(t.hash(start, k), t.hash(start+k, size-k))
+}
+
+// inclusionProof returns the audit path of the leaf at index within the
+// subtree with the leaves [start, start+size).
+func (t *tree) inclusionProof(index, start, size uint64) []Hash {
+	if size <= 1 {
+		return nil
+	}
+
+	k := splitPoint(size)
+	if index < k {
+		return append(t.inclusionProof(index, start, k), t.hash(start+k, size-k))
+	}
+	return append(t.inclusionProof(index-k, start+k, size-k), t.hash(start, k))
+}
+
+// consistencyProof returns the proof that the tree with the first leaves is a
+// prefix of the subtree with the leaves [start, start+size).
+func (t *tree) consistencyProof(first, start, size uint64, complete bool) []Hash {
+	if first == size {
+		if complete {
+			return nil
+		}
+		return []Hash{t.hash(start, size)}
+	}
+
+	k := splitPoint(size)
+	if first <= k {
+		return append(t.consistencyProof(first, start, k, complete), t.hash(start+k, size-k))
+	}
+	return append(t.consistencyProof(first-k, start+k, size-k, false), t.hash(start, k))
+}
+
+// VerifyInclusion verifies that the leaf hash is included at index in the
+// tree with the size and root.
+func VerifyInclusion(leaf Hash, index, size uint64, proof []Hash, root Hash) error {
+	if index >= size {
+		return ErrVerify.New("index %d out of range for tree size %d", index, size)
+	}
+
+	fn, sn := index, size-1
+	r := leaf
+	for _, p := range proof {
+		if sn == 0 {
+			return ErrVerify.New("inclusion proof too long")
+		}
+		if fn&1 == 1 || fn == sn {
+			r = nodeHash(p, r)
+			for fn&1 == 0 && fn != 0 {
+				fn >>= 1
+				sn >>= 1
+			}
+		} else {
+			r = nodeHash(r, p)
+		}
+		fn >>= 1
+		sn >>= 1
+	}
+
+	if sn != 0 || r != root {
+		return ErrVerify.New("invalid inclusion proof")
+	}
+	return nil
+}
+
+// VerifyConsistency verifies that the tree with the first size and root is a
+// prefix of the tree with the second size and root.
+func VerifyConsistency(firstSize, secondSize uint64, firstRoot, secondRoot Hash, proof []Hash) error {
+	switch {
+	case firstSize > secondSize:
+		return ErrVerify.New("tree size %d is smaller than %d", secondSize, firstSize)
+	case firstSize == secondSize:
+		if len(proof) != 0 || firstRoot != secondRoot {
+			return ErrVerify.New("invalid consistency proof")
+		}
+		return nil
+	case firstSize == 0:
+		if len(proof) != 0 {
+			return ErrVerify.New("invalid consistency proof")
+		}
+		return nil
+	}
+
+	if firstSize&(firstSize-1) == 0 {
+		proof = append([]Hash{firstRoot}, proof...)
+	}
+	if len(proof) == 0 {
+		return ErrVerify.New("invalid consistency proof")
+	}
+
+	fn, sn := firstSize-1, secondSize-1
+	for fn&1 == 1 {
+		fn >>= 1
+		sn >>= 1
+	}
+
+	fr, sr := proof[0], proof[0]
+	for _, c := range proof[1:] {
+		if sn == 0 {
+			return ErrVerify.New("consistency proof too long")
+		}
+		if fn&1 == 1 || fn == sn {
+			fr = nodeHash(c, fr)
+			sr = nodeHash(c, sr)
+			for fn&1 == 0 && fn != 0 {
+				fn >>= 1
+				sn >>= 1
+			}
+		} else {
+			sr = nodeHash(sr, c)
+		}
+		fn >>= 1
+		sn >>= 1
+	}
+
+	if sn != 0 || fr != firstRoot || sr != secondRoot {
+		return ErrVerify.New("invalid consistency proof")
+	}
+	return nil
+}
@@ -0,0 +1,92 @@
+// Copyright (C) 2026 Storj Labs, Inc.
+// See LICENSE for copying information.
+
+package issuancelog
+
+import (
+	"context"
+	"encoding/binary"
+	"time"
+
+	"storj.io/common/signing"
+	"storj.io/common/storj"
+)
+
+// treeHeadPrefix separates signatures of tree heads from other signatures.
+const treeHeadPrefix = "storj-issuance-log-tree-head-v1\x00"
+
+// tbsSize is the size of the signed part of an encoded tree head.
+const tbsSize = len(treeHeadPrefix) + len(storj.NodeID{}) + 8 + 8 + len(Hash{})
+
+// SignedTreeHead is the root of the merkle tree of a log at some size, signed
+// by the log.
+type SignedTreeHead struct {
+	LogID     storj.NodeID
+	TreeSize  uint64
+	Timestamp time.Time
+	RootHash  Hash
+	Signature []byte
+}
+
+// TBSBytes returns the bytes which are signed.
+func (sth *SignedTreeHead) TBSBytes() []byte {
+	data := make([]byte, 0, tbsSize)
+	data = append(data, treeHeadPrefix...)
+	data = append(data, sth.LogID[:]...)
+	data = binary.BigEndian.AppendUint64(data, sth.TreeSize)
+	data = binary.BigEndian.AppendUint64(data, uint64(sth.Timestamp.UnixNano()))
+	data = append(data, sth.RootHash[:]...)
+	return data
+}
+
+// Marshal serializes the signed tree head.
+func (sth *SignedTreeHead) Marshal() []byte {
+	return append(sth.TBSBytes(), sth.Signature...)
+}
+
+// UnmarshalSignedTreeHead parses a signed tree head serialized with Marshal.
+func UnmarshalSignedTreeHead(data []byte) (*SignedTreeHead, error) {
+	if len(data) < tbsSize || string(data[:len(treeHeadPrefix)]) != treeHeadPrefix {
+		return nil, Error.New("invalid signed tree head")
+	}
+	data = data[len(treeHeadPrefix):]
+
+	sth := &SignedTreeHead{}
+	copy(sth.LogID[:], data)
+	data = data[len(sth.LogID):]
+	sth.TreeSize = binary.BigEndian.Uint64(data)
+	sth.Timestamp = time.Unix(0, int64(binary.BigEndian.Uint64(data[8:]))).UTC()
+	copy(sth.RootHash[:], data[16:])
+	sth.Signature = append([]byte(nil), data[16+len(sth.RootHash):]...)
+
+	return sth, nil
+}
+
+// signTreeHead signs the tree head.
+func signTreeHead(ctx context.Context, signer signing.Signer, size uint64, root Hash, now time.Time) (*SignedTreeHead, error) {
+	sth := &SignedTreeHead{
+		LogID:     signer.ID(),
+		TreeSize:  size,
+		Timestamp: now.UTC(),
+		RootHash:  root,
+	}
+
+	signature, err := signer.HashAndSign(ctx, sth.TBSBytes())
+	if err != nil {
+		return nil, Error.Wrap(err)
+	}
+	sth.Signature = signature
+
+	return sth, nil
+}
+
+// VerifySignedTreeHead verifies that the tree head is signed by the log.
+func VerifySignedTreeHead(ctx context.Context, log signing.Signee, sth *SignedTreeHead) error {
+	if sth.LogID != log.ID() {
+		return ErrVerify.New("tree head of log %s, expected %s", sth.LogID, log.ID())
+	}
+	if err := log.HashAndVerifySignature(ctx, sth.TBSBytes(), sth.Signature); err != nil {
+		return ErrVerify.Wrap(err)
+	}
+	return nil
+}
@@ -0,0 +1,181 @@
+// Copyright (C) 2026 Storj Labs, Inc.
+// See LICENSE for copying information.
+
+package issuancelog
+
+import (
+	"context"
+	"crypto/x509"
+	"sync"
+	"time"
+
+	"storj.io/common/peertls"
+	"storj.io/common/signing"
+)
+
+// Source provides signed tree heads and proofs of a log, e.g. a *Log or a
+// client of a remote log.
+type Source interface {
+	SignedTreeHead(ctx context.Context) (*SignedTreeHead, error)
+	InclusionProof(ctx context.Context, leaf Hash, treeSize uint64) (index uint64, proof []Hash, err error)
+	ConsistencyProof(ctx context.Context, first, second uint64) ([]Hash, error)
+}
+
+var _ Source = (*Log)(nil)
+
+// VerifierConfig configures a Verifier.
+type VerifierConfig struct {
+	// Timeout limits the requests to the log when verifying a peer during a
+	// handshake. DefaultVerifierTimeout is used when it's zero.
+	Timeout time.Duration
+	// MaxTreeHeadAge is how long a verified tree head is used, before fetching
+	// the current tree head of the log. DefaultMaxTreeHeadAge is used when it's
+	// zero.
+	MaxTreeHeadAge time.Duration
+}
+
+const (
+	// DefaultVerifierTimeout is the default VerifierConfig.Timeout.
+	DefaultVerifierTimeout = 10 * time.Second
+	// DefaultMaxTreeHeadAge is the default VerifierConfig.MaxTreeHeadAge.
+	DefaultMaxTreeHeadAge = time.Minute
+)
+
+// Verifier verifies that certificates are included in a log.
+//
+// It keeps the latest verified tree head and only accepts newer tree heads
+// when the log proves they are consistent with it, such that a log can't
+// present different views of its entries to the verifier.
+type Verifier struct {
+	source Source
+	log    signing.Signee
+	config VerifierConfig
+
+	mu         sync.Mutex
+	trusted    *SignedTreeHead
+	verifiedAt time.Time
+}
+
+// NewVerifier returns a verifier for the log, which signs its tree heads with
+// the key of the signee.
+func NewVerifier(source Source, log signing.Signee, config VerifierConfig) *Verifier {
+	if config.Timeout <= 0 {
+		config.Timeout = DefaultVerifierTimeout
+	}
+	if config.MaxTreeHeadAge <= 0 {
+		config.MaxTreeHeadAge = DefaultMaxTreeHeadAge
+	}
+	return &Verifier{
+		source: source,
+		log:    log,
+		config: config,
+	}
+}
+
+// TrustedTreeHead returns the latest verified tree head, or nil when no tree
+// head has been verified yet.
+func (verifier *Verifier) TrustedTreeHead() *SignedTreeHead {
+	verifier.mu.Lock()
+	defer verifier.mu.Unlock()
+
+	return verifier.trusted
+}
+
+// Verify verifies that the certificate is included in the log.
+//
+// The trusted tree head is reused while it's fresh. When the certificate
+// isn't included in it, e.g. because it was logged afterwards, the current
+// tree head of the log is fetched.
+func (verifier *Verifier) Verify(ctx context.Context, cert *x509.Certificate) (err error) {
+	defer mon.Task()(&ctx)(&err)
+
+	leaf := LeafHash(cert.Raw)
+
+	if sth := verifier.freshTreeHead(); sth != nil {
+		if err := verifier.verifyInclusion(ctx, leaf, sth); err == nil {
+			return nil
+		}
+	}
+
+	sth, err := verifier.update(ctx)
+	if err != nil {
+		return err
+	}
+	return verifier.verifyInclusion(ctx, leaf, sth)
+}
+
+// verifyInclusion verifies that the leaf is included in the tree head.
+func (verifier *Verifier) verifyInclusion(ctx context.Context, leaf Hash, sth *SignedTreeHead) error {
+	index, proof, err := verifier.source.InclusionProof(ctx, leaf, sth.TreeSize)
+	if err != nil {
+		return err
+	}
+	return VerifyInclusion(leaf, index, sth.TreeSize, proof, sth.RootHash)
+}
+
+// freshTreeHead returns the trusted tree head, when it was verified within
+// MaxTreeHeadAge.
+func (verifier *Verifier) freshTreeHead() *SignedTreeHead {
+	verifier.mu.Lock()
+	defer verifier.mu.Unlock()
+
+	if verifier.trusted == nil || time.Since(verifier.verifiedAt) > verifier.config.MaxTreeHeadAge {
+		return nil
+	}
+	return verifier.trusted
+}
+
+// update fetches the current tree head of the log and verifies it against the
+// trusted tree head.
+//
+// NB: the proofs are fetched without holding the lock, hence the consistency
+// is verified again when the trusted tree head changed in the meantime.
+func (verifier *Verifier) update(ctx context.Context) (_ *SignedTreeHead, err error) {
+	sth, err := verifier.source.SignedTreeHead(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if err := VerifySignedTreeHead(ctx, verifier.log, sth); err != nil {
+		return nil, err
+	}
+
+	for {
+		trusted := verifier.TrustedTreeHead()
+		if trusted != nil {
+			if sth.TreeSize < trusted.TreeSize {
+				// NB: the tree head may be outdated, e.g. when it's cached.
+				return trusted, nil
+			}
+
+			proof, err := verifier.source.ConsistencyProof(ctx, trusted.TreeSize, sth.TreeSize)
+			if err != nil {
+				return nil, err
+			}
+			if err := VerifyConsistency(trusted.TreeSize, sth.TreeSize, trusted.RootHash, sth.RootHash, proof); err != nil {
+				return nil, err
+			}
+		}
+
+		verifier.mu.Lock()
+		if verifier.trusted == trusted {
+			verifier.trusted = sth
+			verifier.verifiedAt = time.Now()
+			verifier.mu.Unlock()
+			return sth, nil
+		}
+		verifier.mu.Unlock()
+	}
+}
+
+// VerifyPeer returns a peer certificate verification function, which requires
+// the leaf certificate of the peer to be included in the log. It can be added
+// to tlsopts.VerificationFuncs. The requests to the log are limited by the
+// configured timeout.
+func (verifier *Verifier) VerifyPeer() peertls.PeerCertVerificationFunc {
+	return func(_ [][]byte, parsedChains [][]*x509.Certificate) error {
+		ctx, cancel := context.WithTimeout(context.Background(), verifier.config.Timeout)
+		defer cancel()
+
+		return verifier.Verify(ctx, parsedChains[0][peertls.LeafIndex])
+	}
+}
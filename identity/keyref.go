@@ -0,0 +1,129 @@
+// Copyright (C) 2026 Storj Labs, Inc.
+// See LICENSE for copying information.
+
+package identity
+
+import (
+	"crypto"
+	"net/url"
+	"strings"
+	"sync"
+
+	"github.com/zeebo/errs"
+
+	"storj.io/common/pkcrypto"
+)
+
+// ErrKeyRef is the error class for key references.
+var ErrKeyRef = errs.Class("key reference")
+
+// KeyOpener opens the signer for a key reference, e.g.
+// "unix:///run/storj/signer.sock" or "pkcs11:token=storj;object=identity".
+type KeyOpener func(ref *url.URL) (crypto.Signer, error)
+
+var keySchemes struct {
+	mu      sync.RWMutex
+	openers map[string]KeyOpener
+}
+
+// RegisterKeyScheme registers the opener for key references with the scheme.
+// Key paths in Config and FullCAConfig with a registered scheme refer to keys,
+// which aren't held in memory, e.g. keys in a hardware token or an external
+// signing daemon.
+//
+// Packages providing key references usually register them in init, see
+// storj.io/common/identity/remotesigner for an example.
+func RegisterKeyScheme(scheme string, open KeyOpener) {
+	keySchemes.mu.Lock()
+	defer keySchemes.mu.Unlock()
+
+	if keySchemes.openers == nil {
+		keySchemes.openers = map[string]KeyOpener{}
+	}
+	keySchemes.openers[strings.ToLower(scheme)] = open
+}
+
+// IsKeyRef returns whether the key path is a reference to a key with a
+// registered scheme, rather than the path of a key file.
+func IsKeyRef(keyPath string) bool {
+	_, _, ok := lookupKeyRef(keyPath)
+	return ok
+}
+
+// OpenKeyRef opens the signer for the key reference.
+func OpenKeyRef(keyRef string) (crypto.Signer, error) {
+	ref, open, ok := lookupKeyRef(keyRef)
+	if !ok {
+		return nil, ErrKeyRef.New("unknown key reference %q", keyRef)
+	}
+
+	signer, err := open(ref)
+	if err != nil {
+		return nil, ErrKeyRef.Wrap(err)
+	}
+	return signer, nil
+}
+
+func lookupKeyRef(keyPath string) (*url.URL, KeyOpener, bool) {
+	ref, err := url.Parse(keyPath)
+	// NB: single letter schemes are windows drive letters.
+	if err != nil || len(ref.Scheme) < 2 {
+		return nil, nil, false
+	}
+
+	keySchemes.mu.RLock()
+	open, ok := keySchemes.openers[ref.Scheme]
+	keySchemes.mu.RUnlock()
+
+	return ref, open, ok
+}
+
+// FullIdentityFromSigner loads a FullIdentity from a certificate chain and the
+// signer for the leaf key.
+func FullIdentityFromSigner(chainPEM []byte, signer crypto.Signer) (*FullIdentity, error) {
+	peerIdent, err := PeerIdentityFromPEM(chainPEM)
+	if err != nil {
+		return nil, err
+	}
+
+	if !pkcrypto.PublicKeyEqual(signer.Public(), peerIdent.Leaf.PublicKey) {
+		return nil, ErrKeyRef.New("signer doesn't match the leaf certificate")
+	}
+
+	return &FullIdentity{
+		RestChain: peerIdent.RestChain,
+		CA:        peerIdent.CA,
+		Leaf:      peerIdent.Leaf,
+		Key:       signer,
+		ID:        peerIdent.ID,
+	}, nil
+}
+
+// FullCertificateAuthorityFromSigner loads a FullCertificateAuthority from a
+// certificate chain and the signer for the CA key.
+func FullCertificateAuthorityFromSigner(chainPEM []byte, signer crypto.Signer) (*FullCertificateAuthority, error) {
+	peerCA, err := PeerCertificateAuthorityFromPEM(chainPEM)
+	if err != nil {
+		return nil, err
+	}
+
+	if !pkcrypto.PublicKeyEqual(signer.Public(), peerCA.Cert.PublicKey) {
+		return nil, ErrKeyRef.New("signer doesn't match the CA certificate")
+	}
+
+	return &FullCertificateAuthority{
+		RestChain: peerCA.RestChain,
+		Cert:      peerCA.Cert,
+		Key:       signer,
+		ID:        peerCA.ID,
+	}, nil
+}
+
+// checkSavableToKeyRef returns an error when the key would be lost when saving
+// it to a key reference, i.e. when it's held in memory.
+func checkSavableToKeyRef(keyRef string, key crypto.PrivateKey) error {
+	if _, err := pkcrypto.PrivateKeyToPKCS8(key); err == nil {
+		return ErrKeyRef.New("can't save in-memory key to %q", keyRef)
+	}
+	return nil
+}
@@ -0,0 +1,159 @@
+// Copyright (C) 2026 Storj Labs, Inc.
+// See LICENSE for copying information.
+
+package remotesigner
+
+import (
+	"crypto"
+	"crypto/ed25519"
+	"crypto/rsa"
+	"encoding/binary"
+	"errors"
+	"io"
+	"net"
+	"net/url"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"github.com/zeebo/errs"
+
+	"storj.io/common/identity"
+	"storj.io/common/pkcrypto"
+)
+
+// Timeout is the time limit for requests to the signer server.
+const Timeout = 30 * time.Second
+
+func init() {
+	identity.RegisterKeyScheme("unix", Open)
+}
+
+// Signer is a crypto.Signer, which signs with the key of a signer server.
+type Signer struct {
+	path   string
+	public crypto.PublicKey
+
+	mu   sync.Mutex
+	conn net.Conn
+}
+
+// Open connects to the signer server of a key reference, e.g.
+// "unix:///run/storj/identity.sock" or "unix:identity.sock".
+func Open(ref *url.URL) (crypto.Signer, error) {
+	path := ref.Path
+	if ref.Opaque != "" {
+		path = ref.Opaque
+	}
+	if path == "" {
+		return nil, Error.New("missing socket path in %q", ref.String())
+	}
+	return Dial(filepath.FromSlash(path))
+}
+
+// Dial connects to the signer server listening on the unix socket.
+func Dial(path string) (*Signer, error) {
+	signer := &Signer{path: path}
+
+	response, err := signer.roundTrip([]byte{opPublicKey})
+	if err != nil {
+		return nil, errs.Combine(err, signer.Close())
+	}
+
+	signer.public, err = pkcrypto.PublicKeyFromPKIX(response)
+	if err != nil {
+		return nil, errs.Combine(Error.Wrap(err), signer.Close())
+	}
+
+	return signer, nil
+}
+
+// Public returns the public key of the signer.
+func (signer *Signer) Public() crypto.PublicKey { return signer.public }
+
+// Sign signs the digest with the key of the signer server. Ed25519 options with
+// a context aren't supported.
+func (signer *Signer) Sign(_ io.Reader, digest []byte, opts crypto.SignerOpts) ([]byte, error) {
+	saltLength := noPSS
+	switch opts := opts.(type) {
+	case *rsa.PSSOptions:
+		saltLength = int32(opts.SaltLength)
+	case *ed25519.Options:
+		if opts.Context != "" {
+			return nil, Error.New("ed25519 context is not supported")
+		}
+	}
+
+	request := make([]byte, 0, 6+len(digest))
+	request = append(request, opSign, byte(opts.HashFunc()))
+	request = binary.BigEndian.AppendUint32(request, uint32(saltLength))
+	request = append(request, digest...)
+
+	return signer.roundTrip(request)
+}
+
+// Close closes the connection to the signer server.
+func (signer *Signer) Close() error {
+	signer.mu.Lock()
+	defer signer.mu.Unlock()
+
+	if signer.conn == nil {
+		return nil
+	}
+	err := signer.conn.Close()
+	signer.conn = nil
+	return Error.Wrap(err)
+}
+
+// roundTrip sends the request and returns the response. The connection to the
+// signer server is established again once, when it has been closed.
+func (signer *Signer) roundTrip(request []byte) ([]byte, error) {
+	signer.mu.Lock()
+	defer signer.mu.Unlock()
+
+	reused := signer.conn != nil
+	response, err := signer.roundTripConn(request)
+	if err != nil && reused {
+		response, err = signer.roundTripConn(request)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if len(response) == 0 {
+		return nil, Error.New("empty response")
+	}
+	if response[0] != statusOK {
+		return nil, Error.New("%s", response[1:])
+	}
+	return response[1:], nil
+}
+
+func (signer *Signer) roundTripConn(request []byte) (_ []byte, err error) {
+	if signer.conn == nil {
+		signer.conn, err = net.DialTimeout("unix", signer.path, Timeout)
+		if err != nil {
+			return nil, Error.Wrap(err)
+		}
+	}
+
+	defer func() {
+		if err != nil {
+			_ = signer.conn.Close()
+			signer.conn = nil
+		}
+	}()
+
+	if err := signer.conn.SetDeadline(time.Now().Add(Timeout)); err != nil {
+		return nil, Error.Wrap(err)
+	}
+	if err := writeFrame(signer.conn, request); err != nil {
+		return nil, Error.Wrap(err)
+	}
+
+	response, err := readFrame(signer.conn)
+	if errors.Is(err, io.EOF) {
+		err = io.ErrUnexpectedEOF
+	}
+	return response, Error.Wrap(err)
+}
@@ -0,0 +1,76 @@
+// Copyright (C) 2026 Storj Labs, Inc.
+// See LICENSE for copying information.
+
+// Package remotesigner implements signing with a key held by another process,
+// which listens on a unix socket.
+//
+// It's a reference implementation for keys that aren't held in memory, such
+// as keys in a hardware token. Importing the package registers the "unix" key
+// scheme, hence identities can be loaded with key paths like
+// "unix:///run/storj/identity.sock".
+//
+// The protocol consists of length-prefixed frames. Requests start with an
+// operation byte, responses with a status byte:
+//
+//	request:  opPublicKey
+//	request:  opSign | hash | pss salt length (int32, -1 without pss) | digest
+//	response: statusOK | pkix public key or signature
+//	response: statusError | error message
+package remotesigner
+
+import (
+	"encoding/binary"
+	"io"
+
+	"github.com/zeebo/errs"
+)
+
+// Error is the error class for the remote signer.
+var Error = errs.Class("remotesigner")
+
+const (
+	opPublicKey = byte(1)
+	opSign      = byte(2)
+
+	statusOK    = byte(0)
+	statusError = byte(1)
+
+	// maxFrameSize limits the size of requests and responses.
+	maxFrameSize = 1 << 20
+
+	// noPSS is the salt length of sign requests without pss options.
+	noPSS = int32(-1)
+)
+
+// writeFrame writes the payload prefixed with its length.
+func writeFrame(w io.Writer, payload []byte) error {
+	if len(payload) > maxFrameSize {
+		return Error.New("frame too large: %d", len(payload))
+	}
+
+	frame := make([]byte, 4, 4+len(payload))
+	binary.BigEndian.PutUint32(frame, uint32(len(payload)))
+	frame = append(frame, payload...)
+
+	_, err := w.Write(frame)
+	return err
+}
+
+// readFrame reads a payload written with writeFrame.
+func readFrame(r io.Reader) ([]byte, error) {
+	var header [4]byte
+	if _, err := io.ReadFull(r, header[:]); err != nil {
+		return nil, err
+	}
+
+	size := binary.BigEndian.Uint32(header[:])
+	if size > maxFrameSize {
+		return nil, Error.New("frame too large: %d", size)
+	}
+
+	payload := make([]byte, size)
+	if _, err := io.ReadFull(r, payload); err != nil {
+		return nil, err
+	}
+	return payload, nil
+}
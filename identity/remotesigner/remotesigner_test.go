@@ -0,0 +1,213 @@
+// Copyright (C) 2026 Storj Labs, Inc.
+// See LICENSE for copying information.
+
+package remotesigner_test
+
+import (
+	"context"
+	"crypto"
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"net"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"storj.io/common/identity"
+	"storj.io/common/identity/remotesigner"
+	"storj.io/common/identity/testidentity"
+	"storj.io/common/pb"
+	"storj.io/common/peertls"
+	"storj.io/common/peertls/tlsopts"
+	"storj.io/common/pkcrypto"
+	"storj.io/common/signing"
+	"storj.io/common/storj"
+	"storj.io/common/testcontext"
+	"storj.io/common/testrand"
+)
+
+// serve serves the key on a unix socket and returns the path of the socket.
+func serve(ctx *testcontext.Context, t *testing.T, key crypto.PrivateKey) string {
+	server, err := remotesigner.NewServer(key.(crypto.Signer))
+	require.NoError(t, err)
+
+	path := filepath.Join(ctx.Dir("sock"), fmt.Sprintf("%x.sock", testrand.BytesInt(4)))
+	listener, err := net.Listen("unix", path)
+	require.NoError(t, err)
+
+	serveCtx, cancel := context.WithCancel(ctx)
+	t.Cleanup(cancel)
+	ctx.Go(func() error { return server.Serve(serveCtx, listener) })
+
+	return path
+}
+
+func TestSigner(t *testing.T) {
+	ctx := testcontext.New(t)
+
+	rsaKey, err := pkcrypto.GeneratePrivateRSAKey(pkcrypto.StorjRSAKeyBits)
+	require.NoError(t, err)
+
+	keys := []crypto.PrivateKey{rsaKey}
+	for _, version := range storj.IDVersions {
+		key, err := version.NewPrivateKey()
+		require.NoError(t, err)
+		keys = append(keys, key)
+	}
+
+	for _, key := range keys {
+		signer, err := remotesigner.Dial(serve(ctx, t, key))
+		require.NoError(t, err)
+		defer ctx.Check(signer.Close)
+
+		publicKey, err := pkcrypto.PublicKeyFromPrivate(key)
+		require.NoError(t, err)
+		require.True(t, pkcrypto.PublicKeyEqual(publicKey, signer.Public()), "%T", key)
+
+		// signatures match the signatures of the key.
+		for _, data := range []string{"", "data"} {
+			signature, err := pkcrypto.HashAndSign(signer, []byte(data))
+			require.NoError(t, err, "%T", key)
+			require.NoError(t, pkcrypto.HashAndVerifySignature(publicKey, []byte(data), signature), "%T", key)
+		}
+
+		// keys held remotely can't be used for hmac.
+		_, err = pkcrypto.SignHMACSHA256(signer, []byte("data"))
+		require.True(t, pkcrypto.ErrUnsupportedKey.Has(err), err)
+	}
+}
+
+func TestSigner_Reconnect(t *testing.T) {
+	ctx := testcontext.New(t)
+
+	key, err := pkcrypto.GeneratePrivateKey()
+	require.NoError(t, err)
+	server, err := remotesigner.NewServer(key.(crypto.Signer))
+	require.NoError(t, err)
+
+	path := filepath.Join(ctx.Dir("sock"), "signer.sock")
+	start := func() context.CancelFunc {
+		listener, err := net.Listen("unix", path)
+		require.NoError(t, err)
+
+		serveCtx, cancel := context.WithCancel(ctx)
+		done := make(chan struct{})
+		go func() {
+			defer close(done)
+			_ = server.Serve(serveCtx, listener)
+		}()
+		return func() { cancel(); <-done }
+	}
+
+	stop := start()
+	signer, err := remotesigner.Dial(path)
+	require.NoError(t, err)
+	defer ctx.Check(signer.Close)
+
+	_, err = pkcrypto.HashAndSign(signer, []byte("data"))
+	require.NoError(t, err)
+
+	// requests fail while the server isn't running.
+	stop()
+	_, err = pkcrypto.HashAndSign(signer, []byte("data"))
+	require.Error(t, err)
+
+	// and succeed again once it's restarted.
+	stop = start()
+	defer stop()
+	_, err = pkcrypto.HashAndSign(signer, []byte("data"))
+	require.NoError(t, err)
+}
+
+func TestIdentity(t *testing.T) {
+	ctx := testcontext.New(t)
+
+	for _, version := range storj.IDVersions {
+		ident := testidentity.MustPregeneratedSignedIdentity(0, version)
+		signerCA := testidentity.NewPregeneratedSigner(version)
+
+		identCfg := identity.Config{
+			CertPath: ctx.File("identity", fmt.Sprint(version.Number), "identity.cert"),
+			KeyPath:  "unix://" + filepath.ToSlash(serve(ctx, t, ident.Key)),
+		}
+		require.True(t, identity.IsKeyRef(identCfg.KeyPath))
+
+		// in-memory keys would be lost.
+		require.True(t, identity.ErrKeyRef.Has(identCfg.Save(ident)))
+
+		require.NoError(t, identity.PeerConfig{CertPath: identCfg.CertPath}.Save(ident.PeerIdentity()))
+		status, err := identity.SetupConfig{CertPath: identCfg.CertPath, KeyPath: identCfg.KeyPath}.Status()
+		require.NoError(t, err)
+		require.Equal(t, identity.CertKey, status)
+
+		remoteIdent, err := identCfg.Load()
+		require.NoError(t, err)
+		require.Equal(t, ident.ID, remoteIdent.ID)
+		require.NoError(t, identCfg.Save(remoteIdent))
+
+		// the key of another identity doesn't match the certificate.
+		other := testidentity.MustPregeneratedSignedIdentity(1, version)
+		_, err = identity.Config{
+			CertPath: identCfg.CertPath,
+			KeyPath:  "unix://" + filepath.ToSlash(serve(ctx, t, other.Key)),
+		}.Load()
+		require.Error(t, err)
+
+		t.Run("tls", func(t *testing.T) {
+			config := tlsopts.Config{PeerIDVersions: "*"}
+			clientOpts, err := tlsopts.NewOptions(remoteIdent, config, nil)
+			require.NoError(t, err)
+			serverOpts, err := tlsopts.NewOptions(other, config, nil)
+			require.NoError(t, err)
+
+			clientConn, serverConn := net.Pipe()
+			defer ctx.Check(clientConn.Close)
+			defer ctx.Check(serverConn.Close)
+
+			// NB: the server requires the client certificate, which makes the
+			// client sign with the remote key.
+			server := tls.Server(serverConn, serverOpts.ServerTLSConfig())
+			ctx.Go(func() error { return server.HandshakeContext(ctx) })
+
+			client := tls.Client(clientConn, clientOpts.ClientTLSConfig(other.ID))
+			require.NoError(t, client.HandshakeContext(ctx))
+		})
+
+		t.Run("signing", func(t *testing.T) {
+			signed, err := signing.SignOrderLimit(ctx, signing.SignerFromFullIdentity(remoteIdent), &pb.OrderLimit{
+				SerialNumber:    testrand.SerialNumber(),
+				SatelliteId:     remoteIdent.ID,
+				StorageNodeId:   testrand.NodeID(),
+				PieceId:         testrand.PieceID(),
+				Limit:           1000,
+				Action:          pb.PieceAction_GET,
+				OrderCreation:   time.Now(),
+				OrderExpiration: time.Now().Add(time.Hour),
+			})
+			require.NoError(t, err)
+			require.NoError(t, signing.VerifyOrderLimitSignature(ctx, signing.SigneeFromPeerIdentity(ident.PeerIdentity()), signed))
+		})
+
+		t.Run("certificate authority", func(t *testing.T) {
+			caCfg := identity.FullCAConfig{
+				CertPath: ctx.File("ca", fmt.Sprint(version.Number), "ca.cert"),
+				KeyPath:  "unix://" + filepath.ToSlash(serve(ctx, t, signerCA.Key)),
+			}
+			require.NoError(t, identity.PeerCAConfig{CertPath: caCfg.CertPath}.Save(signerCA.PeerCA()))
+
+			ca, err := caCfg.Load()
+			require.NoError(t, err)
+			require.Equal(t, signerCA.ID, ca.ID)
+
+			unsigned := testidentity.MustPregeneratedIdentity(0, version)
+			signedCert, err := ca.Sign(unsigned.CA)
+			require.NoError(t, err)
+
+			err = peertls.VerifyCAWhitelist([]*x509.Certificate{signerCA.Cert})(nil, [][]*x509.Certificate{{unsigned.Leaf, signedCert}})
+			require.NoError(t, err)
+		})
+	}
+}
@@ -0,0 +1,122 @@
+// Copyright (C) 2026 Storj Labs, Inc.
+// See LICENSE for copying information.
+
+package remotesigner
+
+import (
+	"context"
+	"crypto"
+	"crypto/rand"
+	"crypto/rsa"
+	"encoding/binary"
+	"errors"
+	"net"
+	"sync"
+
+	"storj.io/common/pkcrypto"
+)
+
+// Server serves signing requests for a key.
+type Server struct {
+	key       crypto.Signer
+	publicKey []byte
+}
+
+// NewServer returns a server signing with the key.
+func NewServer(key crypto.Signer) (*Server, error) {
+	publicKey, err := pkcrypto.PublicKeyToPKIX(key.Public())
+	if err != nil {
+		return nil, Error.Wrap(err)
+	}
+	return &Server{
+		key:       key,
+		publicKey: publicKey,
+	}, nil
+}
+
+// Serve serves requests on the listener until the context is canceled. The
+// listener is closed when Serve returns.
+func (server *Server) Serve(ctx context.Context, listener net.Listener) error {
+	var wg sync.WaitGroup
+	defer wg.Wait()
+
+	stop := context.AfterFunc(ctx, func() { _ = listener.Close() })
+	defer func() {
+		if stop() {
+			_ = listener.Close()
+		}
+	}()
+
+	for {
+		conn, err := listener.Accept()
+		if err != nil {
+			if ctx.Err() != nil {
+				return nil
+			}
+			return Error.Wrap(err)
+		}
+
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			server.serveConn(ctx, conn)
+		}()
+	}
+}
+
+// serveConn serves the requests of a connection until it's closed.
+func (server *Server) serveConn(ctx context.Context, conn net.Conn) {
+	stop := context.AfterFunc(ctx, func() { _ = conn.Close() })
+	defer func() {
+		if stop() {
+			_ = conn.Close()
+		}
+	}()
+
+	for {
+		request, err := readFrame(conn)
+		if err != nil {
+			return
+		}
+
+		response, err := server.handle(request)
+		if err != nil {
+			response = append([]byte{statusError}, err.Error()...)
+		} else {
+			response = append([]byte{statusOK}, response...)
+		}
+
+		if err := writeFrame(conn, response); err != nil {
+			return
+		}
+	}
+}
+
+// handle returns the response for a request.
+func (server *Server) handle(request []byte) ([]byte, error) {
+	if len(request) == 0 {
+		return nil, errors.New("empty request")
+	}
+
+	switch request[0] {
+	case opPublicKey:
+		return server.publicKey, nil
+	case opSign:
+		if len(request) < 6 {
+			return nil, errors.New("invalid sign request")
+		}
+
+		hash := crypto.Hash(request[1])
+		saltLength := int32(binary.BigEndian.Uint32(request[2:6]))
+		digest := request[6:]
+
+		var opts crypto.SignerOpts = hash
+		if saltLength != noPSS {
+			opts = &rsa.PSSOptions{SaltLength: int(saltLength), Hash: hash}
+		}
+
+		return server.key.Sign(rand.Reader, digest, opts)
+	default:
+		return nil, errors.New("unknown operation")
+	}
+}
@@ -41,10 +41,22 @@ func NewTestCA(ctx context.Context) (*identity.FullCertificateAuthority, error)
 	})
 }
 
+// defaultIDVersions returns the identity versions which peers accept by
+// default. Newer versions are opt-in and are tested explicitly.
+func defaultIDVersions() map[storj.IDVersionNumber]storj.IDVersion {
+	versions := make(map[storj.IDVersionNumber]storj.IDVersion)
+	for number, version := range storj.IDVersions {
+		if storj.IDVersionInVersions(number, "latest") == nil {
+			versions[number] = version
+		}
+	}
+	return versions
+}
+
 // IdentityVersionsTest runs the `IdentityTest` for each identity
-// version, with an unsigned identity.
+// version accepted by default, with an unsigned identity.
 func IdentityVersionsTest(t *testing.T, test IdentityTest) {
-	for vn, v := range storj.IDVersions {
+	for vn, v := range defaultIDVersions() {
 		versionNumber := vn
 		version := v
 		t.Run(fmt.Sprintf("identity version %d", versionNumber), func(t *testing.T) {
@@ -58,9 +70,9 @@ func IdentityVersionsTest(t *testing.T, test IdentityTest) {
 }
 
 // SignedIdentityVersionsTest runs the `IdentityTest` for each identity
-// version, with an signed identity.
+// version accepted by default, with an signed identity.
 func SignedIdentityVersionsTest(t *testing.T, test IdentityTest) {
-	for vn, v := range storj.IDVersions {
+	for vn, v := range defaultIDVersions() {
 		versionNumber := vn
 		version := v
 		t.Run(fmt.Sprintf("identity version %d", versionNumber), func(t *testing.T) {
@@ -74,7 +86,7 @@ func SignedIdentityVersionsTest(t *testing.T, test IdentityTest) {
 }
 
 // CompleteIdentityVersionsTest runs the `IdentityTest` for each identity
-// version, with both signed dn unsigned identities.
+// version accepted by default, with both signed dn unsigned identities.
 func CompleteIdentityVersionsTest(t *testing.T, test IdentityTest) {
 	t.Run("unsigned identity", func(t *testing.T) {
 		IdentityVersionsTest(t, test)
@@ -85,10 +97,11 @@ func CompleteIdentityVersionsTest(t *testing.T, test IdentityTest) {
 	})
 }
 
-// SignerVersionsTest runs the `SignerTest` for each identity version, with the
-// respective signer used to sign pregenerated, signed  identities.
+// SignerVersionsTest runs the `SignerTest` for each identity version accepted
+// by default, with the respective signer used to sign pregenerated, signed
+// identities.
 func SignerVersionsTest(t *testing.T, test SignerTest) {
-	for vn, v := range storj.IDVersions {
+	for vn, v := range defaultIDVersions() {
 		var (
 			versionNumber = vn
 			version       = v
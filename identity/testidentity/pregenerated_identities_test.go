@@ -46,3 +46,23 @@ func TestPregeneratedSignedIdentity(t *testing.T) {
 		assert.NoError(t, err)
 	})
 }
+
+func TestPregeneratedOptInIdentity(t *testing.T) {
+	for _, version := range []storj.IDVersion{storj.IDVersions[storj.V1], storj.IDVersions[storj.V2]} {
+		require.Error(t, storj.IDVersionInVersions(version.Number, "latest"))
+
+		for _, ident := range []*identity.FullIdentity{
+			MustPregeneratedIdentity(0, version),
+			MustPregeneratedSignedIdentity(0, version),
+		} {
+			assert.Equal(t, version.Number, ident.ID.Version().Number)
+
+			caVersion, err := storj.IDVersionFromCert(ident.CA)
+			require.NoError(t, err)
+			assert.Equal(t, version.Number, caVersion.Number)
+
+			err = peertls.VerifyPeerCertChains(nil, identity.ToChains(ident.Chain()))
+			assert.NoError(t, err)
+		}
+	}
+}
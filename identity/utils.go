@@ -54,9 +54,23 @@ func writeFile(path string, dirmode, filemode os.FileMode, data []byte) error {
 	if writable, err := fpath.IsWritable(filepath.Dir(path)); !writable || err != nil {
 		return errs.Wrap(errs.New("%s is not a writeable directory: %s\n", path, err))
 	}
-	if err := os.WriteFile(path, data, filemode); err != nil {
+
+	// write to a temporary file first, such that the file is either
+	// replaced completely or not at all.
+	tmp, err := os.CreateTemp(filepath.Dir(path), filepath.Base(path)+".*.tmp")
+	if err != nil {
 		return errs.Wrap(err)
 	}
+	tmpPath := tmp.Name()
+
+	_, err = tmp.Write(data)
+	err = errs.Combine(err, tmp.Sync(), tmp.Close(), os.Chmod(tmpPath, filemode))
+	if err == nil {
+		err = os.Rename(tmpPath, path)
+	}
+	if err != nil {
+		return errs.Combine(errs.Wrap(err), os.Remove(tmpPath))
+	}
 
 	return nil
 }
@@ -75,6 +89,9 @@ func statTLSFiles(certPath, keyPath string) (status TLSFilesStatus, err error) {
 	}
 
 	_, err = os.Stat(keyPath)
+	if err != nil && IsKeyRef(keyPath) {
+		err = nil
+	}
 	if err != nil {
 		if os.IsNotExist(err) {
 			hasKey = false
@@ -6,6 +6,8 @@ package macaroon
 import (
 	"bytes"
 	"context"
+	"net/netip"
+	"slices"
 	"time"
 
 	"github.com/spacemonkeygo/monkit/v3"
@@ -134,11 +136,19 @@ type Action struct {
 	Bucket        []byte
 	EncryptedPath []byte
 	Time          time.Time
+
+	// ClientAddr is the address of the client performing the action. It's
+	// required when any caveat restricts the allowed networks.
+	ClientAddr netip.Addr
 }
 
 // APIKey implements a Macaroon-backed Storj-v3 API key.
 type APIKey struct {
 	mac *Macaroon
+
+	// discharges are discharge macaroons bound to mac, which satisfy its
+	// third-party caveats.
+	discharges []*Macaroon
 }
 
 // ParseAPIKey parses a given api key string and returns an APIKey if the
@@ -148,21 +158,46 @@ func ParseAPIKey(key string) (*APIKey, error) {
 	if err != nil || version != 0 {
 		return nil, ErrFormat.New("invalid api key format")
 	}
-	mac, err := ParseMacaroon(data)
-	if err != nil {
-		return nil, ErrFormat.Wrap(err)
-	}
-	return &APIKey{mac: mac}, nil
+	return ParseRawAPIKey(data)
 }
 
+// dischargesMarker precedes the discharges, which are serialized after the
+// macaroon of an API key. Trailing data without the marker is ignored, as it
+// was before API keys had discharges.
+const dischargesMarker = "\x00discharges\x01"
+
 // ParseRawAPIKey parses raw api key data and returns an APIKey if the APIKey
 // was correctly formatted. It does not validate the key.
 func ParseRawAPIKey(data []byte) (*APIKey, error) {
-	mac, err := ParseMacaroon(data)
+	mac, rest, err := parseMacaroon(data)
 	if err != nil {
 		return nil, ErrFormat.Wrap(err)
 	}
-	return &APIKey{mac: mac}, nil
+
+	rest, ok := bytes.CutPrefix(rest, []byte(dischargesMarker))
+	if !ok {
+		return &APIKey{mac: mac}, nil
+	}
+
+	rest, count, err := parseVarint(rest)
+	if err != nil {
+		return nil, ErrFormat.New("invalid discharge count: %w", err)
+	}
+	if count > len(rest) {
+		return nil, ErrFormat.New("invalid discharge count: %d", count)
+	}
+
+	discharges := make([]*Macaroon, 0, count)
+	for range count {
+		var discharge *Macaroon
+		discharge, rest, err = parseMacaroon(rest)
+		if err != nil {
+			return nil, ErrFormat.New("invalid discharge: %w", err)
+		}
+		discharges = append(discharges, discharge)
+	}
+
+	return &APIKey{mac: mac, discharges: discharges}, nil
 }
 
 // NewAPIKey generates a brand new unrestricted API key given the provided.
@@ -192,10 +227,14 @@ func FromParts(head, secret []byte, caveats ...Caveat) (_ *APIKey, err error) {
 // Check makes sure that the key authorizes the provided action given the root
 // project secret, the API key's version, and any possible revocations, returning an error
 // if the action is not authorized. 'revoked' is a list of revoked heads.
+//
+// Every third-party caveat must be satisfied by a discharge added with
+// WithDischarges. The first-party caveats of discharges restrict the action
+// the same way as the caveats of the key.
 func (a *APIKey) Check(ctx context.Context, secret []byte, version APIKeyVersion, action Action, revoker revoker) (err error) {
 	defer mon.Task()(&ctx)(&err)
 
-	ok, tails := a.mac.ValidateAndTails(secret)
+	ok, tails := a.mac.ValidateAndTails(secret, a.discharges...)
 	if !ok {
 		return ErrInvalid.New("macaroon unauthorized")
 	}
@@ -231,7 +270,7 @@ func (a *APIKey) Check(ctx context.Context, secret []byte, version APIKeyVersion
 		}
 	}
 
-	caveats := a.mac.Caveats()
+	caveats := a.caveats()
 	for _, cavbuf := range caveats {
 		var cav Caveat
 		if err := cav.UnmarshalBinary(cavbuf); err != nil {
@@ -273,7 +312,7 @@ func (a *APIKey) GetAllowedBuckets(ctx context.Context, action Action) (allowed
 	// every caveat that includes a list of allowed paths must include the bucket for
 	// the bucket to be allowed. in other words, the set of allowed buckets is the
 	// intersection of all of the buckets in the allowed paths.
-	for _, cavbuf := range a.mac.Caveats() {
+	for _, cavbuf := range a.caveats() {
 		var cav Caveat
 		if err := cav.UnmarshalBinary(cavbuf); err != nil {
 			return AllowedBuckets{}, ErrFormat.New("invalid caveat format: %v", err)
@@ -283,7 +322,7 @@ func (a *APIKey) GetAllowedBuckets(ctx context.Context, action Action) (allowed
 		}
 
 		// If the caveat does not include any allowed paths, then it is not restricting it.
-		if len(cav.AllowedPaths) == 0 {
+		if len(cav.AllowedPaths) == 0 && len(cav.AllowedPathPatterns) == 0 {
 			continue
 		}
 
@@ -295,12 +334,20 @@ func (a *APIKey) GetAllowedBuckets(ctx context.Context, action Action) (allowed
 		for _, caveatPath := range cav.AllowedPaths {
 			caveatBuckets[string(caveatPath.Bucket)] = struct{}{}
 		}
+		for _, caveatPattern := range cav.AllowedPathPatterns {
+			caveatBuckets[string(caveatPattern.Bucket)] = struct{}{}
+		}
 
 		if allowed.Buckets == nil {
-			allowed.Buckets = caveatBuckets
+			allowed.Buckets = map[string]struct{}{}
+			for bucket := range caveatBuckets {
+				if cav.allowsBucket([]byte(bucket)) {
+					allowed.Buckets[bucket] = struct{}{}
+				}
+			}
 		} else {
 			for bucket := range allowed.Buckets {
-				if _, ok := caveatBuckets[bucket]; !ok {
+				if !cav.allowsBucket([]byte(bucket)) {
 					delete(allowed.Buckets, bucket)
 				}
 			}
@@ -314,7 +361,7 @@ func (a *APIKey) GetAllowedBuckets(ctx context.Context, action Action) (allowed
 func (a *APIKey) GetMaxObjectTTL(ctx context.Context) (ttl *time.Duration, err error) {
 	defer mon.Task()(&ctx)(&err)
 
-	caveats := a.mac.Caveats()
+	caveats := a.caveats()
 	for _, cavbuf := range caveats {
 		var cav Caveat
 		if err := cav.UnmarshalBinary(cavbuf); err != nil {
@@ -328,8 +375,100 @@ func (a *APIKey) GetMaxObjectTTL(ctx context.Context) (ttl *time.Duration, err e
 	return ttl, nil
 }
 
+// GetMaxObjectSize returns the smallest MaxObjectSize configured in the APIKey's caveats.
+// Zero means that the object size is not limited.
+func (a *APIKey) GetMaxObjectSize(ctx context.Context) (size int64, err error) {
+	defer mon.Task()(&ctx)(&err)
+
+	return a.minCaveatSize(func(cav *Caveat) int64 { return cav.MaxObjectSize })
+}
+
+// GetMaxUploadSize returns the smallest MaxUploadSize configured in the APIKey's caveats.
+// Zero means that the upload size is not limited.
+func (a *APIKey) GetMaxUploadSize(ctx context.Context) (size int64, err error) {
+	defer mon.Task()(&ctx)(&err)
+
+	return a.minCaveatSize(func(cav *Caveat) int64 { return cav.MaxUploadSize })
+}
+
+func (a *APIKey) minCaveatSize(field func(cav *Caveat) int64) (size int64, err error) {
+	for _, cavbuf := range a.caveats() {
+		var cav Caveat
+		if err := cav.UnmarshalBinary(cavbuf); err != nil {
+			return 0, ErrFormat.New("invalid caveat format")
+		}
+		value := field(&cav)
+		if value < 0 {
+			return 0, ErrFormat.New("negative size limit")
+		}
+		if value > 0 && (size == 0 || value < size) {
+			size = value
+		}
+	}
+	return size, nil
+}
+
+// RateLimit limits the number of requests within a period.
+type RateLimit struct {
+	Requests int64
+	Period   time.Duration
+}
+
+// PerSecond returns the allowed number of requests per second.
+func (limit RateLimit) PerSecond() float64 {
+	return float64(limit.Requests) / limit.Period.Seconds()
+}
+
+// GetRateLimit returns the most restrictive RateLimit configured in the APIKey's caveats,
+// i.e. the one that allows the fewest requests per second.
+// Nil means that the request rate is not limited.
+func (a *APIKey) GetRateLimit(ctx context.Context) (limit *RateLimit, err error) {
+	defer mon.Task()(&ctx)(&err)
+
+	for _, cavbuf := range a.caveats() {
+		var cav Caveat
+		if err := cav.UnmarshalBinary(cavbuf); err != nil {
+			return nil, ErrFormat.New("invalid caveat format")
+		}
+		if cav.RateLimit == nil {
+			continue
+		}
+		if cav.RateLimit.Requests < 0 || cav.RateLimit.Period == nil || *cav.RateLimit.Period <= 0 {
+			return nil, ErrFormat.New("invalid rate limit")
+		}
+
+		current := RateLimit{
+			Requests: cav.RateLimit.Requests,
+			Period:   *cav.RateLimit.Period,
+		}
+		if limit == nil || current.PerSecond() < limit.PerSecond() {
+			limit = &current
+		}
+	}
+
+	return limit, nil
+}
+
+// caveats returns the first-party caveats of the key and its discharges.
+func (a *APIKey) caveats() [][]byte {
+	caveats := a.mac.Caveats()
+	for _, discharge := range a.discharges {
+		caveats = append(caveats, discharge.Caveats()...)
+	}
+	return caveats
+}
+
 // Restrict generates a new APIKey with the provided Caveat attached.
+//
+// The returned APIKey has no discharges, because discharges are bound to the
+// key they were added to.
 func (a *APIKey) Restrict(caveat Caveat) (*APIKey, error) {
+	for _, pattern := range caveat.AllowedPathPatterns {
+		if err := ValidatePathPattern(string(pattern.EncryptedPattern)); err != nil {
+			return nil, err
+		}
+	}
+
 	buf, err := picobuf.Marshal(&caveat)
 	if err != nil {
 		return nil, Error.Wrap(err)
@@ -341,6 +480,39 @@ func (a *APIKey) Restrict(caveat Caveat) (*APIKey, error) {
 	return &APIKey{mac: mac}, nil
 }
 
+// AddThirdPartyCaveat generates a new APIKey with a third-party caveat
+// attached. The key can be used only after a discharge for the caveat is
+// added with WithDischarges. See Macaroon.AddThirdPartyCaveat.
+//
+// The returned APIKey has no discharges, because discharges are bound to the
+// key they were added to.
+func (a *APIKey) AddThirdPartyCaveat(caveatKey, caveatID []byte, location string) (*APIKey, error) {
+	mac, err := a.mac.AddThirdPartyCaveat(caveatKey, caveatID, location)
+	if err != nil {
+		return nil, Error.Wrap(err)
+	}
+	return &APIKey{mac: mac}, nil
+}
+
+// ThirdPartyCaveats returns the third-party caveats that need to be
+// discharged before the key can be used.
+func (a *APIKey) ThirdPartyCaveats() []ThirdPartyCaveat {
+	return a.mac.ThirdPartyCaveats()
+}
+
+// WithDischarges returns a copy of the APIKey with the discharge macaroons
+// bound to it.
+func (a *APIKey) WithDischarges(discharges ...*Macaroon) *APIKey {
+	key := &APIKey{
+		mac:        a.mac,
+		discharges: slices.Clone(a.discharges),
+	}
+	for _, discharge := range discharges {
+		key.discharges = append(key.discharges, discharge.Bind(a.mac))
+	}
+	return key
+}
+
 // Head returns the identifier for this macaroon's root ancestor.
 func (a *APIKey) Head() []byte {
 	return a.mac.Head()
@@ -353,12 +525,22 @@ func (a *APIKey) Tail() []byte {
 
 // Serialize serializes the API Key to a string.
 func (a *APIKey) Serialize() string {
-	return base58.CheckEncode(a.mac.Serialize(), 0)
+	return base58.CheckEncode(a.SerializeRaw(), 0)
 }
 
 // SerializeRaw serialize the API Key to raw bytes.
 func (a *APIKey) SerializeRaw() []byte {
-	return a.mac.Serialize()
+	data := a.mac.Serialize()
+	if len(a.discharges) == 0 {
+		return data
+	}
+
+	data = append(data, dischargesMarker...)
+	data = appendVarint(data, len(a.discharges))
+	for _, discharge := range a.discharges {
+		data = append(data, discharge.Serialize()...)
+	}
+	return data
 }
 
 // Allows returns true if the provided action is allowed by the caveat.
@@ -372,11 +554,15 @@ func (c *Caveat) Allows(action Action) bool {
 	if c.NotBefore != nil && c.NotBefore.After(action.Time) {
 		return false
 	}
+	// network restrictions apply to every action, including bucket metadata reads.
+	if len(c.AllowedNetworks) > 0 && !c.allowsClientAddr(action.ClientAddr) {
+		return false
+	}
 
 	// we want to always allow reads for bucket metadata, perhaps filtered by the
 	// buckets in the allowed paths.
 	if action.Op == ActionRead && len(action.EncryptedPath) == 0 {
-		if len(c.AllowedPaths) == 0 {
+		if len(c.AllowedPaths) == 0 && len(c.AllowedPathPatterns) == 0 {
 			return true
 		}
 		if len(action.Bucket) == 0 {
@@ -385,12 +571,7 @@ func (c *Caveat) Allows(action Action) bool {
 			// filter out buckets that aren't allowed later with `GetAllowedBuckets()`
 			return true
 		}
-		for _, path := range c.AllowedPaths {
-			if bytes.Equal(path.Bucket, action.Bucket) {
-				return true
-			}
-		}
-		return false
+		return c.allowsBucket(action.Bucket)
 	}
 
 	switch action.Op {
@@ -472,5 +653,34 @@ func (c *Caveat) Allows(action Action) bool {
 		}
 	}
 
+	if len(c.AllowedPathPatterns) > 0 && action.Op != ActionProjectInfo {
+		found := false
+		for _, pattern := range c.AllowedPathPatterns {
+			if bytes.Equal(action.Bucket, pattern.Bucket) && pattern.matches(action) {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return false
+		}
+	}
+
+	return true
+}
+
+// allowsBucket returns true if the bucket is in the allowed paths and in the
+// allowed path patterns, when there are any.
+func (c *Caveat) allowsBucket(bucket []byte) bool {
+	if len(c.AllowedPaths) > 0 && !slices.ContainsFunc(c.AllowedPaths, func(path *Caveat_Path) bool {
+		return bytes.Equal(path.Bucket, bucket)
+	}) {
+		return false
+	}
+	if len(c.AllowedPathPatterns) > 0 && !slices.ContainsFunc(c.AllowedPathPatterns, func(pattern *Caveat_PathPattern) bool {
+		return bytes.Equal(pattern.Bucket, bucket)
+	}) {
+		return false
+	}
 	return true
 }
@@ -7,6 +7,7 @@ import (
 	"bytes"
 	"context"
 	"fmt"
+	"net/netip"
 	"testing"
 	"time"
 
@@ -14,6 +15,7 @@ import (
 	"github.com/zeebo/errs"
 
 	"storj.io/common/testcontext"
+	"storj.io/picobuf"
 )
 
 func TestSerializeParseRestrictAndCheck(t *testing.T) {
@@ -197,6 +199,52 @@ func TestExpiration(t *testing.T) {
 	}
 }
 
+func TestAllowedNetworks(t *testing.T) {
+	ctx := context.Background()
+
+	secret, err := NewSecret()
+	require.NoError(t, err)
+	key, err := NewAPIKey(secret)
+	require.NoError(t, err)
+
+	restricted, err := key.Restrict(WithNonce(Caveat{
+		AllowedNetworks: []string{"192.0.2.0/24", "2001:db8::/32", "invalid"},
+	}))
+	require.NoError(t, err)
+
+	parsed, err := ParseAPIKey(restricted.Serialize())
+	require.NoError(t, err)
+
+	for i, test := range []struct {
+		keyToTest *APIKey
+		addr      netip.Addr
+		allowed   bool
+	}{
+		{key, netip.Addr{}, true},
+		{key, netip.MustParseAddr("198.51.100.1"), true},
+
+		{parsed, netip.Addr{}, false},
+		{parsed, netip.MustParseAddr("192.0.2.10"), true},
+		{parsed, netip.MustParseAddr("::ffff:192.0.2.10"), true},
+		{parsed, netip.MustParseAddr("198.51.100.1"), false},
+		{parsed, netip.MustParseAddr("2001:db8::1"), true},
+		{parsed, netip.MustParseAddr("2001:db9::1"), false},
+	} {
+		for _, op := range []ActionType{ActionRead, ActionWrite, ActionProjectInfo} {
+			err := test.keyToTest.Check(ctx, secret, APIKeyVersionObjectLock, Action{
+				Op:         op,
+				Time:       time.Now(),
+				ClientAddr: test.addr,
+			}, nil)
+			if test.allowed {
+				require.NoError(t, err, fmt.Sprintf("test #%d", i+1))
+			} else {
+				require.True(t, ErrUnauthorized.Has(err), fmt.Sprintf("test #%d", i+1))
+			}
+		}
+	}
+}
+
 func TestGetAllowedBuckets(t *testing.T) {
 	ctx := context.Background()
 
@@ -290,6 +338,195 @@ func TestGetMaxObjectTTL(t *testing.T) {
 	require.Equal(t, oneHour, *ttl)
 }
 
+func TestGetMaxObjectSize(t *testing.T) {
+	ctx := context.Background()
+
+	secret, err := NewSecret()
+	require.NoError(t, err)
+	key, err := NewAPIKey(secret)
+	require.NoError(t, err)
+
+	size, err := key.GetMaxObjectSize(ctx)
+	require.NoError(t, err)
+	require.Zero(t, size)
+
+	restricted, err := key.Restrict(WithNonce(Caveat{MaxObjectSize: 2000, MaxUploadSize: 100}))
+	require.NoError(t, err)
+	restricted, err = restricted.Restrict(WithNonce(Caveat{MaxObjectSize: 1000}))
+	require.NoError(t, err)
+	restricted, err = restricted.Restrict(WithNonce(Caveat{MaxObjectSize: 3000, MaxUploadSize: 200}))
+	require.NoError(t, err)
+	restricted, err = restricted.Restrict(WithNonce(Caveat{DisallowWrites: true}))
+	require.NoError(t, err)
+
+	restricted, err = ParseAPIKey(restricted.Serialize())
+	require.NoError(t, err)
+
+	size, err = restricted.GetMaxObjectSize(ctx)
+	require.NoError(t, err)
+	require.EqualValues(t, 1000, size)
+
+	size, err = restricted.GetMaxUploadSize(ctx)
+	require.NoError(t, err)
+	require.EqualValues(t, 100, size)
+
+	invalid, err := restricted.Restrict(WithNonce(Caveat{MaxObjectSize: -1}))
+	require.NoError(t, err)
+	_, err = invalid.GetMaxObjectSize(ctx)
+	require.True(t, ErrFormat.Has(err))
+}
+
+func TestGetRateLimit(t *testing.T) {
+	ctx := context.Background()
+
+	secret, err := NewSecret()
+	require.NoError(t, err)
+	key, err := NewAPIKey(secret)
+	require.NoError(t, err)
+
+	limit, err := key.GetRateLimit(ctx)
+	require.NoError(t, err)
+	require.Nil(t, limit)
+
+	second := time.Second
+	minute := time.Minute
+
+	// 10 requests per second
+	restricted, err := key.Restrict(WithNonce(Caveat{
+		RateLimit: &Caveat_RateLimit{Requests: 10, Period: &second},
+	}))
+	require.NoError(t, err)
+
+	limit, err = restricted.GetRateLimit(ctx)
+	require.NoError(t, err)
+	require.Equal(t, &RateLimit{Requests: 10, Period: time.Second}, limit)
+
+	// 60 requests per minute is more restrictive
+	restricted, err = restricted.Restrict(WithNonce(Caveat{
+		RateLimit: &Caveat_RateLimit{Requests: 60, Period: &minute},
+	}))
+	require.NoError(t, err)
+
+	// 20 requests per second is less restrictive
+	restricted, err = restricted.Restrict(WithNonce(Caveat{
+		RateLimit: &Caveat_RateLimit{Requests: 20, Period: &second},
+	}))
+	require.NoError(t, err)
+
+	restricted, err = ParseAPIKey(restricted.Serialize())
+	require.NoError(t, err)
+
+	limit, err = restricted.GetRateLimit(ctx)
+	require.NoError(t, err)
+	require.Equal(t, &RateLimit{Requests: 60, Period: time.Minute}, limit)
+	require.Equal(t, 1.0, limit.PerSecond())
+
+	invalid, err := restricted.Restrict(WithNonce(Caveat{
+		RateLimit: &Caveat_RateLimit{Requests: 1},
+	}))
+	require.NoError(t, err)
+	_, err = invalid.GetRateLimit(ctx)
+	require.True(t, ErrFormat.Has(err))
+}
+
+func TestThirdPartyCaveats(t *testing.T) {
+	ctx := context.Background()
+	now := time.Now()
+
+	secret, err := NewSecret()
+	require.NoError(t, err)
+	caveatKey, err := NewSecret()
+	require.NoError(t, err)
+
+	key, err := NewAPIKey(secret)
+	require.NoError(t, err)
+	key, err = key.Restrict(WithNonce(Caveat{DisallowDeletes: true}))
+	require.NoError(t, err)
+	key, err = key.AddThirdPartyCaveat(caveatKey, []byte("sso"), "sso")
+	require.NoError(t, err)
+	require.Len(t, key.ThirdPartyCaveats(), 1)
+
+	action := Action{Op: ActionRead, Time: now}
+	require.True(t, ErrInvalid.Has(key.Check(ctx, secret, APIKeyVersionObjectLock, action, nil)))
+
+	// the third party only allows access for an hour.
+	notAfter := now.Add(time.Hour)
+	discharge, err := NewDischarge(caveatKey, []byte("sso")).AddFirstPartyCaveat(mustMarshalCaveat(t, WithNonce(Caveat{
+		NotAfter: &notAfter,
+	})))
+	require.NoError(t, err)
+
+	discharged, err := ParseAPIKey(key.WithDischarges(discharge).Serialize())
+	require.NoError(t, err)
+	require.Len(t, discharged.discharges, 1)
+
+	require.NoError(t, discharged.Check(ctx, secret, APIKeyVersionObjectLock, action, nil))
+	require.True(t, ErrUnauthorized.Has(discharged.Check(ctx, secret, APIKeyVersionObjectLock, Action{
+		Op:   ActionDelete,
+		Time: now,
+	}, nil)))
+	require.True(t, ErrUnauthorized.Has(discharged.Check(ctx, secret, APIKeyVersionObjectLock, Action{
+		Op:   ActionRead,
+		Time: now.Add(2 * time.Hour),
+	}, nil)))
+
+	// restricting the key drops the discharges.
+	restricted, err := discharged.Restrict(WithNonce(Caveat{DisallowWrites: true}))
+	require.NoError(t, err)
+	require.True(t, ErrInvalid.Has(restricted.Check(ctx, secret, APIKeyVersionObjectLock, action, nil)))
+	require.NoError(t, restricted.WithDischarges(discharge).Check(ctx, secret, APIKeyVersionObjectLock, action, nil))
+}
+
+func TestParseRawAPIKey_TrailingData(t *testing.T) {
+	ctx := context.Background()
+	action := Action{Op: ActionRead, Time: time.Now()}
+
+	secret, err := NewSecret()
+	require.NoError(t, err)
+	caveatKey, err := NewSecret()
+	require.NoError(t, err)
+
+	key, err := NewAPIKey(secret)
+	require.NoError(t, err)
+
+	// unmarked trailing data is ignored, even when it is a macaroon.
+	discharge := NewDischarge(caveatKey, []byte("sso"))
+	for _, trailing := range [][]byte{{0}, []byte("garbage"), discharge.Serialize()} {
+		parsed, err := ParseRawAPIKey(append(key.SerializeRaw(), trailing...))
+		require.NoError(t, err)
+		require.Empty(t, parsed.discharges)
+		require.Equal(t, key.SerializeRaw(), parsed.SerializeRaw())
+		require.NoError(t, parsed.Check(ctx, secret, APIKeyVersionMin, action, nil))
+	}
+
+	// marked discharges are parsed and data after them is ignored.
+	restricted, err := key.AddThirdPartyCaveat(caveatKey, []byte("sso"), "sso")
+	require.NoError(t, err)
+	discharged := restricted.WithDischarges(discharge)
+
+	parsed, err := ParseRawAPIKey(append(discharged.SerializeRaw(), "garbage"...))
+	require.NoError(t, err)
+	require.Len(t, parsed.discharges, 1)
+	require.Equal(t, discharged.SerializeRaw(), parsed.SerializeRaw())
+	require.NoError(t, parsed.Check(ctx, secret, APIKeyVersionMin, action, nil))
+
+	// marked discharges must be valid.
+	for _, invalid := range [][]byte{
+		[]byte(dischargesMarker),
+		append([]byte(dischargesMarker), 2),
+		append(append([]byte(dischargesMarker), 1), "garbage"...),
+	} {
+		_, err := ParseRawAPIKey(append(restricted.SerializeRaw(), invalid...))
+		require.True(t, ErrFormat.Has(err), err)
+	}
+}
+
+func mustMarshalCaveat(t *testing.T, caveat Caveat) []byte {
+	data, err := picobuf.Marshal(&caveat)
+	require.NoError(t, err)
+	return data
+}
+
 func TestNonce(t *testing.T) {
 	secret, err := NewSecret()
 	require.NoError(t, err)
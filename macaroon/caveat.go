@@ -8,6 +8,7 @@ import (
 	"encoding/binary"
 	"encoding/json"
 	mrand "math/rand"
+	"net/netip"
 	"time"
 
 	"storj.io/common/encryption"
@@ -53,6 +54,25 @@ func (cp *Caveat_Path) MarshalJSON() ([]byte, error) {
 	})
 }
 
+// allowsClientAddr returns true if addr is within one of the allowed networks.
+// Invalid network entries never match.
+func (caveat *Caveat) allowsClientAddr(addr netip.Addr) bool {
+	if !addr.IsValid() {
+		return false
+	}
+	addr = addr.Unmap()
+	for _, network := range caveat.AllowedNetworks {
+		prefix, err := netip.ParsePrefix(network)
+		if err != nil {
+			continue
+		}
+		if prefix.Contains(addr) {
+			return true
+		}
+	}
+	return false
+}
+
 // ParseCaveat parses binary encoded caveat.
 func ParseCaveat(data []byte) (*Caveat, error) {
 	var caveat Caveat
@@ -65,5 +85,13 @@ func ParseCaveat(data []byte) (*Caveat, error) {
 
 // UnmarshalBinary implements encoding.BinaryUnmarshaler.
 func (caveat *Caveat) UnmarshalBinary(data []byte) error {
-	return picobuf.Unmarshal(data, caveat)
+	if err := picobuf.Unmarshal(data, caveat); err != nil {
+		return err
+	}
+	for _, pattern := range caveat.AllowedPathPatterns {
+		if err := ValidatePathPattern(string(pattern.EncryptedPattern)); err != nil {
+			return err
+		}
+	}
+	return nil
 }
@@ -16,6 +16,10 @@ type Macaroon struct {
 	head    []byte
 	caveats [][]byte
 	tail    []byte
+
+	// thirdParty contains the third-party caveat details keyed by the
+	// caveat index. It's nil when there are no third-party caveats.
+	thirdParty map[int]thirdPartyCaveat
 }
 
 // NewUnrestricted creates Macaroon with random Head and generated Tail.
@@ -70,38 +74,48 @@ func (m *Macaroon) AddFirstPartyCaveat(c []byte) (macaroon *Macaroon, err error)
 }
 
 // Validate reconstructs with all caveats from the secret and compares tails,
-// returning true if the tails match.
-func (m *Macaroon) Validate(secret []byte) (ok bool) {
-	tail := sign(secret, m.head)
-	for _, cav := range m.caveats {
-		tail = sign(tail, cav)
-	}
-
-	return subtle.ConstantTimeCompare(tail, m.tail) == 1
+// returning true if the tails match. Every third-party caveat must be
+// satisfied by one of the discharge macaroons bound to m.
+func (m *Macaroon) Validate(secret []byte, discharges ...*Macaroon) (ok bool) {
+	ok, _ = m.ValidateAndTails(secret, discharges...)
+	return ok
 }
 
 // Tails returns all ancestor tails up to and including the current tail.
 func (m *Macaroon) Tails(secret []byte) [][]byte {
+	return m.chain(secret)
+}
+
+// ValidateAndTails combines Validate and Tails to a single method.
+func (m *Macaroon) ValidateAndTails(secret []byte, discharges ...*Macaroon) (bool, [][]byte) {
+	tails := m.chain(secret)
+	if subtle.ConstantTimeCompare(tails[len(tails)-1], m.tail) != 1 {
+		return false, tails
+	}
+	if len(m.thirdParty) == 0 && len(discharges) == 0 {
+		return true, tails
+	}
+	return verifyDischarges(m, tails, discharges), tails
+}
+
+// chain returns the tails of m starting from the key.
+func (m *Macaroon) chain(key []byte) [][]byte {
 	tails := make([][]byte, 0, len(m.caveats)+1)
-	tail := sign(secret, m.head)
+	tail := sign(key, m.head)
 	tails = append(tails, tail)
-	for _, cav := range m.caveats {
-		tail = sign(tail, cav)
+	for i := range m.caveats {
+		tail = m.signCaveat(tail, i)
 		tails = append(tails, tail)
 	}
 	return tails
 }
 
-// ValidateAndTails combines Validate and Tails to a single method.
-func (m *Macaroon) ValidateAndTails(secret []byte) (bool, [][]byte) {
-	tails := make([][]byte, 0, len(m.caveats)+1)
-	tail := sign(secret, m.head)
-	tails = append(tails, tail)
-	for _, cav := range m.caveats {
-		tail = sign(tail, cav)
-		tails = append(tails, tail)
+// signCaveat returns the tail after adding the caveat at index i.
+func (m *Macaroon) signCaveat(tail []byte, i int) []byte {
+	if caveat, ok := m.thirdParty[i]; ok {
+		return signThirdParty(tail, caveat.verificationID, m.caveats[i])
 	}
-	return subtle.ConstantTimeCompare(tail, m.tail) == 1, tails
+	return sign(tail, m.caveats[i])
 }
 
 // Head returns copy of macaroon head.
@@ -112,18 +126,22 @@ func (m *Macaroon) Head() (head []byte) {
 	return slices.Clone(m.head)
 }
 
-// CaveatLen returns the number of caveats this macaroon has.
+// CaveatLen returns the number of caveats this macaroon has, including
+// third-party caveats.
 func (m *Macaroon) CaveatLen() int {
 	return len(m.caveats)
 }
 
-// Caveats returns copy of macaroon caveats.
+// Caveats returns copy of macaroon first-party caveats.
 func (m *Macaroon) Caveats() (caveats [][]byte) {
-	if len(m.caveats) == 0 {
+	if len(m.caveats) == len(m.thirdParty) {
 		return nil
 	}
-	caveats = make([][]byte, 0, len(m.caveats))
-	for _, cav := range m.caveats {
+	caveats = make([][]byte, 0, len(m.caveats)-len(m.thirdParty))
+	for i, cav := range m.caveats {
+		if _, ok := m.thirdParty[i]; ok {
+			continue
+		}
 		caveats = append(caveats, slices.Clone(cav))
 	}
 	return caveats
@@ -139,9 +157,26 @@ func (m *Macaroon) Tail() (tail []byte) {
 
 // Copy return copy of macaroon.
 func (m *Macaroon) Copy() *Macaroon {
+	var caveats [][]byte
+	if len(m.caveats) > 0 {
+		caveats = make([][]byte, 0, len(m.caveats))
+		for _, cav := range m.caveats {
+			caveats = append(caveats, slices.Clone(cav))
+		}
+	}
+
+	var thirdParty map[int]thirdPartyCaveat
+	if len(m.thirdParty) > 0 {
+		thirdParty = make(map[int]thirdPartyCaveat, len(m.thirdParty))
+		for i, caveat := range m.thirdParty {
+			thirdParty[i] = caveat.clone()
+		}
+	}
+
 	return &Macaroon{
-		head:    m.Head(),
-		caveats: m.Caveats(),
-		tail:    m.Tail(),
+		head:       m.Head(),
+		caveats:    caveats,
+		tail:       m.Tail(),
+		thirdParty: thirdParty,
 	}
 }
@@ -89,6 +89,72 @@ func TestMacaroon(t *testing.T) {
 	})
 }
 
+func TestThirdPartyCaveat(t *testing.T) {
+	secret, err := macaroon.NewSecret()
+	assert.NoError(t, err)
+	caveatKey, err := macaroon.NewSecret()
+	assert.NoError(t, err)
+
+	root, err := macaroon.NewUnrestricted(secret)
+	assert.NoError(t, err)
+	root, err = root.AddFirstPartyCaveat([]byte("cav1"))
+	assert.NoError(t, err)
+	root, err = root.AddThirdPartyCaveat(caveatKey, []byte("sso"), "https://sso.example.test")
+	assert.NoError(t, err)
+
+	assert.Equal(t, 2, root.CaveatLen())
+	assert.Equal(t, [][]byte{[]byte("cav1")}, root.Caveats())
+	assert.Equal(t, []macaroon.ThirdPartyCaveat{{Location: "https://sso.example.test", ID: []byte("sso")}}, root.ThirdPartyCaveats())
+
+	// serialization keeps the third-party caveat.
+	parsed, err := macaroon.ParseMacaroon(root.Serialize())
+	assert.NoError(t, err)
+	assert.Equal(t, root, parsed)
+
+	// without a discharge the macaroon is invalid.
+	assert.False(t, root.Validate(secret))
+	ok, tails := root.ValidateAndTails(secret)
+	assert.False(t, ok)
+	assert.Equal(t, root.Tails(secret), tails)
+
+	discharge, err := macaroon.NewDischarge(caveatKey, []byte("sso")).AddFirstPartyCaveat([]byte("dcav"))
+	assert.NoError(t, err)
+
+	// an unbound discharge is invalid.
+	assert.False(t, root.Validate(secret, discharge))
+
+	bound := discharge.Bind(root)
+	assert.True(t, root.Validate(secret, bound))
+	assert.False(t, root.Validate(nil, bound))
+
+	ok, tails = root.ValidateAndTails(secret, bound)
+	assert.True(t, ok)
+	assert.Len(t, tails, 3)
+	assert.Equal(t, root.Tail(), tails[2])
+
+	// a discharge bound to a different macaroon is invalid.
+	other, err := root.AddFirstPartyCaveat([]byte("cav2"))
+	assert.NoError(t, err)
+	assert.False(t, other.Validate(secret, bound))
+	assert.True(t, other.Validate(secret, discharge.Bind(other)))
+
+	// a discharge with a wrong caveat key is invalid.
+	wrongKey, err := macaroon.NewSecret()
+	assert.NoError(t, err)
+	assert.False(t, root.Validate(secret, macaroon.NewDischarge(wrongKey, []byte("sso")).Bind(root)))
+
+	// unused discharges are not allowed.
+	assert.False(t, root.Validate(secret, bound, macaroon.NewDischarge(caveatKey, []byte("unused")).Bind(root)))
+
+	// discharges can have third-party caveats too.
+	nestedKey, err := macaroon.NewSecret()
+	assert.NoError(t, err)
+	nested, err := macaroon.NewDischarge(caveatKey, []byte("sso")).AddThirdPartyCaveat(nestedKey, []byte("mfa"), "")
+	assert.NoError(t, err)
+	assert.False(t, root.Validate(secret, nested.Bind(root)))
+	assert.True(t, root.Validate(secret, nested.Bind(root), macaroon.NewDischarge(nestedKey, []byte("mfa")).Bind(root)))
+}
+
 func FuzzParseMacaroon(f *testing.F) {
 	f.Add([]byte{})
 	f.Add([]byte{0x2, 0x2, 0x20, 0xfb, 0x22, 0xe5, 0x50, 0x30, 0x5, 0xca, 0x60, 0x5, 0xc5, 0x4a, 0x5d, 0x5, 0x1c, 0x4c, 0xa0, 0x95, 0x58, 0x45, 0xfe, 0x77, 0x44, 0xd0, 0x11, 0xdd, 0x69, 0x9, 0xa1, 0x46, 0x5, 0x23, 0x6e, 0x0, 0x0, 0x6, 0x20, 0x5b, 0x50, 0x2a, 0xcd, 0xc3, 0x64, 0x69, 0xca, 0xeb, 0xbe, 0xf6, 0xa3, 0x6, 0x74, 0x8f, 0x9c, 0xc3, 0xd, 0x47, 0xfd, 0xd9, 0xd1, 0xd9, 0xb9, 0xd, 0x8d, 0x18, 0xe9, 0xf9, 0x5a, 0x6f, 0x7})
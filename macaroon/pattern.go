@@ -0,0 +1,169 @@
+// Copyright (C) 2026 Storj Labs, Inc.
+// See LICENSE for copying information.
+
+package macaroon
+
+import (
+	"path"
+	"strings"
+)
+
+// Path patterns restrict access to the object keys matching them. A pattern
+// consists of components separated by forward slashes, which are matched
+// against the components of the encrypted object key:
+//
+//   - "**" matches zero or more components.
+//   - any other component is matched with path.Match, hence "*", "?" and
+//     character classes only match within a single component.
+//
+// Encrypted path components are opaque, so they need to be escaped with
+// EscapePathPattern before they are used within a pattern. Wildcards that
+// match parts of a component, e.g. "*.jpg", are only meaningful when the
+// object keys aren't encrypted.
+
+const (
+	// maxPathPatternLength is the maximum length of a path pattern.
+	maxPathPatternLength = 4096
+	// maxPathPatternDoubleStars is the maximum number of "**" components in a
+	// path pattern.
+	maxPathPatternDoubleStars = 16
+)
+
+// EscapePathPattern escapes the glob metacharacters in the encrypted path, such
+// that the result is a pattern which only matches the path itself.
+func EscapePathPattern(encryptedPath string) string {
+	var b strings.Builder
+	for i := 0; i < len(encryptedPath); i++ {
+		switch c := encryptedPath[i]; c {
+		case '*', '?', '[', '\\':
+			b.WriteByte('\\')
+			b.WriteByte(c)
+		default:
+			b.WriteByte(c)
+		}
+	}
+	return b.String()
+}
+
+// UnescapePathPattern returns the path matched by the pattern component. It
+// returns false when the component contains wildcards, i.e. it matches more
+// than one path component.
+func UnescapePathPattern(component string) (string, bool) {
+	var b strings.Builder
+	for i := 0; i < len(component); i++ {
+		switch c := component[i]; c {
+		case '*', '?', '[':
+			return "", false
+		case '\\':
+			i++
+			if i >= len(component) {
+				return "", false
+			}
+			b.WriteByte(component[i])
+		default:
+			b.WriteByte(c)
+		}
+	}
+	return b.String(), true
+}
+
+// ValidatePathPattern checks whether the pattern is well formed and within the
+// limits for its length and the number of "**" components.
+func ValidatePathPattern(pattern string) error {
+	if len(pattern) > maxPathPatternLength {
+		return ErrFormat.New("path pattern too long: %d > %d", len(pattern), maxPathPatternLength)
+	}
+
+	doubleStars := 0
+	for _, component := range strings.Split(pattern, "/") {
+		if component == "**" {
+			doubleStars++
+			continue
+		}
+		if _, err := path.Match(component, ""); err != nil {
+			return ErrFormat.New("invalid path pattern %q: %v", pattern, err)
+		}
+	}
+	if doubleStars > maxPathPatternDoubleStars {
+		return ErrFormat.New("too many ** components in path pattern: %d > %d", doubleStars, maxPathPatternDoubleStars)
+	}
+	return nil
+}
+
+// SplitPrefix splits the pattern into the encrypted path prefix consisting of
+// the leading components without wildcards and the remaining pattern.
+func (pattern *Caveat_PathPattern) SplitPrefix() (encryptedPathPrefix []byte, rest []byte) {
+	components := strings.Split(string(pattern.EncryptedPattern), "/")
+
+	var literals []string
+	for _, component := range components {
+		literal, ok := UnescapePathPattern(component)
+		if !ok {
+			break
+		}
+		literals = append(literals, literal)
+	}
+
+	return []byte(strings.Join(literals, "/")), []byte(strings.Join(components[len(literals):], "/"))
+}
+
+// matches returns whether the action is allowed by the pattern. Invalid
+// patterns don't match anything.
+func (pattern *Caveat_PathPattern) matches(action Action) bool {
+	if ValidatePathPattern(string(pattern.EncryptedPattern)) != nil {
+		return false
+	}
+	return matchPathPattern(string(pattern.EncryptedPattern), string(action.EncryptedPath), action.Op == ActionList)
+}
+
+// matchPathPattern reports whether the encrypted path matches the pattern.
+//
+// When listing is set, the path is a listing prefix, whose last component is
+// incomplete. It matches when the complete components can be extended to a
+// path matching the pattern. The listed items themselves are not filtered.
+func matchPathPattern(pattern, encryptedPath string, listing bool) bool {
+	components := strings.Split(encryptedPath, "/")
+	if listing {
+		components = components[:len(components)-1]
+	}
+	return matchPathComponents(strings.Split(pattern, "/"), components, listing)
+}
+
+// matchPathComponents matches the components against the patterns in
+// O(len(patterns)*len(components)) steps.
+//
+// A "**" matches as few components as possible. When the match fails later on,
+// only the last "**" needs to match one more component, because the earlier
+// ones can't help matching the remaining components.
+func matchPathComponents(patterns, components []string, listing bool) bool {
+	p, c := 0, 0
+	star, starComponent := -1, 0
+	for c < len(components) {
+		switch {
+		case p < len(patterns) && patterns[p] == "**":
+			star, starComponent = p, c
+			p++
+			continue
+		case p < len(patterns):
+			if matched, err := path.Match(patterns[p], components[c]); err == nil && matched {
+				p, c = p+1, c+1
+				continue
+			}
+		}
+
+		if star < 0 {
+			return false
+		}
+		starComponent++
+		p, c = star+1, starComponent
+	}
+
+	// the remaining patterns can match the components after a listing prefix.
+	if listing {
+		return true
+	}
+	for p < len(patterns) && patterns[p] == "**" {
+		p++
+	}
+	return p == len(patterns)
+}
@@ -0,0 +1,179 @@
+// Copyright (C) 2026 Storj Labs, Inc.
+// See LICENSE for copying information.
+
+package macaroon
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"storj.io/picobuf"
+)
+
+func TestMatchPathPattern(t *testing.T) {
+	for _, test := range []struct {
+		pattern string
+		path    string
+		listing bool
+		matches bool
+	}{
+		{"photos/*.jpg", "photos/a.jpg", false, true},
+		{"photos/*.jpg", "photos/a.png", false, false},
+		{"photos/*.jpg", "photos/2024/a.jpg", false, false},
+		{"photos/*.jpg", "photos", false, false},
+		{"photos/**/*.jpg", "photos/a.jpg", false, true},
+		{"photos/**/*.jpg", "photos/2024/01/a.jpg", false, true},
+		{"photos/**", "photos", false, true},
+		{"photos/**", "photos/a/b", false, true},
+		{"photos/**", "photosx/a", false, false},
+		{"**/**/a", "x/y/a", false, true},
+		{"**/a/**/b", "a/x/a/b", false, true},
+		{"**/a/*/b", "a/a/x/b/b", false, false},
+		{"**/a/*/b/**", "a/a/x/b/b", false, true},
+		{"a/**/b/**/c", "a/b/x/c/b", false, false},
+		{`\*/*`, "*/a", false, true},
+		{`\*/*`, "x/a", false, false},
+
+		{"photos/*.jpg", "", true, true},
+		{"photos/*.jpg", "pho", true, true},
+		{"photos/*.jpg", "photos/", true, true},
+		{"photos/*.jpg", "photos/a", true, true},
+		{"photos/*.jpg", "videos/", true, false},
+		{"photos/*.jpg", "photos/2024/", true, false},
+		{"photos/**", "photos/2024/01/", true, true},
+	} {
+		require.Equal(t, test.matches, matchPathPattern(test.pattern, test.path, test.listing), "%q %q %v", test.pattern, test.path, test.listing)
+	}
+}
+
+func TestMatchPathPattern_ManyDoubleStars(t *testing.T) {
+	patterns := make([]string, 0, 201)
+	for range 100 {
+		patterns = append(patterns, "**", "*")
+	}
+	patterns = append(patterns, "x")
+
+	components := make([]string, 1000)
+	for i := range components {
+		components[i] = "a"
+	}
+
+	start := time.Now()
+	require.False(t, matchPathComponents(patterns, components, false))
+	require.True(t, matchPathComponents(patterns, append(components, "x"), false))
+	require.True(t, matchPathComponents(patterns, components, true))
+	require.Less(t, time.Since(start), 5*time.Second)
+
+	// such patterns are rejected in caveats.
+	pattern := strings.Join(patterns, "/")
+	require.Error(t, ValidatePathPattern(pattern))
+	require.Error(t, ValidatePathPattern(strings.Repeat("a", maxPathPatternLength+1)))
+	require.NoError(t, ValidatePathPattern(strings.Repeat("**/a/", maxPathPatternDoubleStars)))
+
+	caveat := Caveat{AllowedPathPatterns: []*Caveat_PathPattern{{Bucket: []byte("bucket"), EncryptedPattern: []byte(pattern)}}}
+	require.False(t, caveat.Allows(Action{Op: ActionRead, Bucket: []byte("bucket"), EncryptedPath: []byte(strings.Join(components, "/") + "/x"), Time: time.Now()}))
+
+	key, err := NewAPIKey([]byte("secret"))
+	require.NoError(t, err)
+	_, err = key.Restrict(caveat)
+	require.True(t, ErrFormat.Has(err), err)
+
+	data, err := picobuf.Marshal(&caveat)
+	require.NoError(t, err)
+	_, err = ParseCaveat(data)
+	require.True(t, ErrFormat.Has(err), err)
+}
+
+func TestPathPatternEscaping(t *testing.T) {
+	path := "a*b?c[d]\\e"
+	escaped := EscapePathPattern(path)
+	require.True(t, matchPathPattern(escaped, path, false))
+	require.False(t, matchPathPattern(escaped, "axb?c[d]\\e", false))
+
+	unescaped, ok := UnescapePathPattern(escaped)
+	require.True(t, ok)
+	require.Equal(t, path, unescaped)
+
+	_, ok = UnescapePathPattern("*.jpg")
+	require.False(t, ok)
+
+	pattern := Caveat_PathPattern{EncryptedPattern: []byte(EscapePathPattern("x*") + "/y/*/z")}
+	prefix, rest := pattern.SplitPrefix()
+	require.Equal(t, "x*/y", string(prefix))
+	require.Equal(t, "*/z", string(rest))
+
+	require.NoError(t, ValidatePathPattern("a/**/[a-z]*.jpg"))
+	require.Error(t, ValidatePathPattern("a/[a-"))
+}
+
+func TestAllowedPathPatterns(t *testing.T) {
+	ctx := context.Background()
+
+	secret, err := NewSecret()
+	require.NoError(t, err)
+	key, err := NewAPIKey(secret)
+	require.NoError(t, err)
+
+	restricted, err := key.Restrict(WithNonce(Caveat{
+		AllowedPathPatterns: []*Caveat_PathPattern{
+			{Bucket: []byte("bucket1"), EncryptedPattern: []byte("photos/*.jpg")},
+			{Bucket: []byte("bucket2"), EncryptedPattern: []byte("**")},
+		},
+	}))
+	require.NoError(t, err)
+
+	parsed, err := ParseAPIKey(restricted.Serialize())
+	require.NoError(t, err)
+
+	now := time.Now()
+	for _, test := range []struct {
+		op      ActionType
+		bucket  string
+		path    string
+		allowed bool
+	}{
+		{ActionRead, "bucket1", "photos/a.jpg", true},
+		{ActionWrite, "bucket1", "photos/a.jpg", true},
+		{ActionRead, "bucket1", "photos/a.png", false},
+		{ActionRead, "bucket2", "photos/a.png", true},
+		{ActionRead, "bucket3", "photos/a.jpg", false},
+		{ActionList, "bucket1", "photos/", true},
+		{ActionList, "bucket1", "videos/", false},
+		{ActionRead, "bucket1", "", true},
+		{ActionRead, "bucket3", "", false},
+		{ActionProjectInfo, "", "", true},
+	} {
+		err := parsed.Check(ctx, secret, APIKeyVersionObjectLock, Action{
+			Op:            test.op,
+			Bucket:        []byte(test.bucket),
+			EncryptedPath: []byte(test.path),
+			Time:          now,
+		}, nil)
+		if test.allowed {
+			require.NoError(t, err, "%v", test)
+		} else {
+			require.True(t, ErrUnauthorized.Has(err), "%v", test)
+		}
+	}
+
+	allowed, err := parsed.GetAllowedBuckets(ctx, Action{Op: ActionRead, Time: now})
+	require.NoError(t, err)
+	require.Equal(t, AllowedBuckets{
+		Buckets: map[string]struct{}{"bucket1": {}, "bucket2": {}},
+	}, allowed)
+
+	// patterns and paths of the same caveat have to match both.
+	restricted, err = key.Restrict(WithNonce(Caveat{
+		AllowedPaths:        []*Caveat_Path{{Bucket: []byte("bucket1")}},
+		AllowedPathPatterns: []*Caveat_PathPattern{{Bucket: []byte("bucket2"), EncryptedPattern: []byte("**")}},
+	}))
+	require.NoError(t, err)
+
+	allowed, err = restricted.GetAllowedBuckets(ctx, Action{Op: ActionRead, Time: now})
+	require.NoError(t, err)
+	require.Equal(t, AllowedBuckets{Buckets: map[string]struct{}{}}, allowed)
+}
@@ -0,0 +1,9 @@
+// Copyright (C) 2026 Storj Labs, Inc.
+// See LICENSE for copying information.
+
+// Package revocation implements revokers for macaroon.APIKey.Check keyed by
+// macaroon tails, and a signed revocation list format to distribute them.
+//
+// Revoking a tail revokes the API key with that tail and every API key
+// restricted from it, because their Tails include all the ancestor tails.
+package revocation
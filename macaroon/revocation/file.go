@@ -0,0 +1,115 @@
+// Copyright (C) 2026 Storj Labs, Inc.
+// See LICENSE for copying information.
+
+package revocation
+
+import (
+	"context"
+	"errors"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sync"
+)
+
+// File is a revoker that persists revoked tails to a file.
+//
+// File is safe for concurrent use within a single process.
+type File struct {
+	path string
+
+	// mu serializes writing the file.
+	mu     sync.Mutex
+	memory *Memory
+}
+
+// OpenFile opens a file-backed revoker. The file is created on the first
+// revocation, when it doesn't exist.
+func OpenFile(path string) (*File, error) {
+	file := &File{
+		path:   path,
+		memory: NewMemory(),
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		if errors.Is(err, fs.ErrNotExist) {
+			return file, nil
+		}
+		return nil, Error.Wrap(err)
+	}
+
+	list, err := ParseList(data)
+	if err != nil {
+		return nil, Error.New("invalid revocation file %q: %w", path, err)
+	}
+	file.memory.Merge(list)
+
+	return file, nil
+}
+
+// Revoke revokes the macaroon tails and persists them. The tails stay
+// revoked in memory even when persisting fails.
+func (file *File) Revoke(tails ...[]byte) error {
+	file.mu.Lock()
+	defer file.mu.Unlock()
+
+	if err := file.memory.Revoke(tails...); err != nil {
+		return err
+	}
+	return file.save()
+}
+
+// Merge adds all tails of the list and persists them.
+func (file *File) Merge(list *List) error {
+	file.mu.Lock()
+	defer file.mu.Unlock()
+
+	file.memory.Merge(list)
+	return file.save()
+}
+
+// Check returns true if any of the tails are revoked. It implements the
+// revoker interface of macaroon.APIKey.Check.
+func (file *File) Check(ctx context.Context, tails [][]byte) (bool, error) {
+	return file.memory.Check(ctx, tails)
+}
+
+// Len returns the number of revoked tails.
+func (file *File) Len() int {
+	return file.memory.Len()
+}
+
+// List returns all revoked tails.
+func (file *File) List() *List {
+	return file.memory.List()
+}
+
+// save atomically replaces the file with the current list.
+func (file *File) save() (err error) {
+	data := file.memory.List().Bytes()
+
+	temp, err := os.CreateTemp(filepath.Dir(file.path), filepath.Base(file.path)+".*.tmp")
+	if err != nil {
+		return Error.Wrap(err)
+	}
+	defer func() {
+		if err != nil {
+			_ = os.Remove(temp.Name())
+		}
+	}()
+
+	if _, err := temp.Write(data); err != nil {
+		_ = temp.Close()
+		return Error.Wrap(err)
+	}
+	if err := temp.Sync(); err != nil {
+		_ = temp.Close()
+		return Error.Wrap(err)
+	}
+	if err := temp.Close(); err != nil {
+		return Error.Wrap(err)
+	}
+
+	return Error.Wrap(os.Rename(temp.Name(), file.path))
+}
@@ -0,0 +1,200 @@
+// Copyright (C) 2026 Storj Labs, Inc.
+// See LICENSE for copying information.
+
+package revocation
+
+import (
+	"bytes"
+	"crypto/ed25519"
+	"encoding/binary"
+	"slices"
+	"time"
+
+	"github.com/zeebo/errs"
+)
+
+// Error is the error class for revocation errors.
+var Error = errs.Class("revocation")
+
+// TailSize is the size of a macaroon tail.
+const TailSize = 32
+
+// Tail is a revoked macaroon tail.
+type Tail [TailSize]byte
+
+// TailFromBytes converts a macaroon tail to Tail.
+func TailFromBytes(tail []byte) (Tail, error) {
+	if len(tail) != TailSize {
+		return Tail{}, Error.New("invalid tail length %d", len(tail))
+	}
+	return Tail(tail), nil
+}
+
+var (
+	listMagic       = []byte("SRL1")
+	signedListMagic = []byte("SRS1")
+)
+
+// List is a list of revoked macaroon tails that can be distributed to
+// services that check API keys.
+//
+// The tails are sorted and unique.
+type List struct {
+	// Sequence increases with every list published by the same issuer.
+	Sequence uint64
+	// Issued is the time the list was created.
+	Issued time.Time
+	Tails  []Tail
+}
+
+// NewList creates a new list from the tails.
+func NewList(sequence uint64, issued time.Time, tails ...Tail) *List {
+	list := &List{
+		Sequence: sequence,
+		Issued:   issued,
+		Tails:    slices.Clone(tails),
+	}
+	list.normalize()
+	return list
+}
+
+func (list *List) normalize() {
+	slices.SortFunc(list.Tails, func(a, b Tail) int {
+		return bytes.Compare(a[:], b[:])
+	})
+	list.Tails = slices.Compact(list.Tails)
+}
+
+// Contains returns true if the tail is in the list.
+func (list *List) Contains(tail []byte) bool {
+	_, found := slices.BinarySearchFunc(list.Tails, tail, func(a Tail, b []byte) int {
+		return bytes.Compare(a[:], b)
+	})
+	return found
+}
+
+// Merge returns a new list containing the tails of all the lists. The
+// sequence and the issue time are the largest of all lists.
+func Merge(lists ...*List) *List {
+	merged := &List{}
+	for _, list := range lists {
+		if list == nil {
+			continue
+		}
+		merged.Sequence = max(merged.Sequence, list.Sequence)
+		if list.Issued.After(merged.Issued) {
+			merged.Issued = list.Issued
+		}
+		merged.Tails = append(merged.Tails, list.Tails...)
+	}
+	merged.normalize()
+	return merged
+}
+
+// Bytes encodes the list into:
+//
+//	magic | uvarint(sequence) | varint(issued unix nanos) | uvarint(len(tails)) | tails
+func (list *List) Bytes() []byte {
+	data := make([]byte, 0, len(listMagic)+3*binary.MaxVarintLen64+len(list.Tails)*TailSize)
+	data = append(data, listMagic...)
+	data = binary.AppendUvarint(data, list.Sequence)
+	var issued int64
+	if !list.Issued.IsZero() {
+		issued = list.Issued.UnixNano()
+	}
+	data = binary.AppendVarint(data, issued)
+	data = binary.AppendUvarint(data, uint64(len(list.Tails)))
+	for _, tail := range list.Tails {
+		data = append(data, tail[:]...)
+	}
+	return data
+}
+
+// ParseList decodes a list encoded with Bytes.
+func ParseList(data []byte) (*List, error) {
+	if !bytes.HasPrefix(data, listMagic) {
+		return nil, Error.New("invalid list header")
+	}
+	data = data[len(listMagic):]
+
+	sequence, n := binary.Uvarint(data)
+	if n <= 0 {
+		return nil, Error.New("invalid sequence")
+	}
+	data = data[n:]
+
+	issued, n := binary.Varint(data)
+	if n <= 0 {
+		return nil, Error.New("invalid issue time")
+	}
+	data = data[n:]
+
+	count, n := binary.Uvarint(data)
+	if n <= 0 || count != uint64(len(data)-n)/TailSize || (len(data)-n)%TailSize != 0 {
+		return nil, Error.New("invalid tail count")
+	}
+	data = data[n:]
+
+	list := &List{
+		Sequence: sequence,
+		Tails:    make([]Tail, count),
+	}
+	if issued != 0 {
+		list.Issued = time.Unix(0, issued)
+	}
+	for i := range list.Tails {
+		list.Tails[i] = Tail(data[i*TailSize : (i+1)*TailSize])
+	}
+	list.normalize()
+
+	return list, nil
+}
+
+// Sign signs the list with the private key of the issuer. The result can be
+// verified and decoded with ParseSignedList.
+//
+//	signed magic | uvarint(len(list)) | list | signature
+func (list *List) Sign(key ed25519.PrivateKey) ([]byte, error) {
+	if len(key) != ed25519.PrivateKeySize {
+		return nil, Error.New("invalid private key")
+	}
+
+	encoded := list.Bytes()
+	data := make([]byte, 0, len(signedListMagic)+binary.MaxVarintLen64+len(encoded)+ed25519.SignatureSize)
+	data = append(data, signedListMagic...)
+	data = binary.AppendUvarint(data, uint64(len(encoded)))
+	data = append(data, encoded...)
+	return append(data, ed25519.Sign(key, data)...), nil
+}
+
+// ParseSignedList verifies the signature of a list signed with Sign and
+// decodes it. The list must be signed by one of the trusted keys.
+func ParseSignedList(data []byte, trusted ...ed25519.PublicKey) (*List, error) {
+	if len(data) < ed25519.SignatureSize {
+		return nil, Error.New("signed list too short")
+	}
+	signed, signature := data[:len(data)-ed25519.SignatureSize], data[len(data)-ed25519.SignatureSize:]
+
+	verified := false
+	for _, key := range trusted {
+		if len(key) == ed25519.PublicKeySize && ed25519.Verify(key, signed, signature) {
+			verified = true
+			break
+		}
+	}
+	if !verified {
+		return nil, Error.New("invalid signature")
+	}
+
+	if !bytes.HasPrefix(signed, signedListMagic) {
+		return nil, Error.New("invalid signed list header")
+	}
+	signed = signed[len(signedListMagic):]
+
+	length, n := binary.Uvarint(signed)
+	if n <= 0 || length != uint64(len(signed)-n) {
+		return nil, Error.New("invalid list length")
+	}
+
+	return ParseList(signed[n:])
+}
@@ -0,0 +1,105 @@
+// Copyright (C) 2026 Storj Labs, Inc.
+// See LICENSE for copying information.
+
+package revocation
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// Memory is an in-memory revoker.
+//
+// Memory is safe for concurrent use.
+type Memory struct {
+	mu       sync.RWMutex
+	sequence uint64
+	issued   time.Time
+	tails    map[Tail]struct{}
+}
+
+// NewMemory creates an empty in-memory revoker.
+func NewMemory() *Memory {
+	return &Memory{
+		tails: map[Tail]struct{}{},
+	}
+}
+
+// Revoke revokes the macaroon tails.
+func (m *Memory) Revoke(tails ...[]byte) error {
+	revoked := make([]Tail, 0, len(tails))
+	for _, tail := range tails {
+		t, err := TailFromBytes(tail)
+		if err != nil {
+			return err
+		}
+		revoked = append(revoked, t)
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	for _, tail := range revoked {
+		m.tails[tail] = struct{}{}
+	}
+	return nil
+}
+
+// Merge adds all tails of the list. It keeps track of the largest sequence
+// and issue time of merged lists.
+func (m *Memory) Merge(list *List) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.sequence = max(m.sequence, list.Sequence)
+	if list.Issued.After(m.issued) {
+		m.issued = list.Issued
+	}
+	for _, tail := range list.Tails {
+		m.tails[tail] = struct{}{}
+	}
+}
+
+// Check returns true if any of the tails are revoked. It implements the
+// revoker interface of macaroon.APIKey.Check.
+func (m *Memory) Check(ctx context.Context, tails [][]byte) (bool, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	for _, tail := range tails {
+		if len(tail) != TailSize {
+			continue
+		}
+		if _, ok := m.tails[Tail(tail)]; ok {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+// Len returns the number of revoked tails.
+func (m *Memory) Len() int {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	return len(m.tails)
+}
+
+// List returns all revoked tails as a list with the largest sequence and
+// issue time of merged lists.
+func (m *Memory) List() *List {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	list := &List{
+		Sequence: m.sequence,
+		Issued:   m.issued,
+		Tails:    make([]Tail, 0, len(m.tails)),
+	}
+	for tail := range m.tails {
+		list.Tails = append(list.Tails, tail)
+	}
+	list.normalize()
+	return list
+}
@@ -0,0 +1,205 @@
+// Copyright (C) 2026 Storj Labs, Inc.
+// See LICENSE for copying information.
+
+package revocation_test
+
+import (
+	"context"
+	"crypto/ed25519"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"storj.io/common/macaroon"
+	"storj.io/common/macaroon/revocation"
+	"storj.io/common/testrand"
+)
+
+type testKeys struct {
+	secret     []byte
+	root       *macaroon.APIKey
+	restricted *macaroon.APIKey
+	sibling    *macaroon.APIKey
+}
+
+func newTestKeys(t *testing.T) testKeys {
+	secret, err := macaroon.NewSecret()
+	require.NoError(t, err)
+
+	root, err := macaroon.NewAPIKey(secret)
+	require.NoError(t, err)
+
+	restricted, err := root.Restrict(macaroon.WithNonce(macaroon.Caveat{DisallowDeletes: true}))
+	require.NoError(t, err)
+
+	sibling, err := root.Restrict(macaroon.WithNonce(macaroon.Caveat{DisallowWrites: true}))
+	require.NoError(t, err)
+
+	return testKeys{
+		secret:     secret,
+		root:       root,
+		restricted: restricted,
+		sibling:    sibling,
+	}
+}
+
+func (keys testKeys) check(ctx context.Context, key *macaroon.APIKey, revoker interface {
+	Check(ctx context.Context, tails [][]byte) (bool, error)
+}) error {
+	return key.Check(ctx, keys.secret, macaroon.APIKeyVersionMin, macaroon.Action{
+		Op:   macaroon.ActionRead,
+		Time: time.Now(),
+	}, revoker)
+}
+
+func TestMemory(t *testing.T) {
+	ctx := context.Background()
+	keys := newTestKeys(t)
+
+	revoker := revocation.NewMemory()
+	require.NoError(t, keys.check(ctx, keys.restricted, revoker))
+
+	// revoking a restricted key doesn't revoke its parent nor siblings.
+	require.NoError(t, revoker.Revoke(keys.restricted.Tail()))
+	require.True(t, macaroon.ErrRevoked.Has(keys.check(ctx, keys.restricted, revoker)))
+	require.NoError(t, keys.check(ctx, keys.root, revoker))
+	require.NoError(t, keys.check(ctx, keys.sibling, revoker))
+
+	// revoking the root revokes all keys derived from it.
+	require.NoError(t, revoker.Revoke(keys.root.Tail()))
+	require.True(t, macaroon.ErrRevoked.Has(keys.check(ctx, keys.root, revoker)))
+	require.True(t, macaroon.ErrRevoked.Has(keys.check(ctx, keys.sibling, revoker)))
+
+	require.Equal(t, 2, revoker.Len())
+	require.Error(t, revoker.Revoke([]byte("short")))
+}
+
+func TestMemory_Tails(t *testing.T) {
+	ctx := context.Background()
+	keys := newTestKeys(t)
+
+	mac, err := macaroon.ParseMacaroon(keys.restricted.SerializeRaw())
+	require.NoError(t, err)
+
+	tails := mac.Tails(keys.secret)
+	require.Len(t, tails, 2)
+	require.Equal(t, keys.root.Tail(), tails[0])
+	require.Equal(t, keys.restricted.Tail(), tails[1])
+
+	for _, tail := range tails {
+		revoker := revocation.NewMemory()
+		require.NoError(t, revoker.Revoke(tail))
+
+		revoked, err := revoker.Check(ctx, tails)
+		require.NoError(t, err)
+		require.True(t, revoked)
+	}
+
+	revoker := revocation.NewMemory()
+	require.NoError(t, revoker.Revoke(keys.sibling.Tail()))
+	revoked, err := revoker.Check(ctx, tails)
+	require.NoError(t, err)
+	require.False(t, revoked)
+}
+
+func TestList(t *testing.T) {
+	a, b, c := revocation.Tail(testrand.Bytes(32)), revocation.Tail(testrand.Bytes(32)), revocation.Tail(testrand.Bytes(32))
+	issued := time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC)
+
+	list := revocation.NewList(1, issued, b, a, b)
+	require.Len(t, list.Tails, 2)
+	require.True(t, list.Contains(a[:]))
+	require.True(t, list.Contains(b[:]))
+	require.False(t, list.Contains(c[:]))
+
+	parsed, err := revocation.ParseList(list.Bytes())
+	require.NoError(t, err)
+	require.Equal(t, list.Sequence, parsed.Sequence)
+	require.True(t, list.Issued.Equal(parsed.Issued))
+	require.Equal(t, list.Tails, parsed.Tails)
+
+	merged := revocation.Merge(list, revocation.NewList(3, issued.Add(-time.Hour), c, a), nil)
+	require.EqualValues(t, 3, merged.Sequence)
+	require.Equal(t, issued, merged.Issued)
+	require.Len(t, merged.Tails, 3)
+
+	for _, invalid := range [][]byte{
+		nil,
+		[]byte("SRL1"),
+		list.Bytes()[:len(list.Bytes())-1],
+		append(list.Bytes(), 0),
+	} {
+		_, err := revocation.ParseList(invalid)
+		require.Error(t, err)
+	}
+}
+
+func TestSignedList(t *testing.T) {
+	ctx := context.Background()
+	keys := newTestKeys(t)
+
+	public, private, err := ed25519.GenerateKey(nil)
+	require.NoError(t, err)
+	otherPublic, otherPrivate, err := ed25519.GenerateKey(nil)
+	require.NoError(t, err)
+
+	tail, err := revocation.TailFromBytes(keys.restricted.Tail())
+	require.NoError(t, err)
+
+	signed, err := revocation.NewList(7, time.Now(), tail).Sign(private)
+	require.NoError(t, err)
+
+	list, err := revocation.ParseSignedList(signed, otherPublic, public)
+	require.NoError(t, err)
+	require.EqualValues(t, 7, list.Sequence)
+
+	_, err = revocation.ParseSignedList(signed, otherPublic)
+	require.Error(t, err)
+
+	tampered := append([]byte{}, signed...)
+	tampered[len(tampered)-ed25519.SignatureSize-1] ^= 1
+	_, err = revocation.ParseSignedList(tampered, public)
+	require.Error(t, err)
+
+	// lists from several issuers can be merged by a gateway.
+	otherSigned, err := revocation.NewList(1, time.Now(), revocation.Tail(testrand.Bytes(32))).Sign(otherPrivate)
+	require.NoError(t, err)
+	otherList, err := revocation.ParseSignedList(otherSigned, public, otherPublic)
+	require.NoError(t, err)
+
+	revoker := revocation.NewMemory()
+	revoker.Merge(list)
+	revoker.Merge(otherList)
+	require.Equal(t, 2, revoker.Len())
+	require.EqualValues(t, 7, revoker.List().Sequence)
+
+	require.True(t, macaroon.ErrRevoked.Has(keys.check(ctx, keys.restricted, revoker)))
+	require.NoError(t, keys.check(ctx, keys.sibling, revoker))
+}
+
+func TestFile(t *testing.T) {
+	ctx := context.Background()
+	keys := newTestKeys(t)
+	path := filepath.Join(t.TempDir(), "revoked")
+
+	revoker, err := revocation.OpenFile(path)
+	require.NoError(t, err)
+	require.Zero(t, revoker.Len())
+
+	require.NoError(t, revoker.Revoke(keys.restricted.Tail()))
+	require.NoError(t, revoker.Merge(revocation.NewList(2, time.Now(), revocation.Tail(testrand.Bytes(32)))))
+
+	reopened, err := revocation.OpenFile(path)
+	require.NoError(t, err)
+	require.Equal(t, 2, reopened.Len())
+	require.EqualValues(t, 2, reopened.List().Sequence)
+
+	require.True(t, macaroon.ErrRevoked.Has(keys.check(ctx, keys.restricted, reopened)))
+	require.NoError(t, keys.check(ctx, keys.root, reopened))
+
+	matches, err := filepath.Glob(filepath.Join(filepath.Dir(path), "*.tmp"))
+	require.NoError(t, err)
+	require.Empty(t, matches)
+}
@@ -41,11 +41,24 @@ func (m *Macaroon) Serialize() (data []byte) {
 	data = append(data, 0)
 
 	// Serialize caveats
-	for _, cav := range m.caveats {
+	for i, cav := range m.caveats {
+		thirdParty, isThirdParty := m.thirdParty[i]
+		if isThirdParty && thirdParty.location != "" {
+			data = serializePacket(data, packet{
+				fieldType: fieldLocation,
+				data:      []byte(thirdParty.location),
+			})
+		}
 		data = serializePacket(data, packet{
 			fieldType: fieldIdentifier,
 			data:      cav,
 		})
+		if isThirdParty {
+			data = serializePacket(data, packet{
+				fieldType: fieldVerificationID,
+				data:      thirdParty.verificationID,
+			})
+		}
 		data = append(data, 0)
 	}
 
@@ -78,42 +91,50 @@ func appendVarint(data []byte, x int) []byte {
 
 // ParseMacaroon converts binary to macaroon.
 func ParseMacaroon(data []byte) (_ *Macaroon, err error) {
+	mac, _, err := parseMacaroon(data)
+	return mac, err
+}
+
+// parseMacaroon converts binary to macaroon and returns the data after it.
+func parseMacaroon(data []byte) (_ *Macaroon, rest []byte, err error) {
 	if len(data) < 2 {
-		return nil, errors.New("empty macaroon")
+		return nil, nil, errors.New("empty macaroon")
 	}
 	if data[0] != version {
-		return nil, errors.New("invalid macaroon version")
+		return nil, nil, errors.New("invalid macaroon version")
 	}
 	// skip version
 	data = data[1:]
 	// Parse Location
 	data, section, err := parseSection(data)
 	if err != nil {
-		return nil, err
+		return nil, nil, err
 	}
 	if len(section) > 0 && section[0].fieldType == fieldLocation {
 		section = section[1:]
 	}
 	if len(section) != 1 || section[0].fieldType != fieldIdentifier {
-		return nil, errors.New("invalid macaroon header")
+		return nil, nil, errors.New("invalid macaroon header")
 	}
 
 	mac := Macaroon{}
 	mac.head = section[0].data
 	for {
-		rest, section, err := parseSection(data)
+		remaining, section, err := parseSection(data)
 		if err != nil {
-			return nil, err
+			return nil, nil, err
 		}
-		data = rest
+		data = remaining
 		if len(section) == 0 {
 			break
 		}
+		var location []byte
 		if len(section) > 0 && section[0].fieldType == fieldLocation {
+			location = section[0].data
 			section = section[1:]
 		}
 		if len(section) == 0 || section[0].fieldType != fieldIdentifier {
-			return nil, errors.New("no Identifier in caveat")
+			return nil, nil, errors.New("no Identifier in caveat")
 		}
 		cav := slices.Clone(section[0].data)
 		section = section[1:]
@@ -126,31 +147,33 @@ func ParseMacaroon(data []byte) (_ *Macaroon, err error) {
 			continue
 		}
 		if len(section) != 1 {
-			return nil, errors.New("extra fields found in caveat")
+			return nil, nil, errors.New("extra fields found in caveat")
 		}
 		if section[0].fieldType != fieldVerificationID {
-			return nil, errors.New("invalid field found in caveat")
+			return nil, nil, errors.New("invalid field found in caveat")
+		}
+		if mac.thirdParty == nil {
+			mac.thirdParty = map[int]thirdPartyCaveat{}
+		}
+		mac.thirdParty[len(mac.caveats)] = thirdPartyCaveat{
+			location:       string(location),
+			verificationID: slices.Clone(section[0].data),
 		}
-		// cav.VerificationId = section[0].data
 		mac.caveats = append(mac.caveats, cav)
 	}
-	_, sig, err := parsePacket(data)
+	rest, sig, err := parsePacket(data)
 	if err != nil {
-		return nil, err
+		return nil, nil, err
 	}
 	if sig.fieldType != fieldSignature {
-		return nil, errors.New("unexpected field found instead of signature")
+		return nil, nil, errors.New("unexpected field found instead of signature")
 	}
 	if len(sig.data) != 32 {
-		return nil, errors.New("signature has unexpected length")
+		return nil, nil, errors.New("signature has unexpected length")
 	}
 	mac.tail = make([]byte, 32)
 	copy(mac.tail, sig.data)
-	// return data, nil
-	//    Parse Identity
-	//    Parse caveats
-	//    Parse tail
-	return &mac, nil
+	return &mac, rest, nil
 }
 
 // parseSection returns data leftover and packet array.
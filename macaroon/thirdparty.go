@@ -0,0 +1,186 @@
+// Copyright (C) 2026 Storj Labs, Inc.
+// See LICENSE for copying information.
+
+package macaroon
+
+import (
+	"bytes"
+	"crypto/rand"
+	"crypto/subtle"
+	"errors"
+	"slices"
+
+	"golang.org/x/crypto/nacl/secretbox"
+)
+
+// maxDischargeDepth limits how deep discharges with their own third-party
+// caveats can be nested.
+const maxDischargeDepth = 8
+
+// verificationNonceSize is the size of the secretbox nonce prefixed to
+// verification ids.
+const verificationNonceSize = 24
+
+// thirdPartyCaveat contains the details of a caveat that is verified by a
+// discharge macaroon issued by a third party.
+type thirdPartyCaveat struct {
+	location string
+	// verificationID is the caveat key encrypted with the tail preceding the caveat.
+	verificationID []byte
+}
+
+func (caveat thirdPartyCaveat) clone() thirdPartyCaveat {
+	return thirdPartyCaveat{
+		location:       caveat.location,
+		verificationID: slices.Clone(caveat.verificationID),
+	}
+}
+
+// ThirdPartyCaveat is a caveat that must be satisfied by a discharge macaroon.
+type ThirdPartyCaveat struct {
+	// Location is a hint where the discharge macaroon can be acquired.
+	Location string
+	// ID identifies the caveat for the third party. It usually contains the
+	// caveat key and the condition encrypted for the third party.
+	ID []byte
+}
+
+// AddThirdPartyCaveat creates signed macaroon with appended third-party
+// caveat. The caveat is satisfied by a discharge macaroon created with
+// NewDischarge from the same caveat key and id.
+//
+// The caveat key must be random and must be shared only with the third party,
+// usually by encrypting it into the caveat id.
+func (m *Macaroon) AddThirdPartyCaveat(caveatKey, caveatID []byte, location string) (macaroon *Macaroon, err error) {
+	if len(caveatKey) == 0 {
+		return nil, errors.New("missing caveat key")
+	}
+	if len(caveatID) == 0 {
+		return nil, errors.New("missing caveat id")
+	}
+
+	var nonce [verificationNonceSize]byte
+	if _, err := rand.Read(nonce[:]); err != nil {
+		return nil, err
+	}
+	verificationID := secretbox.Seal(nonce[:], caveatKey, &nonce, tailKey(m.tail))
+
+	macaroon = m.Copy()
+	if macaroon.thirdParty == nil {
+		macaroon.thirdParty = map[int]thirdPartyCaveat{}
+	}
+	macaroon.thirdParty[len(macaroon.caveats)] = thirdPartyCaveat{
+		location:       location,
+		verificationID: verificationID,
+	}
+	macaroon.caveats = append(macaroon.caveats, slices.Clone(caveatID))
+	macaroon.tail = signThirdParty(macaroon.tail, verificationID, caveatID)
+
+	return macaroon, nil
+}
+
+// ThirdPartyCaveats returns copy of macaroon third-party caveats.
+func (m *Macaroon) ThirdPartyCaveats() (caveats []ThirdPartyCaveat) {
+	for i, cav := range m.caveats {
+		caveat, ok := m.thirdParty[i]
+		if !ok {
+			continue
+		}
+		caveats = append(caveats, ThirdPartyCaveat{
+			Location: caveat.location,
+			ID:       slices.Clone(cav),
+		})
+	}
+	return caveats
+}
+
+// NewDischarge creates a discharge macaroon for the third-party caveat with
+// the specified caveat key and id. The third party can restrict the discharge
+// further with first-party caveats, before it's bound to the root macaroon
+// with Bind.
+func NewDischarge(caveatKey, caveatID []byte) *Macaroon {
+	return NewUnrestrictedFromParts(slices.Clone(caveatID), caveatKey)
+}
+
+// Bind returns a copy of the discharge macaroon bound to the root macaroon,
+// such that it can't be used together with any other macaroon.
+//
+// Adding caveats to the root macaroon invalidates the bound discharges.
+func (m *Macaroon) Bind(root *Macaroon) *Macaroon {
+	discharge := m.Copy()
+	discharge.tail = bindTail(root.tail, m.tail)
+	return discharge
+}
+
+// verifyDischarges verifies that every third-party caveat of root is
+// satisfied by exactly one of the discharges, and that every discharge is used.
+func verifyDischarges(root *Macaroon, tails [][]byte, discharges []*Macaroon) bool {
+	used := make([]bool, len(discharges))
+
+	var verify func(m *Macaroon, tails [][]byte, depth int) bool
+	verify = func(m *Macaroon, tails [][]byte, depth int) bool {
+		if len(m.thirdParty) > 0 && depth >= maxDischargeDepth {
+			return false
+		}
+		for i, caveat := range m.thirdParty {
+			if len(caveat.verificationID) < verificationNonceSize+secretbox.Overhead {
+				return false
+			}
+			caveatKey, ok := secretbox.Open(nil, caveat.verificationID[verificationNonceSize:], nonceOf(caveat.verificationID), tailKey(tails[i]))
+			if !ok {
+				return false
+			}
+
+			k := -1
+			for j, discharge := range discharges {
+				if !used[j] && bytes.Equal(discharge.head, m.caveats[i]) {
+					k = j
+					break
+				}
+			}
+			if k < 0 {
+				return false
+			}
+			used[k] = true
+
+			discharge := discharges[k]
+			dischargeTails := discharge.chain(caveatKey)
+			bound := bindTail(root.tail, dischargeTails[len(dischargeTails)-1])
+			if subtle.ConstantTimeCompare(bound, discharge.tail) != 1 {
+				return false
+			}
+
+			if !verify(discharge, dischargeTails, depth+1) {
+				return false
+			}
+		}
+		return true
+	}
+
+	if !verify(root, tails, 0) {
+		return false
+	}
+	return !slices.Contains(used, false)
+}
+
+// signThirdParty returns the tail after adding a third-party caveat.
+func signThirdParty(tail, verificationID, caveatID []byte) []byte {
+	return sign(tail, append(sign(tail, verificationID), sign(tail, caveatID)...))
+}
+
+// bindTail returns the tail of a discharge bound to the root tail.
+func bindTail(rootTail, dischargeTail []byte) []byte {
+	return sign(make([]byte, 32), append(slices.Clone(rootTail), dischargeTail...))
+}
+
+func tailKey(tail []byte) *[32]byte {
+	var key [32]byte
+	copy(key[:], tail)
+	return &key
+}
+
+func nonceOf(verificationID []byte) *[verificationNonceSize]byte {
+	var nonce [verificationNonceSize]byte
+	copy(nonce[:], verificationID)
+	return &nonce
+}
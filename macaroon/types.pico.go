@@ -15,25 +15,30 @@ import (
 )
 
 type Caveat struct {
-	DisallowReads                              bool           `json:"disallow_reads,omitempty"`
-	DisallowWrites                             bool           `json:"disallow_writes,omitempty"`
-	DisallowLists                              bool           `json:"disallow_lists,omitempty"`
-	DisallowDeletes                            bool           `json:"disallow_deletes,omitempty"`
-	DisallowLocks                              bool           `json:"disallow_locks,omitempty"`
-	DisallowPutRetention                       bool           `json:"disallow_put_retention,omitempty"`
-	DisallowGetRetention                       bool           `json:"disallow_get_retention,omitempty"`
-	DisallowPutLegalHold                       bool           `json:"disallow_put_legal_hold,omitempty"`
-	DisallowGetLegalHold                       bool           `json:"disallow_get_legal_hold,omitempty"`
-	DisallowBypassGovernanceRetention          bool           `json:"disallow_bypass_governance_retention,omitempty"`
-	DisallowPutBucketObjectLockConfiguration   bool           `json:"disallow_put_bucket_object_lock_configuration,omitempty"`
-	DisallowGetBucketObjectLockConfiguration   bool           `json:"disallow_get_bucket_object_lock_configuration,omitempty"`
-	DisallowPutBucketNotificationConfiguration bool           `json:"disallow_put_bucket_notification_configuration,omitempty"`
-	DisallowGetBucketNotificationConfiguration bool           `json:"disallow_get_bucket_notification_configuration,omitempty"`
-	AllowedPaths                               []*Caveat_Path `json:"allowed_paths,omitempty"`
-	NotAfter                                   *time.Time     `json:"not_after,omitempty"`
-	NotBefore                                  *time.Time     `json:"not_before,omitempty"`
-	MaxObjectTtl                               *time.Duration `json:"max_object_ttl,omitempty"`
-	Nonce                                      []byte         `json:"nonce,omitempty"`
+	DisallowReads                              bool                  `json:"disallow_reads,omitempty"`
+	DisallowWrites                             bool                  `json:"disallow_writes,omitempty"`
+	DisallowLists                              bool                  `json:"disallow_lists,omitempty"`
+	DisallowDeletes                            bool                  `json:"disallow_deletes,omitempty"`
+	DisallowLocks                              bool                  `json:"disallow_locks,omitempty"`
+	DisallowPutRetention                       bool                  `json:"disallow_put_retention,omitempty"`
+	DisallowGetRetention                       bool                  `json:"disallow_get_retention,omitempty"`
+	DisallowPutLegalHold                       bool                  `json:"disallow_put_legal_hold,omitempty"`
+	DisallowGetLegalHold                       bool                  `json:"disallow_get_legal_hold,omitempty"`
+	DisallowBypassGovernanceRetention          bool                  `json:"disallow_bypass_governance_retention,omitempty"`
+	DisallowPutBucketObjectLockConfiguration   bool                  `json:"disallow_put_bucket_object_lock_configuration,omitempty"`
+	DisallowGetBucketObjectLockConfiguration   bool                  `json:"disallow_get_bucket_object_lock_configuration,omitempty"`
+	DisallowPutBucketNotificationConfiguration bool                  `json:"disallow_put_bucket_notification_configuration,omitempty"`
+	DisallowGetBucketNotificationConfiguration bool                  `json:"disallow_get_bucket_notification_configuration,omitempty"`
+	AllowedPaths                               []*Caveat_Path        `json:"allowed_paths,omitempty"`
+	NotAfter                                   *time.Time            `json:"not_after,omitempty"`
+	NotBefore                                  *time.Time            `json:"not_before,omitempty"`
+	MaxObjectTtl                               *time.Duration        `json:"max_object_ttl,omitempty"`
+	AllowedNetworks                            []string              `json:"allowed_networks,omitempty"`
+	MaxObjectSize                              int64                 `json:"max_object_size,omitempty"`
+	MaxUploadSize                              int64                 `json:"max_upload_size,omitempty"`
+	RateLimit                                  *Caveat_RateLimit     `json:"rate_limit,omitempty"`
+	AllowedPathPatterns                        []*Caveat_PathPattern `json:"allowed_path_patterns,omitempty"`
+	Nonce                                      []byte                `json:"nonce,omitempty"`
 }
 
 func (m *Caveat) Encode(c *picobuf.Encoder) bool {
@@ -60,6 +65,13 @@ func (m *Caveat) Encode(c *picobuf.Encoder) bool {
 	(*picoconv.Timestamp)(m.NotAfter).PicoEncode(c, 20)
 	(*picoconv.Timestamp)(m.NotBefore).PicoEncode(c, 21)
 	(*picoconv.Duration)(m.MaxObjectTtl).PicoEncode(c, 22)
+	c.RepeatedString(23, &m.AllowedNetworks)
+	c.Int64(24, &m.MaxObjectSize)
+	c.Int64(25, &m.MaxUploadSize)
+	c.Message(26, m.RateLimit.Encode)
+	for _, x := range m.AllowedPathPatterns {
+		c.AlwaysMessage(27, x.Encode)
+	}
 	c.Bytes(30, &m.Nonce)
 	return true
 }
@@ -105,6 +117,20 @@ func (m *Caveat) Decode(c *picobuf.Decoder) {
 		}
 		(*picoconv.Duration)(m.MaxObjectTtl).PicoDecode(c, 22)
 	}
+	c.RepeatedString(23, &m.AllowedNetworks)
+	c.Int64(24, &m.MaxObjectSize)
+	c.Int64(25, &m.MaxUploadSize)
+	c.Message(26, func(c *picobuf.Decoder) {
+		if m.RateLimit == nil {
+			m.RateLimit = new(Caveat_RateLimit)
+		}
+		m.RateLimit.Decode(c)
+	})
+	c.RepeatedMessage(27, func(c *picobuf.Decoder) {
+		x := new(Caveat_PathPattern)
+		c.Loop(x.Decode)
+		m.AllowedPathPatterns = append(m.AllowedPathPatterns, x)
+	})
 	c.Bytes(30, &m.Nonce)
 }
 
@@ -129,3 +155,52 @@ func (m *Caveat_Path) Decode(c *picobuf.Decoder) {
 	c.Bytes(1, &m.Bucket)
 	c.Bytes(2, &m.EncryptedPathPrefix)
 }
+
+type Caveat_RateLimit struct {
+	Requests int64          `json:"requests,omitempty"`
+	Period   *time.Duration `json:"period,omitempty"`
+}
+
+func (m *Caveat_RateLimit) Encode(c *picobuf.Encoder) bool {
+	if m == nil {
+		return false
+	}
+	c.Int64(1, &m.Requests)
+	(*picoconv.Duration)(m.Period).PicoEncode(c, 2)
+	return true
+}
+
+func (m *Caveat_RateLimit) Decode(c *picobuf.Decoder) {
+	if m == nil {
+		return
+	}
+	c.Int64(1, &m.Requests)
+	if c.PendingField() == 2 {
+		if m.Period == nil {
+			m.Period = new(time.Duration)
+		}
+		(*picoconv.Duration)(m.Period).PicoDecode(c, 2)
+	}
+}
+
+type Caveat_PathPattern struct {
+	Bucket           []byte `json:"bucket,omitempty"`
+	EncryptedPattern []byte `json:"encrypted_pattern,omitempty"`
+}
+
+func (m *Caveat_PathPattern) Encode(c *picobuf.Encoder) bool {
+	if m == nil {
+		return false
+	}
+	c.Bytes(1, &m.Bucket)
+	c.Bytes(2, &m.EncryptedPattern)
+	return true
+}
+
+func (m *Caveat_PathPattern) Decode(c *picobuf.Decoder) {
+	if m == nil {
+		return
+	}
+	c.Bytes(1, &m.Bucket)
+	c.Bytes(2, &m.EncryptedPattern)
+}
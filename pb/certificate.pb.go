@@ -4,6 +4,8 @@
 package pb
 
 import (
+	time "time"
+
 	proto "github.com/gogo/protobuf/proto"
 )
 
@@ -13,6 +15,30 @@ import (
 // proto package needs to be updated.
 const _ = proto.GoGoProtoPackageIsVersion3 // please upgrade the proto package
 
+type CertificateStatus_Status int32
+
+const (
+	CertificateStatus_UNKNOWN CertificateStatus_Status = 0
+	CertificateStatus_GOOD    CertificateStatus_Status = 1
+	CertificateStatus_REVOKED CertificateStatus_Status = 2
+)
+
+var CertificateStatus_Status_name = map[int32]string{
+	0: "UNKNOWN",
+	1: "GOOD",
+	2: "REVOKED",
+}
+
+var CertificateStatus_Status_value = map[string]int32{
+	"UNKNOWN": 0,
+	"GOOD":    1,
+	"REVOKED": 2,
+}
+
+func (x CertificateStatus_Status) String() string {
+	return proto.EnumName(CertificateStatus_Status_name, int32(x))
+}
+
 type SigningRequest struct {
 	AuthToken            string   `protobuf:"bytes,1,opt,name=auth_token,json=authToken,proto3" json:"auth_token,omitempty"`
 	Timestamp            int64    `protobuf:"varint,2,opt,name=timestamp,proto3" json:"timestamp,omitempty"`
@@ -92,3 +118,148 @@ func (m *SigningResponse) GetChain() [][]byte {
 	}
 	return nil
 }
+
+type CertificateStatusRequest struct {
+	// certificate chain starting with the leaf, whose status is requested.
+	Chain                [][]byte `protobuf:"bytes,1,rep,name=chain,proto3" json:"chain,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *CertificateStatusRequest) Reset()         { *m = CertificateStatusRequest{} }
+func (m *CertificateStatusRequest) String() string { return proto.CompactTextString(m) }
+func (*CertificateStatusRequest) ProtoMessage()    {}
+
+func (m *CertificateStatusRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_CertificateStatusRequest.Unmarshal(m, b)
+}
+func (m *CertificateStatusRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_CertificateStatusRequest.Marshal(b, m, deterministic)
+}
+func (m *CertificateStatusRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_CertificateStatusRequest.Merge(m, src)
+}
+func (m *CertificateStatusRequest) XXX_Size() int {
+	return xxx_messageInfo_CertificateStatusRequest.Size(m)
+}
+func (m *CertificateStatusRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_CertificateStatusRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_CertificateStatusRequest proto.InternalMessageInfo
+
+func (m *CertificateStatusRequest) GetChain() [][]byte {
+	if m != nil {
+		return m.Chain
+	}
+	return nil
+}
+
+type CertificateStatusResponse struct {
+	Status               *CertificateStatus `protobuf:"bytes,1,opt,name=status,proto3" json:"status,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}           `json:"-"`
+	XXX_unrecognized     []byte             `json:"-"`
+	XXX_sizecache        int32              `json:"-"`
+}
+
+func (m *CertificateStatusResponse) Reset()         { *m = CertificateStatusResponse{} }
+func (m *CertificateStatusResponse) String() string { return proto.CompactTextString(m) }
+func (*CertificateStatusResponse) ProtoMessage()    {}
+
+func (m *CertificateStatusResponse) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_CertificateStatusResponse.Unmarshal(m, b)
+}
+func (m *CertificateStatusResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_CertificateStatusResponse.Marshal(b, m, deterministic)
+}
+func (m *CertificateStatusResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_CertificateStatusResponse.Merge(m, src)
+}
+func (m *CertificateStatusResponse) XXX_Size() int {
+	return xxx_messageInfo_CertificateStatusResponse.Size(m)
+}
+func (m *CertificateStatusResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_CertificateStatusResponse.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_CertificateStatusResponse proto.InternalMessageInfo
+
+func (m *CertificateStatusResponse) GetStatus() *CertificateStatus {
+	if m != nil {
+		return m.Status
+	}
+	return nil
+}
+
+type CertificateStatus struct {
+	// sha256 hash of the leaf certificate.
+	LeafHash []byte                   `protobuf:"bytes,1,opt,name=leaf_hash,json=leafHash,proto3" json:"leaf_hash,omitempty"`
+	Status   CertificateStatus_Status `protobuf:"varint,2,opt,name=status,proto3,enum=node.CertificateStatus_Status" json:"status,omitempty"`
+	// authority which signed the status.
+	AuthorityId NodeID    `protobuf:"bytes,3,opt,name=authority_id,json=authorityId,proto3,customtype=NodeID" json:"authority_id"`
+	ProducedAt  time.Time `protobuf:"bytes,4,opt,name=produced_at,json=producedAt,proto3,stdtime" json:"produced_at"`
+	// the status must not be used after next_update.
+	NextUpdate           time.Time `protobuf:"bytes,5,opt,name=next_update,json=nextUpdate,proto3,stdtime" json:"next_update"`
+	Signature            []byte    `protobuf:"bytes,6,opt,name=signature,proto3" json:"signature,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}  `json:"-"`
+	XXX_unrecognized     []byte    `json:"-"`
+	XXX_sizecache        int32     `json:"-"`
+}
+
+func (m *CertificateStatus) Reset()         { *m = CertificateStatus{} }
+func (m *CertificateStatus) String() string { return proto.CompactTextString(m) }
+func (*CertificateStatus) ProtoMessage()    {}
+
+func (m *CertificateStatus) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_CertificateStatus.Unmarshal(m, b)
+}
+func (m *CertificateStatus) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_CertificateStatus.Marshal(b, m, deterministic)
+}
+func (m *CertificateStatus) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_CertificateStatus.Merge(m, src)
+}
+func (m *CertificateStatus) XXX_Size() int {
+	return xxx_messageInfo_CertificateStatus.Size(m)
+}
+func (m *CertificateStatus) XXX_DiscardUnknown() {
+	xxx_messageInfo_CertificateStatus.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_CertificateStatus proto.InternalMessageInfo
+
+func (m *CertificateStatus) GetLeafHash() []byte {
+	if m != nil {
+		return m.LeafHash
+	}
+	return nil
+}
+
+func (m *CertificateStatus) GetStatus() CertificateStatus_Status {
+	if m != nil {
+		return m.Status
+	}
+	return CertificateStatus_UNKNOWN
+}
+
+func (m *CertificateStatus) GetProducedAt() time.Time {
+	if m != nil {
+		return m.ProducedAt
+	}
+	return time.Time{}
+}
+
+func (m *CertificateStatus) GetNextUpdate() time.Time {
+	if m != nil {
+		return m.NextUpdate
+	}
+	return time.Time{}
+}
+
+func (m *CertificateStatus) GetSignature() []byte {
+	if m != nil {
+		return m.Signature
+	}
+	return nil
+}
@@ -117,3 +117,82 @@ func (x *drpcCertificates_SignStream) SendAndClose(m *SigningResponse) error {
 	}
 	return x.CloseSend()
 }
+
+type DRPCCertificateStatusesClient interface {
+	DRPCConn() drpc.Conn
+
+	Status(ctx context.Context, in *CertificateStatusRequest) (*CertificateStatusResponse, error)
+}
+
+type drpcCertificateStatusesClient struct {
+	cc drpc.Conn
+}
+
+func NewDRPCCertificateStatusesClient(cc drpc.Conn) DRPCCertificateStatusesClient {
+	return &drpcCertificateStatusesClient{cc}
+}
+
+func (c *drpcCertificateStatusesClient) DRPCConn() drpc.Conn { return c.cc }
+
+func (c *drpcCertificateStatusesClient) Status(ctx context.Context, in *CertificateStatusRequest) (*CertificateStatusResponse, error) {
+	out := new(CertificateStatusResponse)
+	err := c.cc.Invoke(ctx, "/node.CertificateStatuses/Status", drpcEncoding_File_certificate_proto{}, in, out)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+type DRPCCertificateStatusesServer interface {
+	Status(context.Context, *CertificateStatusRequest) (*CertificateStatusResponse, error)
+}
+
+type DRPCCertificateStatusesUnimplementedServer struct{}
+
+func (s *DRPCCertificateStatusesUnimplementedServer) Status(context.Context, *CertificateStatusRequest) (*CertificateStatusResponse, error) {
+	return nil, drpcerr.WithCode(errors.New("Unimplemented"), drpcerr.Unimplemented)
+}
+
+type DRPCCertificateStatusesDescription struct{}
+
+func (DRPCCertificateStatusesDescription) NumMethods() int { return 1 }
+
+func (DRPCCertificateStatusesDescription) Method(n int) (string, drpc.Encoding, drpc.Receiver, interface{}, bool) {
+	switch n {
+	case 0:
+		return "/node.CertificateStatuses/Status", drpcEncoding_File_certificate_proto{},
+			func(srv interface{}, ctx context.Context, in1, in2 interface{}) (drpc.Message, error) {
+				return srv.(DRPCCertificateStatusesServer).
+					Status(
+						ctx,
+						in1.(*CertificateStatusRequest),
+					)
+			}, DRPCCertificateStatusesServer.Status, true
+	default:
+		return "", nil, nil, nil, false
+	}
+}
+
+func DRPCRegisterCertificateStatuses(mux drpc.Mux, impl DRPCCertificateStatusesServer) error {
+	return mux.Register(impl, DRPCCertificateStatusesDescription{})
+}
+
+type DRPCCertificateStatuses_StatusStream interface {
+	drpc.Stream
+	SendAndClose(*CertificateStatusResponse) error
+}
+
+type drpcCertificateStatuses_StatusStream struct {
+	drpc.Stream
+}
+
+func (x *drpcCertificateStatuses_StatusStream) GetStream() drpc.Stream {
+	return x.Stream
+}
+
+func (x *drpcCertificateStatuses_StatusStream) SendAndClose(m *CertificateStatusResponse) error {
+	if err := x.MsgSend(m, drpcEncoding_File_certificate_proto{}); err != nil {
+		return err
+	}
+	return x.CloseSend()
+}
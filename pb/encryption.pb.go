@@ -16,10 +16,12 @@ const _ = proto.GoGoProtoPackageIsVersion3 // please upgrade the proto package
 type CipherSuite int32
 
 const (
-	CipherSuite_ENC_UNSPECIFIED CipherSuite = 0
-	CipherSuite_ENC_NULL        CipherSuite = 1
-	CipherSuite_ENC_AESGCM      CipherSuite = 2
-	CipherSuite_ENC_SECRETBOX   CipherSuite = 3
+	CipherSuite_ENC_UNSPECIFIED              CipherSuite = 0
+	CipherSuite_ENC_NULL                     CipherSuite = 1
+	CipherSuite_ENC_AESGCM                   CipherSuite = 2
+	CipherSuite_ENC_SECRETBOX                CipherSuite = 3
+	CipherSuite_ENC_XCHACHA20POLY1305_STREAM CipherSuite = 5
+	CipherSuite_ENC_PATH_ORDER_PRESERVING    CipherSuite = 6
 )
 
 var CipherSuite_name = map[int32]string{
@@ -27,19 +29,65 @@ var CipherSuite_name = map[int32]string{
 	1: "ENC_NULL",
 	2: "ENC_AESGCM",
 	3: "ENC_SECRETBOX",
+	5: "ENC_XCHACHA20POLY1305_STREAM",
+	6: "ENC_PATH_ORDER_PRESERVING",
 }
 
 var CipherSuite_value = map[string]int32{
-	"ENC_UNSPECIFIED": 0,
-	"ENC_NULL":        1,
-	"ENC_AESGCM":      2,
-	"ENC_SECRETBOX":   3,
+	"ENC_UNSPECIFIED":              0,
+	"ENC_NULL":                     1,
+	"ENC_AESGCM":                   2,
+	"ENC_SECRETBOX":                3,
+	"ENC_XCHACHA20POLY1305_STREAM": 5,
+	"ENC_PATH_ORDER_PRESERVING":    6,
 }
 
 func (x CipherSuite) String() string {
 	return proto.EnumName(CipherSuite_name, int32(x))
 }
 
+type KDFAlgorithm int32
+
+const (
+	KDFAlgorithm_KDF_UNSPECIFIED KDFAlgorithm = 0
+	KDFAlgorithm_KDF_ARGON2ID    KDFAlgorithm = 1
+)
+
+var KDFAlgorithm_name = map[int32]string{
+	0: "KDF_UNSPECIFIED",
+	1: "KDF_ARGON2ID",
+}
+
+var KDFAlgorithm_value = map[string]int32{
+	"KDF_UNSPECIFIED": 0,
+	"KDF_ARGON2ID":    1,
+}
+
+func (x KDFAlgorithm) String() string {
+	return proto.EnumName(KDFAlgorithm_name, int32(x))
+}
+
+type SaltDerivation int32
+
+const (
+	SaltDerivation_SALT_UNSPECIFIED SaltDerivation = 0
+	SaltDerivation_SALT_HMAC_SHA256 SaltDerivation = 1
+)
+
+var SaltDerivation_name = map[int32]string{
+	0: "SALT_UNSPECIFIED",
+	1: "SALT_HMAC_SHA256",
+}
+
+var SaltDerivation_value = map[string]int32{
+	"SALT_UNSPECIFIED": 0,
+	"SALT_HMAC_SHA256": 1,
+}
+
+func (x SaltDerivation) String() string {
+	return proto.EnumName(SaltDerivation_name, int32(x))
+}
+
 type EncryptionParameters struct {
 	CipherSuite          CipherSuite `protobuf:"varint,1,opt,name=cipher_suite,json=cipherSuite,proto3,enum=encryption.CipherSuite" json:"cipher_suite,omitempty"`
 	BlockSize            int64       `protobuf:"varint,2,opt,name=block_size,json=blockSize,proto3" json:"block_size,omitempty"`
@@ -83,3 +131,79 @@ func (m *EncryptionParameters) GetBlockSize() int64 {
 	}
 	return 0
 }
+
+type KDFParameters struct {
+	Version              uint32         `protobuf:"varint,1,opt,name=version,proto3" json:"version,omitempty"`
+	Algorithm            KDFAlgorithm   `protobuf:"varint,2,opt,name=algorithm,proto3,enum=encryption.KDFAlgorithm" json:"algorithm,omitempty"`
+	Memory               uint64         `protobuf:"varint,3,opt,name=memory,proto3" json:"memory,omitempty"`
+	Iterations           uint32         `protobuf:"varint,4,opt,name=iterations,proto3" json:"iterations,omitempty"`
+	Parallelism          uint32         `protobuf:"varint,5,opt,name=parallelism,proto3" json:"parallelism,omitempty"`
+	SaltDerivation       SaltDerivation `protobuf:"varint,6,opt,name=salt_derivation,json=saltDerivation,proto3,enum=encryption.SaltDerivation" json:"salt_derivation,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}       `json:"-"`
+	XXX_unrecognized     []byte         `json:"-"`
+	XXX_sizecache        int32          `json:"-"`
+}
+
+func (m *KDFParameters) Reset()         { *m = KDFParameters{} }
+func (m *KDFParameters) String() string { return proto.CompactTextString(m) }
+func (*KDFParameters) ProtoMessage()    {}
+
+func (m *KDFParameters) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_KDFParameters.Unmarshal(m, b)
+}
+func (m *KDFParameters) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_KDFParameters.Marshal(b, m, deterministic)
+}
+func (m *KDFParameters) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_KDFParameters.Merge(m, src)
+}
+func (m *KDFParameters) XXX_Size() int {
+	return xxx_messageInfo_KDFParameters.Size(m)
+}
+func (m *KDFParameters) XXX_DiscardUnknown() {
+	xxx_messageInfo_KDFParameters.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_KDFParameters proto.InternalMessageInfo
+
+func (m *KDFParameters) GetVersion() uint32 {
+	if m != nil {
+		return m.Version
+	}
+	return 0
+}
+
+func (m *KDFParameters) GetAlgorithm() KDFAlgorithm {
+	if m != nil {
+		return m.Algorithm
+	}
+	return KDFAlgorithm_KDF_UNSPECIFIED
+}
+
+func (m *KDFParameters) GetMemory() uint64 {
+	if m != nil {
+		return m.Memory
+	}
+	return 0
+}
+
+func (m *KDFParameters) GetIterations() uint32 {
+	if m != nil {
+		return m.Iterations
+	}
+	return 0
+}
+
+func (m *KDFParameters) GetParallelism() uint32 {
+	if m != nil {
+		return m.Parallelism
+	}
+	return 0
+}
+
+func (m *KDFParameters) GetSaltDerivation() SaltDerivation {
+	if m != nil {
+		return m.SaltDerivation
+	}
+	return SaltDerivation_SALT_UNSPECIFIED
+}
@@ -18,6 +18,13 @@ type encryptionAccessStoreEntryMarshal struct {
 	Key                  string                `json:"key,omitempty"`
 	PathCipher           CipherSuite           `json:"path_cipher,omitempty"`
 	EncryptionParameters *EncryptionParameters `json:"encryption_parameters,omitempty"`
+	KeyVersion           uint32                `json:"key_version,omitempty"`
+	PreviousKeys         []keyVersionMarshal   `json:"previous_keys,omitempty"`
+}
+
+type keyVersionMarshal struct {
+	Version uint32 `json:"version,omitempty"`
+	Key     string `json:"key,omitempty"`
 }
 
 // MarshalJSON implements the json.Marshaler interface.
@@ -32,6 +39,14 @@ func (se *EncryptionAccess_StoreEntry) MarshalJSON() ([]byte, error) {
 		return nil, err
 	}
 
+	var previousKeys []keyVersionMarshal
+	for _, previous := range se.PreviousKeys {
+		previousKeys = append(previousKeys, keyVersionMarshal{
+			Version: previous.Version,
+			Key:     base64.URLEncoding.EncodeToString(previous.Key),
+		})
+	}
+
 	return json.Marshal(encryptionAccessStoreEntryMarshal{
 		Bucket:               string(se.Bucket),
 		UnencryptedPath:      string(se.UnencryptedPath),
@@ -39,5 +54,7 @@ func (se *EncryptionAccess_StoreEntry) MarshalJSON() ([]byte, error) {
 		Key:                  base64.URLEncoding.EncodeToString(se.Key),
 		PathCipher:           se.PathCipher,
 		EncryptionParameters: se.EncryptionParameters,
+		KeyVersion:           se.KeyVersion,
+		PreviousKeys:         previousKeys,
 	})
 }
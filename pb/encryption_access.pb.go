@@ -18,6 +18,9 @@ type EncryptionAccess struct {
 	StoreEntries                []*EncryptionAccess_StoreEntry `protobuf:"bytes,2,rep,name=store_entries,json=storeEntries,proto3" json:"store_entries,omitempty"`
 	DefaultPathCipher           CipherSuite                    `protobuf:"varint,3,opt,name=default_path_cipher,json=defaultPathCipher,proto3,enum=encryption.CipherSuite" json:"default_path_cipher,omitempty"`
 	DefaultEncryptionParameters *EncryptionParameters          `protobuf:"bytes,4,opt,name=default_encryption_parameters,json=defaultEncryptionParameters,proto3" json:"default_encryption_parameters,omitempty"`
+	DefaultKeyVersion           uint32                         `protobuf:"varint,5,opt,name=default_key_version,json=defaultKeyVersion,proto3" json:"default_key_version,omitempty"`
+	PreviousDefaultKeys         []*EncryptionAccess_KeyVersion `protobuf:"bytes,6,rep,name=previous_default_keys,json=previousDefaultKeys,proto3" json:"previous_default_keys,omitempty"`
+	KdfParameters               *KDFParameters                 `protobuf:"bytes,7,opt,name=kdf_parameters,json=kdfParameters,proto3" json:"kdf_parameters,omitempty"`
 	XXX_NoUnkeyedLiteral        struct{}                       `json:"-"`
 	XXX_unrecognized            []byte                         `json:"-"`
 	XXX_sizecache               int32                          `json:"-"`
@@ -73,16 +76,39 @@ func (m *EncryptionAccess) GetDefaultEncryptionParameters() *EncryptionParameter
 	return nil
 }
 
+func (m *EncryptionAccess) GetDefaultKeyVersion() uint32 {
+	if m != nil {
+		return m.DefaultKeyVersion
+	}
+	return 0
+}
+
+func (m *EncryptionAccess) GetPreviousDefaultKeys() []*EncryptionAccess_KeyVersion {
+	if m != nil {
+		return m.PreviousDefaultKeys
+	}
+	return nil
+}
+
+func (m *EncryptionAccess) GetKdfParameters() *KDFParameters {
+	if m != nil {
+		return m.KdfParameters
+	}
+	return nil
+}
+
 type EncryptionAccess_StoreEntry struct {
-	Bucket               []byte                `protobuf:"bytes,1,opt,name=bucket,proto3" json:"bucket,omitempty"`
-	UnencryptedPath      []byte                `protobuf:"bytes,2,opt,name=unencrypted_path,json=unencryptedPath,proto3" json:"unencrypted_path,omitempty"`
-	EncryptedPath        []byte                `protobuf:"bytes,3,opt,name=encrypted_path,json=encryptedPath,proto3" json:"encrypted_path,omitempty"`
-	Key                  []byte                `protobuf:"bytes,4,opt,name=key,proto3" json:"key,omitempty"`
-	PathCipher           CipherSuite           `protobuf:"varint,5,opt,name=path_cipher,json=pathCipher,proto3,enum=encryption.CipherSuite" json:"path_cipher,omitempty"`
-	EncryptionParameters *EncryptionParameters `protobuf:"bytes,6,opt,name=encryption_parameters,json=encryptionParameters,proto3" json:"encryption_parameters,omitempty"`
-	XXX_NoUnkeyedLiteral struct{}              `json:"-"`
-	XXX_unrecognized     []byte                `json:"-"`
-	XXX_sizecache        int32                 `json:"-"`
+	Bucket               []byte                         `protobuf:"bytes,1,opt,name=bucket,proto3" json:"bucket,omitempty"`
+	UnencryptedPath      []byte                         `protobuf:"bytes,2,opt,name=unencrypted_path,json=unencryptedPath,proto3" json:"unencrypted_path,omitempty"`
+	EncryptedPath        []byte                         `protobuf:"bytes,3,opt,name=encrypted_path,json=encryptedPath,proto3" json:"encrypted_path,omitempty"`
+	Key                  []byte                         `protobuf:"bytes,4,opt,name=key,proto3" json:"key,omitempty"`
+	PathCipher           CipherSuite                    `protobuf:"varint,5,opt,name=path_cipher,json=pathCipher,proto3,enum=encryption.CipherSuite" json:"path_cipher,omitempty"`
+	EncryptionParameters *EncryptionParameters          `protobuf:"bytes,6,opt,name=encryption_parameters,json=encryptionParameters,proto3" json:"encryption_parameters,omitempty"`
+	KeyVersion           uint32                         `protobuf:"varint,7,opt,name=key_version,json=keyVersion,proto3" json:"key_version,omitempty"`
+	PreviousKeys         []*EncryptionAccess_KeyVersion `protobuf:"bytes,8,rep,name=previous_keys,json=previousKeys,proto3" json:"previous_keys,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}                       `json:"-"`
+	XXX_unrecognized     []byte                         `json:"-"`
+	XXX_sizecache        int32                          `json:"-"`
 }
 
 func (m *EncryptionAccess_StoreEntry) Reset()         { *m = EncryptionAccess_StoreEntry{} }
@@ -148,3 +174,61 @@ func (m *EncryptionAccess_StoreEntry) GetEncryptionParameters() *EncryptionParam
 	}
 	return nil
 }
+
+func (m *EncryptionAccess_StoreEntry) GetKeyVersion() uint32 {
+	if m != nil {
+		return m.KeyVersion
+	}
+	return 0
+}
+
+func (m *EncryptionAccess_StoreEntry) GetPreviousKeys() []*EncryptionAccess_KeyVersion {
+	if m != nil {
+		return m.PreviousKeys
+	}
+	return nil
+}
+
+type EncryptionAccess_KeyVersion struct {
+	Version              uint32   `protobuf:"varint,1,opt,name=version,proto3" json:"version,omitempty"`
+	Key                  []byte   `protobuf:"bytes,2,opt,name=key,proto3" json:"key,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *EncryptionAccess_KeyVersion) Reset()         { *m = EncryptionAccess_KeyVersion{} }
+func (m *EncryptionAccess_KeyVersion) String() string { return proto.CompactTextString(m) }
+func (*EncryptionAccess_KeyVersion) ProtoMessage()    {}
+
+func (m *EncryptionAccess_KeyVersion) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_EncryptionAccess_KeyVersion.Unmarshal(m, b)
+}
+func (m *EncryptionAccess_KeyVersion) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_EncryptionAccess_KeyVersion.Marshal(b, m, deterministic)
+}
+func (m *EncryptionAccess_KeyVersion) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_EncryptionAccess_KeyVersion.Merge(m, src)
+}
+func (m *EncryptionAccess_KeyVersion) XXX_Size() int {
+	return xxx_messageInfo_EncryptionAccess_KeyVersion.Size(m)
+}
+func (m *EncryptionAccess_KeyVersion) XXX_DiscardUnknown() {
+	xxx_messageInfo_EncryptionAccess_KeyVersion.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_EncryptionAccess_KeyVersion proto.InternalMessageInfo
+
+func (m *EncryptionAccess_KeyVersion) GetVersion() uint32 {
+	if m != nil {
+		return m.Version
+	}
+	return 0
+}
+
+func (m *EncryptionAccess_KeyVersion) GetKey() []byte {
+	if m != nil {
+		return m.Key
+	}
+	return nil
+}
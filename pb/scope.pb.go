@@ -14,12 +14,13 @@ import (
 const _ = proto.GoGoProtoPackageIsVersion3 // please upgrade the proto package
 
 type Scope struct {
-	SatelliteAddr        string            `protobuf:"bytes,1,opt,name=satellite_addr,json=satelliteAddr,proto3" json:"satellite_addr,omitempty"`
-	ApiKey               []byte            `protobuf:"bytes,2,opt,name=api_key,json=apiKey,proto3" json:"api_key,omitempty"`
-	EncryptionAccess     *EncryptionAccess `protobuf:"bytes,3,opt,name=encryption_access,json=encryptionAccess,proto3" json:"encryption_access,omitempty"`
-	XXX_NoUnkeyedLiteral struct{}          `json:"-"`
-	XXX_unrecognized     []byte            `json:"-"`
-	XXX_sizecache        int32             `json:"-"`
+	SatelliteAddr        string             `protobuf:"bytes,1,opt,name=satellite_addr,json=satelliteAddr,proto3" json:"satellite_addr,omitempty"`
+	ApiKey               []byte             `protobuf:"bytes,2,opt,name=api_key,json=apiKey,proto3" json:"api_key,omitempty"`
+	EncryptionAccess     *EncryptionAccess  `protobuf:"bytes,3,opt,name=encryption_access,json=encryptionAccess,proto3" json:"encryption_access,omitempty"`
+	AdditionalSatellites []*Scope_Satellite `protobuf:"bytes,4,rep,name=additional_satellites,json=additionalSatellites,proto3" json:"additional_satellites,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}           `json:"-"`
+	XXX_unrecognized     []byte             `json:"-"`
+	XXX_sizecache        int32              `json:"-"`
 }
 
 func (m *Scope) Reset()         { *m = Scope{} }
@@ -64,3 +65,65 @@ func (m *Scope) GetEncryptionAccess() *EncryptionAccess {
 	}
 	return nil
 }
+
+func (m *Scope) GetAdditionalSatellites() []*Scope_Satellite {
+	if m != nil {
+		return m.AdditionalSatellites
+	}
+	return nil
+}
+
+// Satellite is an additional satellite the access grant is valid for.
+// If encryption_access is not set, the encryption access of the scope
+// is shared.
+type Scope_Satellite struct {
+	SatelliteAddr        string            `protobuf:"bytes,1,opt,name=satellite_addr,json=satelliteAddr,proto3" json:"satellite_addr,omitempty"`
+	ApiKey               []byte            `protobuf:"bytes,2,opt,name=api_key,json=apiKey,proto3" json:"api_key,omitempty"`
+	EncryptionAccess     *EncryptionAccess `protobuf:"bytes,3,opt,name=encryption_access,json=encryptionAccess,proto3" json:"encryption_access,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}          `json:"-"`
+	XXX_unrecognized     []byte            `json:"-"`
+	XXX_sizecache        int32             `json:"-"`
+}
+
+func (m *Scope_Satellite) Reset()         { *m = Scope_Satellite{} }
+func (m *Scope_Satellite) String() string { return proto.CompactTextString(m) }
+func (*Scope_Satellite) ProtoMessage()    {}
+
+func (m *Scope_Satellite) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_Scope_Satellite.Unmarshal(m, b)
+}
+func (m *Scope_Satellite) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_Scope_Satellite.Marshal(b, m, deterministic)
+}
+func (m *Scope_Satellite) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_Scope_Satellite.Merge(m, src)
+}
+func (m *Scope_Satellite) XXX_Size() int {
+	return xxx_messageInfo_Scope_Satellite.Size(m)
+}
+func (m *Scope_Satellite) XXX_DiscardUnknown() {
+	xxx_messageInfo_Scope_Satellite.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_Scope_Satellite proto.InternalMessageInfo
+
+func (m *Scope_Satellite) GetSatelliteAddr() string {
+	if m != nil {
+		return m.SatelliteAddr
+	}
+	return ""
+}
+
+func (m *Scope_Satellite) GetApiKey() []byte {
+	if m != nil {
+		return m.ApiKey
+	}
+	return nil
+}
+
+func (m *Scope_Satellite) GetEncryptionAccess() *EncryptionAccess {
+	if m != nil {
+		return m.EncryptionAccess
+	}
+	return nil
+}
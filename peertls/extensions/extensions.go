@@ -43,6 +43,10 @@ var (
 	// most recent certificate revocation data
 	// for the current TLS cert chain.
 	RevocationExtID = ExtensionID{2, 999, 1, 2}
+	// RevocationGracePeriodExtID is the asn1 object ID for a pkix extension
+	// containing a certificate revocation, which takes effect after a grace
+	// period. Peers, which don't know about grace periods, ignore it.
+	RevocationGracePeriodExtID = ExtensionID{2, 999, 1, 3}
 	// IdentityVersionExtID is the asn1 object ID for a pkix extension that
 	// specifies the identity version of the certificate chain.
 	IdentityVersionExtID = ExtensionID{2, 999, 2, 1}
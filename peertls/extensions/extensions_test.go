@@ -156,6 +156,17 @@ func TestRevocationMarshaling(t *testing.T) {
 			revocation: extensions.Revocation{
 				KeyHash:   []byte{1, 2, 3},
 				Signature: []byte{5, 4, 3}},
+		}, {
+			// revocations with an effective time include the field in the wire encoding.
+			gobbytes:   nil,
+			revocation: extensions.Revocation{EffectiveAt: 2},
+		}, {
+			gobbytes: nil,
+			revocation: extensions.Revocation{
+				Timestamp:   1,
+				KeyHash:     []byte{1, 2, 3},
+				Signature:   []byte{5, 4, 3},
+				EffectiveAt: 9223372036854775807},
 		}, {
 			gobbytes: nil, // skip the encoding test for this
 			revocation: extensions.Revocation{
@@ -8,6 +8,7 @@ import (
 	"encoding/binary"
 	"io"
 	"math/bits"
+	"slices"
 )
 
 const (
@@ -25,6 +26,16 @@ var wireEncoding = []byte{
 	97, 115, 104, 1, 10, 0, 1, 9, 83, 105, 103, 110, 97, 116, 117, 114, 101, 1, 10, 0, 0, 0,
 }
 
+// wireEncodingEffectiveAt is the initial part of the Revocation gob encoding
+// including the EffectiveAt field. It's only used for revocations with an
+// effective time, so the encoding of other revocations doesn't change.
+var wireEncodingEffectiveAt = []byte{
+	80, 255, 129, 3, 1, 1, 10, 82, 101, 118, 111, 99, 97, 116, 105, 111, 110, 1, 255, 130, 0,
+	1, 4, 1, 9, 84, 105, 109, 101, 115, 116, 97, 109, 112, 1, 4, 0, 1, 7, 75, 101, 121, 72,
+	97, 115, 104, 1, 10, 0, 1, 9, 83, 105, 103, 110, 97, 116, 117, 114, 101, 1, 10, 0, 1, 11,
+	69, 102, 102, 101, 99, 116, 105, 118, 101, 65, 116, 1, 4, 0, 0, 0,
+}
+
 type revocationEncoder struct {
 	value *bytes.Buffer
 }
@@ -33,29 +44,38 @@ func (encoder *revocationEncoder) encode(revocation Revocation) ([]byte, error)
 	encoder.value = new(bytes.Buffer)
 
 	encoder.encodeInt(firstCustomTypeID)
-	delta := uint64(1)
+
+	// fields are encoded with the delta to the previous field number.
+	previous := uint64(0)
+	field := func(number uint64) {
+		encoder.encodeUint(number - previous)
+		previous = number
+	}
+
 	if revocation.Timestamp != 0 {
-		encoder.encodeUint(delta)
+		field(1)
 		encoder.encodeInt(revocation.Timestamp)
-	} else {
-		delta++
 	}
 
 	if len(revocation.KeyHash) > 0 {
-		encoder.encodeUint(delta)
+		field(2)
 		encoder.encodeUint(uint64(len(revocation.KeyHash)))
 		encoder.writeBytes(revocation.KeyHash)
-		delta = uint64(1)
-	} else {
-		delta++
 	}
 
 	if len(revocation.Signature) > 0 {
-		encoder.encodeUint(delta)
+		field(3)
 		encoder.encodeUint(uint64(len(revocation.Signature)))
 		encoder.writeBytes(revocation.Signature)
 	}
 
+	wire := wireEncoding
+	if revocation.EffectiveAt != 0 {
+		wire = wireEncodingEffectiveAt
+		field(4)
+		encoder.encodeInt(revocation.EffectiveAt)
+	}
+
 	encoder.encodeUint(0)
 
 	valueLength := encoder.value.Len()
@@ -65,7 +85,7 @@ func (encoder *revocationEncoder) encode(revocation Revocation) ([]byte, error)
 	value := encoder.value.Bytes()
 	lengthData := value[valueLength:]
 	valueData := value[:valueLength]
-	return append(wireEncoding, append(lengthData, valueData...)...), nil
+	return slices.Concat(wire, lengthData, valueData), nil
 }
 
 func (encoder *revocationEncoder) encodeInt(i int64) {
@@ -107,12 +127,14 @@ type revocationDecoder struct {
 func (decoder *revocationDecoder) decode(data []byte) (revocation Revocation, err error) {
 	decoder.data = bytes.NewBuffer(data)
 
-	wire := make([]byte, len(wireEncoding))
-	_, err = io.ReadFull(decoder.data, wire)
-	if err != nil {
-		return revocation, ErrRevocation.Wrap(err)
-	}
-	if !bytes.Equal(wire, wireEncoding) {
+	var hasEffectiveAt bool
+	switch {
+	case bytes.HasPrefix(data, wireEncoding):
+		decoder.data.Next(len(wireEncoding))
+	case bytes.HasPrefix(data, wireEncodingEffectiveAt):
+		decoder.data.Next(len(wireEncodingEffectiveAt))
+		hasEffectiveAt = true
+	default:
 		return revocation, ErrRevocation.New("invalid revocation encoding")
 	}
 
@@ -160,6 +182,14 @@ func (decoder *revocationDecoder) decode(data []byte) (revocation Revocation, er
 			if err != nil {
 				return revocation, ErrRevocation.Wrap(err)
 			}
+		case 4:
+			if !hasEffectiveAt {
+				return revocation, ErrRevocation.New("invalid field")
+			}
+			revocation.EffectiveAt, err = decoder.decodeInt()
+			if err != nil {
+				return revocation, ErrRevocation.Wrap(err)
+			}
 		default:
 			return revocation, ErrRevocation.New("invalid field")
 		}
@@ -0,0 +1,251 @@
+// Copyright (C) 2026 Storj Labs, Inc.
+// See LICENSE for copying information.
+
+package extensions
+
+import (
+	"bytes"
+	"context"
+	"crypto"
+	"crypto/x509"
+	"encoding/binary"
+	"errors"
+	"time"
+
+	"github.com/zeebo/errs"
+
+	"storj.io/common/pkcrypto"
+)
+
+// revocationBundlePrefix identifies revocation bundles and separates their
+// signatures from other signatures.
+const revocationBundlePrefix = "storj-revocation-bundle-v1\x00"
+
+// maxRevocationFieldSize limits the size of certificates, revocations and
+// signatures in encoded revocation records.
+const maxRevocationFieldSize = 64 << 10
+
+// ErrRevocationBundle is used when an error occurs involving a revocation bundle.
+var ErrRevocationBundle = errs.Class("revocation bundle")
+
+// RevocationBundle is a set of revocation records signed by its publisher,
+// such that peers can pass revocations on to each other.
+//
+// Every record is signed by the revoking certificate authority, hence records
+// can be verified independently of the publisher, and bundles of different
+// publishers can be merged without trusting them.
+type RevocationBundle struct {
+	Timestamp time.Time
+	Publisher *x509.Certificate
+	Records   []RevocationRecord
+	Signature []byte
+}
+
+// NewRevocationBundle creates a revocation bundle of the records, which is
+// signed by the key of the publisher certificate.
+func NewRevocationBundle(key crypto.PrivateKey, publisher *x509.Certificate, records []RevocationRecord) (*RevocationBundle, error) {
+	bundle := &RevocationBundle{
+		Timestamp: time.Now(),
+		Publisher: publisher,
+		Records:   records,
+	}
+	if err := bundle.Sign(key); err != nil {
+		return nil, err
+	}
+	return bundle, nil
+}
+
+// TBSBytes (ToBeSigned) returns the encoded bundle without the signature.
+func (bundle *RevocationBundle) TBSBytes() ([]byte, error) {
+	if bundle.Publisher == nil {
+		return nil, ErrRevocationBundle.New("missing publisher")
+	}
+
+	data := []byte(revocationBundlePrefix)
+	data = binary.BigEndian.AppendUint64(data, uint64(bundle.Timestamp.Unix()))
+	data = appendRevocationField(data, bundle.Publisher.Raw)
+	return encodeRevocationRecords(data, bundle.Records)
+}
+
+// Sign signs the bundle with the key of the publisher.
+func (bundle *RevocationBundle) Sign(key crypto.PrivateKey) error {
+	data, err := bundle.TBSBytes()
+	if err != nil {
+		return err
+	}
+	sig, err := pkcrypto.HashAndSign(key, data)
+	if err != nil {
+		return ErrRevocationBundle.Wrap(err)
+	}
+	bundle.Signature = sig
+	return nil
+}
+
+// Verify checks that the bundle was signed by its publisher and, when trusted
+// publishers are passed, that the publisher is one of them. The records need
+// to be verified separately, see RevocationRecord.Verify.
+func (bundle *RevocationBundle) Verify(trusted ...*x509.Certificate) error {
+	data, err := bundle.TBSBytes()
+	if err != nil {
+		return err
+	}
+	if err := pkcrypto.HashAndVerifySignature(bundle.Publisher.PublicKey, data, bundle.Signature); err != nil {
+		return ErrRevocationBundle.Wrap(err)
+	}
+
+	if len(trusted) == 0 {
+		return nil
+	}
+	for _, cert := range trusted {
+		if pkcrypto.PublicKeyEqual(cert.PublicKey, bundle.Publisher.PublicKey) {
+			return nil
+		}
+	}
+	return ErrRevocationBundle.New("untrusted publisher")
+}
+
+// Marshal serializes the bundle to bytes.
+func (bundle *RevocationBundle) Marshal() ([]byte, error) {
+	data, err := bundle.TBSBytes()
+	if err != nil {
+		return nil, err
+	}
+	return appendRevocationField(data, bundle.Signature), nil
+}
+
+// Unmarshal deserializes a bundle from bytes.
+func (bundle *RevocationBundle) Unmarshal(data []byte) error {
+	if !bytes.HasPrefix(data, []byte(revocationBundlePrefix)) || len(data) < len(revocationBundlePrefix)+8 {
+		return ErrRevocationBundle.New("invalid revocation bundle encoding")
+	}
+	data = data[len(revocationBundlePrefix):]
+	timestamp := int64(binary.BigEndian.Uint64(data))
+	data = data[8:]
+
+	publisherRaw, data, err := readRevocationField(data)
+	if err != nil {
+		return ErrRevocationBundle.Wrap(err)
+	}
+	publisher, err := x509.ParseCertificate(publisherRaw)
+	if err != nil {
+		return ErrRevocationBundle.Wrap(err)
+	}
+
+	records, data, err := decodeRevocationRecords(data)
+	if err != nil {
+		return ErrRevocationBundle.Wrap(err)
+	}
+
+	signature, data, err := readRevocationField(data)
+	if err != nil {
+		return ErrRevocationBundle.Wrap(err)
+	}
+	if len(data) != 0 {
+		return ErrRevocationBundle.New("invalid revocation bundle encoding")
+	}
+
+	*bundle = RevocationBundle{
+		Timestamp: time.Unix(timestamp, 0),
+		Publisher: publisher,
+		Records:   records,
+		Signature: signature,
+	}
+	return nil
+}
+
+// RevocationRecordDB is a RevocationDB, which stores revocation records, e.g.
+// MemoryRevocationDB and FileRevocationDB.
+type RevocationRecordDB interface {
+	RevocationDB
+	PutRecord(ctx context.Context, record RevocationRecord) (stored bool, err error)
+	Records(ctx context.Context) ([]RevocationRecord, error)
+}
+
+// ImportRevocationBundle verifies the bundle and stores its records in the
+// database. Invalid and outdated records don't prevent importing the others;
+// their errors are combined. It returns the number of stored records.
+func ImportRevocationBundle(ctx context.Context, db RevocationRecordDB, bundle *RevocationBundle, trusted ...*x509.Certificate) (imported int, err error) {
+	if err := bundle.Verify(trusted...); err != nil {
+		return 0, err
+	}
+
+	var group errs.Group
+	for _, record := range bundle.Records {
+		stored, err := db.PutRecord(ctx, record)
+		switch {
+		case errors.Is(err, ErrRevocationTimestamp):
+			// NB: bundles are passed around, hence they may be older than
+			// the revocations received by other means.
+		case err != nil:
+			group.Add(err)
+		case stored:
+			imported++
+		}
+	}
+	return imported, group.Err()
+}
+
+// encodeRevocationRecords appends the encoded records to data.
+func encodeRevocationRecords(data []byte, records []RevocationRecord) ([]byte, error) {
+	data = binary.AppendUvarint(data, uint64(len(records)))
+	for _, record := range records {
+		if record.CA == nil {
+			return nil, ErrRevocation.New("missing certificate authority")
+		}
+		rev, err := record.Revocation.Marshal()
+		if err != nil {
+			return nil, err
+		}
+		data = appendRevocationField(data, record.CA.Raw)
+		data = appendRevocationField(data, rev)
+	}
+	return data, nil
+}
+
+// decodeRevocationRecords decodes records encoded with
+// encodeRevocationRecords and returns the remaining data.
+func decodeRevocationRecords(data []byte) (_ []RevocationRecord, rest []byte, err error) {
+	count, n := binary.Uvarint(data)
+	if n <= 0 || count > uint64(len(data)) {
+		return nil, nil, ErrRevocation.New("invalid revocation records encoding")
+	}
+	data = data[n:]
+
+	records := make([]RevocationRecord, 0, count)
+	for range count {
+		var caRaw, rev []byte
+		if caRaw, data, err = readRevocationField(data); err != nil {
+			return nil, nil, err
+		}
+		if rev, data, err = readRevocationField(data); err != nil {
+			return nil, nil, err
+		}
+
+		var record RevocationRecord
+		if record.CA, err = x509.ParseCertificate(caRaw); err != nil {
+			return nil, nil, ErrRevocation.Wrap(err)
+		}
+		if err := record.Revocation.Unmarshal(rev); err != nil {
+			return nil, nil, err
+		}
+		records = append(records, record)
+	}
+	return records, data, nil
+}
+
+// appendRevocationField appends the length prefixed field to data.
+func appendRevocationField(data, field []byte) []byte {
+	data = binary.AppendUvarint(data, uint64(len(field)))
+	return append(data, field...)
+}
+
+// readRevocationField reads a length prefixed field and returns the remaining
+// data.
+func readRevocationField(data []byte) (field, rest []byte, err error) {
+	size, n := binary.Uvarint(data)
+	if n <= 0 || size > maxRevocationFieldSize || size > uint64(len(data)-n) {
+		return nil, nil, ErrRevocation.New("invalid revocation field encoding")
+	}
+	data = data[n:]
+	return data[:size], data[size:], nil
+}
@@ -0,0 +1,318 @@
+// Copyright (C) 2026 Storj Labs, Inc.
+// See LICENSE for copying information.
+
+package extensions
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"errors"
+	"os"
+	"path/filepath"
+	"sort"
+	"sync"
+
+	"github.com/zeebo/errs"
+
+	"storj.io/common/peertls"
+)
+
+// revocationsFilePrefix identifies files written by FileRevocationDB.
+const revocationsFilePrefix = "storj-revocations-v1\x00"
+
+// RevocationRecord is a revocation together with the certificate authority,
+// which signed it. Unlike a revocation alone, a record can be verified without
+// the certificate chain of the revoking peer.
+type RevocationRecord struct {
+	CA         *x509.Certificate
+	Revocation Revocation
+}
+
+// Verify checks that the revocation was signed by the certificate authority.
+func (record RevocationRecord) Verify() error {
+	if record.CA == nil {
+		return ErrRevocation.New("missing certificate authority")
+	}
+	return ErrRevocation.Wrap(record.Revocation.Verify(record.CA))
+}
+
+// MemoryRevocationDB is a RevocationDB, which keeps the last revocation of
+// every certificate authority in memory.
+type MemoryRevocationDB struct {
+	mu      sync.RWMutex
+	records map[[sha256.Size]byte]RevocationRecord
+}
+
+var _ RevocationDB = (*MemoryRevocationDB)(nil)
+
+// NewMemoryRevocationDB returns an empty in-memory revocation database.
+func NewMemoryRevocationDB() *MemoryRevocationDB {
+	return &MemoryRevocationDB{
+		records: map[[sha256.Size]byte]RevocationRecord{},
+	}
+}
+
+// Get returns the last revocation of the certificate authority of the chain,
+// or nil when it has no revocation.
+func (db *MemoryRevocationDB) Get(ctx context.Context, chain []*x509.Certificate) (*Revocation, error) {
+	key, err := revocationKey(chain[peertls.CAIndex])
+	if err != nil {
+		return nil, err
+	}
+
+	db.mu.RLock()
+	defer db.mu.RUnlock()
+
+	record, ok := db.records[key]
+	if !ok {
+		return nil, nil
+	}
+	rev := record.Revocation
+	return &rev, nil
+}
+
+// Put verifies the revocation in the extension against the certificate
+// authority of the chain and stores it, when it's newer than the last one.
+func (db *MemoryRevocationDB) Put(ctx context.Context, chain []*x509.Certificate, ext pkix.Extension) error {
+	var rev Revocation
+	if err := rev.Unmarshal(ext.Value); err != nil {
+		return err
+	}
+	_, err := db.PutRecord(ctx, RevocationRecord{CA: chain[peertls.CAIndex], Revocation: rev})
+	return err
+}
+
+// PutRecord verifies and stores the revocation record, when it's newer than
+// the last revocation of its certificate authority. It returns whether the
+// record was stored; putting the last revocation again is a no-op.
+func (db *MemoryRevocationDB) PutRecord(ctx context.Context, record RevocationRecord) (stored bool, err error) {
+	key, err := verifyRecord(record)
+	if err != nil {
+		return false, err
+	}
+
+	db.mu.Lock()
+	defer db.mu.Unlock()
+
+	_, stored, err = db.put(key, record)
+	return stored, err
+}
+
+// put stores the record under the key and returns the previous record.
+func (db *MemoryRevocationDB) put(key [sha256.Size]byte, record RevocationRecord) (previous *RevocationRecord, stored bool, err error) {
+	if last, ok := db.records[key]; ok {
+		switch {
+		case last.Revocation.Timestamp > record.Revocation.Timestamp:
+			return nil, false, ErrRevocationTimestamp
+		case last.Revocation.Timestamp == record.Revocation.Timestamp:
+			// a revocation issued at the same time replaces the last one only
+			// when it takes effect earlier, e.g. an emergency revocation
+			// during the grace period of a rotation.
+			if record.Revocation.effectiveTime() < last.Revocation.effectiveTime() {
+				break
+			}
+			if !bytes.Equal(last.Revocation.KeyHash, record.Revocation.KeyHash) ||
+				last.Revocation.EffectiveAt != record.Revocation.EffectiveAt {
+				return nil, false, ErrRevocationTimestamp
+			}
+			return nil, false, nil
+		}
+		previous = &last
+	}
+
+	db.records[key] = record
+	return previous, true, nil
+}
+
+// List returns all revocations in the database.
+func (db *MemoryRevocationDB) List(ctx context.Context) ([]*Revocation, error) {
+	records, err := db.Records(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	revs := make([]*Revocation, 0, len(records))
+	for i := range records {
+		revs = append(revs, &records[i].Revocation)
+	}
+	return revs, nil
+}
+
+// Records returns all revocation records in the database ordered by the
+// certificate authority, e.g. for creating a revocation bundle.
+func (db *MemoryRevocationDB) Records(ctx context.Context) ([]RevocationRecord, error) {
+	db.mu.RLock()
+	defer db.mu.RUnlock()
+
+	return db.sortedRecords(), nil
+}
+
+func (db *MemoryRevocationDB) sortedRecords() []RevocationRecord {
+	keys := make([][sha256.Size]byte, 0, len(db.records))
+	for key := range db.records {
+		keys = append(keys, key)
+	}
+	sort.Slice(keys, func(i, k int) bool {
+		return bytes.Compare(keys[i][:], keys[k][:]) < 0
+	})
+
+	records := make([]RevocationRecord, 0, len(keys))
+	for _, key := range keys {
+		records = append(records, db.records[key])
+	}
+	return records
+}
+
+// FileRevocationDB is a RevocationDB, which keeps the last revocation of
+// every certificate authority in memory and persists them to a file.
+type FileRevocationDB struct {
+	path string
+
+	// NB: the lock of the in-memory database is held while the file is
+	// written, such that the file matches the database.
+	mem *MemoryRevocationDB
+}
+
+var _ RevocationDB = (*FileRevocationDB)(nil)
+
+// OpenFileRevocationDB opens the revocation database stored in the file. The
+// file is created on the first revocation, when it doesn't exist.
+func OpenFileRevocationDB(path string) (*FileRevocationDB, error) {
+	db := &FileRevocationDB{
+		path: path,
+		mem:  NewMemoryRevocationDB(),
+	}
+
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return db, nil
+	}
+	if err != nil {
+		return nil, ErrRevocationDB.Wrap(err)
+	}
+
+	if !bytes.HasPrefix(data, []byte(revocationsFilePrefix)) {
+		return nil, ErrRevocationDB.New("invalid revocations file %q", path)
+	}
+	records, _, err := decodeRevocationRecords(data[len(revocationsFilePrefix):])
+	if err != nil {
+		return nil, ErrRevocationDB.Wrap(err)
+	}
+	for _, record := range records {
+		key, err := verifyRecord(record)
+		if err != nil {
+			return nil, ErrRevocationDB.Wrap(err)
+		}
+		db.mem.records[key] = record
+	}
+
+	return db, nil
+}
+
+// Get returns the last revocation of the certificate authority of the chain,
+// or nil when it has no revocation.
+func (db *FileRevocationDB) Get(ctx context.Context, chain []*x509.Certificate) (*Revocation, error) {
+	return db.mem.Get(ctx, chain)
+}
+
+// Put verifies the revocation in the extension against the certificate
+// authority of the chain and stores it, when it's newer than the last one.
+func (db *FileRevocationDB) Put(ctx context.Context, chain []*x509.Certificate, ext pkix.Extension) error {
+	var rev Revocation
+	if err := rev.Unmarshal(ext.Value); err != nil {
+		return err
+	}
+	_, err := db.PutRecord(ctx, RevocationRecord{CA: chain[peertls.CAIndex], Revocation: rev})
+	return err
+}
+
+// PutRecord verifies and stores the revocation record, when it's newer than
+// the last revocation of its certificate authority. It returns whether the
+// record was stored.
+func (db *FileRevocationDB) PutRecord(ctx context.Context, record RevocationRecord) (stored bool, err error) {
+	key, err := verifyRecord(record)
+	if err != nil {
+		return false, err
+	}
+
+	db.mem.mu.Lock()
+	defer db.mem.mu.Unlock()
+
+	previous, stored, err := db.mem.put(key, record)
+	if err != nil || !stored {
+		return false, err
+	}
+
+	if err := db.save(); err != nil {
+		if previous != nil {
+			db.mem.records[key] = *previous
+		} else {
+			delete(db.mem.records, key)
+		}
+		return false, err
+	}
+	return true, nil
+}
+
+// List returns all revocations in the database.
+func (db *FileRevocationDB) List(ctx context.Context) ([]*Revocation, error) {
+	return db.mem.List(ctx)
+}
+
+// Records returns all revocation records in the database ordered by the
+// certificate authority.
+func (db *FileRevocationDB) Records(ctx context.Context) ([]RevocationRecord, error) {
+	return db.mem.Records(ctx)
+}
+
+// save replaces the file with the records of the database. The caller must
+// hold the lock of the in-memory database.
+func (db *FileRevocationDB) save() (err error) {
+	data, err := encodeRevocationRecords([]byte(revocationsFilePrefix), db.mem.sortedRecords())
+	if err != nil {
+		return ErrRevocationDB.Wrap(err)
+	}
+
+	tmp, err := os.CreateTemp(filepath.Dir(db.path), filepath.Base(db.path)+".*.tmp")
+	if err != nil {
+		return ErrRevocationDB.Wrap(err)
+	}
+	defer func() {
+		if err != nil {
+			err = errs.Combine(err, os.Remove(tmp.Name()))
+		}
+	}()
+
+	_, err = tmp.Write(data)
+	if err == nil {
+		err = tmp.Sync()
+	}
+	err = errs.Combine(err, tmp.Close())
+	if err != nil {
+		return ErrRevocationDB.Wrap(err)
+	}
+
+	return ErrRevocationDB.Wrap(os.Rename(tmp.Name(), db.path))
+}
+
+// verifyRecord verifies the record and returns the key of its certificate
+// authority.
+func verifyRecord(record RevocationRecord) ([sha256.Size]byte, error) {
+	if err := record.Verify(); err != nil {
+		return [sha256.Size]byte{}, err
+	}
+	return revocationKey(record.CA)
+}
+
+// revocationKey returns the key of the revocations of the certificate
+// authority, i.e. the hash its node ID is derived from.
+func revocationKey(ca *x509.Certificate) ([sha256.Size]byte, error) {
+	key, err := peertls.DoubleSHA256PublicKey(ca.PublicKey)
+	if err != nil {
+		return key, ErrRevocationDB.Wrap(err)
+	}
+	return key, nil
+}
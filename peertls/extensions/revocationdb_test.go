@@ -0,0 +1,237 @@
+// Copyright (C) 2026 Storj Labs, Inc.
+// See LICENSE for copying information.
+
+package extensions_test
+
+import (
+	"crypto"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"storj.io/common/peertls"
+	"storj.io/common/peertls/extensions"
+	"storj.io/common/peertls/testpeertls"
+	"storj.io/common/storj"
+	"storj.io/common/testcontext"
+)
+
+func newChain(t *testing.T) ([]crypto.PrivateKey, []*x509.Certificate) {
+	keys, chain, err := testpeertls.NewCertChain(2, storj.DefaultIDVersion().Number)
+	require.NoError(t, err)
+	return keys, chain
+}
+
+// newRevocationExt signs the revocation with the key and returns its extension.
+func newRevocationExt(t *testing.T, key crypto.PrivateKey, rev extensions.Revocation) pkix.Extension {
+	require.NoError(t, rev.Sign(key))
+	data, err := rev.Marshal()
+	require.NoError(t, err)
+	return pkix.Extension{Id: extensions.RevocationExtID, Value: data}
+}
+
+func TestRevocationDB(t *testing.T) {
+	ctx := testcontext.New(t)
+
+	file, err := extensions.OpenFileRevocationDB(filepath.Join(ctx.Dir("db"), "revocations.db"))
+	require.NoError(t, err)
+
+	for name, db := range map[string]extensions.RevocationRecordDB{
+		"memory": extensions.NewMemoryRevocationDB(),
+		"file":   file,
+	} {
+		t.Run(name, func(t *testing.T) {
+			keys, chain := newChain(t)
+			caKey := keys[peertls.CAIndex]
+
+			rev, err := db.Get(ctx, chain)
+			require.NoError(t, err)
+			require.Nil(t, rev)
+
+			ext, err := extensions.NewRevocationExt(caKey, chain[peertls.LeafIndex])
+			require.NoError(t, err)
+			require.NoError(t, db.Put(ctx, chain, ext))
+			// the same revocation is put on every handshake.
+			require.NoError(t, db.Put(ctx, chain, ext))
+
+			rev, err = db.Get(ctx, chain)
+			require.NoError(t, err)
+			require.NotNil(t, rev)
+			require.ErrorIs(t, extensions.CheckRevocation(ctx, db, chain), extensions.ErrRevokedCert)
+
+			// newer revocations replace older ones, but not the other way around.
+			newer := newRevocationExt(t, caKey, extensions.Revocation{
+				Timestamp: rev.Timestamp + 1,
+				KeyHash:   rev.KeyHash,
+			})
+			require.NoError(t, db.Put(ctx, chain, newer))
+			require.ErrorIs(t, db.Put(ctx, chain, ext), extensions.ErrRevocationTimestamp)
+
+			newerRev, err := db.Get(ctx, chain)
+			require.NoError(t, err)
+			require.Greater(t, newerRev.Timestamp, rev.Timestamp)
+
+			// revocations must be signed by the CA of the chain.
+			_, otherChain := newChain(t)
+			require.Error(t, db.Put(ctx, otherChain, ext))
+
+			revs, err := db.List(ctx)
+			require.NoError(t, err)
+			require.Len(t, revs, 1)
+			require.Equal(t, newerRev, revs[0])
+
+			records, err := db.Records(ctx)
+			require.NoError(t, err)
+			require.Len(t, records, 1)
+			require.Equal(t, chain[peertls.CAIndex].Raw, records[0].CA.Raw)
+		})
+	}
+
+	reopened, err := extensions.OpenFileRevocationDB(filepath.Join(ctx.Dir("db"), "revocations.db"))
+	require.NoError(t, err)
+	expected, err := file.Records(ctx)
+	require.NoError(t, err)
+	records, err := reopened.Records(ctx)
+	require.NoError(t, err)
+	require.Equal(t, expected, records)
+}
+
+func TestRevocationDB_EmergencyDuringGracePeriod(t *testing.T) {
+	ctx := testcontext.New(t)
+
+	file, err := extensions.OpenFileRevocationDB(filepath.Join(ctx.Dir("db"), "emergency.db"))
+	require.NoError(t, err)
+
+	for name, db := range map[string]extensions.RevocationRecordDB{
+		"memory": extensions.NewMemoryRevocationDB(),
+		"file":   file,
+	} {
+		t.Run(name, func(t *testing.T) {
+			keys, chain := newChain(t)
+			caKey := keys[peertls.CAIndex]
+
+			// the leaf is rotated with a grace period.
+			scheduled, err := extensions.NewRevocationExtWithGracePeriod(caKey, chain[peertls.LeafIndex], time.Hour)
+			require.NoError(t, err)
+			require.True(t, extensions.RevocationGracePeriodExtID.Equal(scheduled.Id))
+			require.NoError(t, db.Put(ctx, chain, scheduled))
+			require.NoError(t, extensions.CheckRevocation(ctx, db, chain))
+
+			rev, err := db.Get(ctx, chain)
+			require.NoError(t, err)
+			require.False(t, rev.InEffect(time.Now()))
+			require.True(t, rev.InEffect(time.Now().Add(time.Hour)))
+			require.LessOrEqual(t, rev.Timestamp, time.Now().Unix())
+
+			// the effective time is signed.
+			tampered := *rev
+			tampered.EffectiveAt += 3600
+			require.Error(t, tampered.Verify(chain[peertls.CAIndex]))
+
+			// an emergency revocation takes effect immediately, even when it's
+			// issued in the same second.
+			emergency, err := extensions.NewRevocationExt(caKey, chain[peertls.LeafIndex])
+			require.NoError(t, err)
+			require.NoError(t, db.Put(ctx, chain, emergency))
+			require.ErrorIs(t, extensions.CheckRevocation(ctx, db, chain), extensions.ErrRevokedCert)
+
+			// the scheduled revocation doesn't replace it again.
+			require.ErrorIs(t, db.Put(ctx, chain, scheduled), extensions.ErrRevocationTimestamp)
+			require.ErrorIs(t, extensions.CheckRevocation(ctx, db, chain), extensions.ErrRevokedCert)
+		})
+	}
+}
+
+func TestOpenFileRevocationDB_Invalid(t *testing.T) {
+	ctx := testcontext.New(t)
+
+	path := ctx.File("revocations.db")
+	require.NoError(t, os.WriteFile(path, []byte("invalid"), 0644))
+
+	_, err := extensions.OpenFileRevocationDB(path)
+	require.True(t, extensions.ErrRevocationDB.Has(err), err)
+}
+
+func TestRevocationBundle(t *testing.T) {
+	ctx := testcontext.New(t)
+
+	publisherKeys, publisherChain := newChain(t)
+	publisherKey, publisher := publisherKeys[peertls.CAIndex], publisherChain[peertls.CAIndex]
+
+	source := extensions.NewMemoryRevocationDB()
+	var chains [][]*x509.Certificate
+	for range 3 {
+		keys, chain := newChain(t)
+		ext, err := extensions.NewRevocationExt(keys[peertls.CAIndex], chain[peertls.LeafIndex])
+		require.NoError(t, err)
+		require.NoError(t, source.Put(ctx, chain, ext))
+		chains = append(chains, chain)
+	}
+	records, err := source.Records(ctx)
+	require.NoError(t, err)
+
+	bundle, err := extensions.NewRevocationBundle(publisherKey, publisher, records)
+	require.NoError(t, err)
+	require.NoError(t, bundle.Verify())
+	require.NoError(t, bundle.Verify(publisher))
+
+	data, err := bundle.Marshal()
+	require.NoError(t, err)
+
+	var decoded extensions.RevocationBundle
+	require.NoError(t, decoded.Unmarshal(data))
+	require.NoError(t, decoded.Verify(publisher))
+	require.Equal(t, bundle.Timestamp.Unix(), decoded.Timestamp.Unix())
+	require.Equal(t, bundle.Signature, decoded.Signature)
+	require.Len(t, decoded.Records, len(records))
+	for i := range records {
+		require.Equal(t, records[i].CA.Raw, decoded.Records[i].CA.Raw)
+		require.Equal(t, records[i].Revocation, decoded.Records[i].Revocation)
+	}
+
+	// truncated and modified bundles are rejected.
+	require.Error(t, new(extensions.RevocationBundle).Unmarshal(data[:len(data)-1]))
+	decoded.Records = decoded.Records[1:]
+	require.True(t, extensions.ErrRevocationBundle.Has(decoded.Verify()))
+
+	// bundles of untrusted publishers are rejected.
+	_, otherChain := newChain(t)
+	require.True(t, extensions.ErrRevocationBundle.Has(bundle.Verify(otherChain[peertls.CAIndex])))
+	_, err = extensions.ImportRevocationBundle(ctx, extensions.NewMemoryRevocationDB(), bundle, otherChain[peertls.CAIndex])
+	require.Error(t, err)
+
+	t.Run("import", func(t *testing.T) {
+		db := extensions.NewMemoryRevocationDB()
+		imported, err := extensions.ImportRevocationBundle(ctx, db, bundle, publisher)
+		require.NoError(t, err)
+		require.Equal(t, 3, imported)
+		for _, chain := range chains {
+			rev, err := db.Get(ctx, chain)
+			require.NoError(t, err)
+			require.NotNil(t, rev)
+		}
+
+		// importing again doesn't store anything.
+		imported, err = extensions.ImportRevocationBundle(ctx, db, bundle, publisher)
+		require.NoError(t, err)
+		require.Zero(t, imported)
+	})
+
+	t.Run("invalid record", func(t *testing.T) {
+		forged := append([]extensions.RevocationRecord{}, records...)
+		forged[0].CA = otherChain[peertls.CAIndex]
+		forgedBundle, err := extensions.NewRevocationBundle(publisherKey, publisher, forged)
+		require.NoError(t, err)
+
+		// the other records are imported regardless.
+		db := extensions.NewMemoryRevocationDB()
+		imported, err := extensions.ImportRevocationBundle(ctx, db, forgedBundle)
+		require.Error(t, err)
+		require.Equal(t, 2, imported)
+	})
+}
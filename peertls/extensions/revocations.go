@@ -26,6 +26,12 @@ var (
 	// RevocationUpdateHandler looks for certificate revocation extensions on a
 	// remote peer's certificate chain, adding them to the revocation DB if valid.
 	RevocationUpdateHandler = NewHandlerFactory(&RevocationExtID, revocationUpdater)
+	// RevocationGracePeriodCheckHandler is like RevocationCheckHandler for
+	// revocations with a grace period.
+	RevocationGracePeriodCheckHandler = NewHandlerFactory(&RevocationGracePeriodExtID, revocationChecker)
+	// RevocationGracePeriodUpdateHandler is like RevocationUpdateHandler for
+	// revocations with a grace period.
+	RevocationGracePeriodUpdateHandler = NewHandlerFactory(&RevocationGracePeriodExtID, revocationUpdater)
 )
 
 // ErrRevocation is used when an error occurs involving a certificate revocation.
@@ -42,10 +48,16 @@ var ErrRevocationTimestamp = Error.New("revocation timestamp is older than last
 
 // Revocation represents a certificate revocation for storage in the revocation
 // database and for use in a TLS extension.
+//
+// Timestamp is the time the revocation was issued, which orders the
+// revocations of a certificate authority. EffectiveAt is the time the
+// revocation takes effect, which is zero when it takes effect immediately.
+// Both are Unix timestamps.
 type Revocation struct {
-	Timestamp int64
-	KeyHash   []byte
-	Signature []byte
+	Timestamp   int64
+	KeyHash     []byte
+	Signature   []byte
+	EffectiveAt int64
 }
 
 // RevocationDB stores certificate revocation data.
@@ -57,16 +69,33 @@ type RevocationDB interface {
 
 // NewRevocationExt generates a revocation extension for a certificate.
 func NewRevocationExt(key crypto.PrivateKey, revokedCert *x509.Certificate) (pkix.Extension, error) {
-	nowUnix := time.Now().Unix()
+	return NewRevocationExtWithGracePeriod(key, revokedCert, 0)
+}
+
+// NewRevocationExtWithGracePeriod generates a revocation extension for a
+// certificate, which takes effect after the grace period. Until then, the
+// revoked certificate is accepted alongside the revoking one. Revocations with
+// a grace period use RevocationGracePeriodExtID.
+func NewRevocationExtWithGracePeriod(key crypto.PrivateKey, revokedCert *x509.Certificate, gracePeriod time.Duration) (pkix.Extension, error) {
+	if gracePeriod < 0 {
+		return pkix.Extension{}, ErrRevocation.New("negative grace period")
+	}
 
 	keyHash, err := peertls.DoubleSHA256PublicKey(revokedCert.PublicKey)
 	if err != nil {
 		return pkix.Extension{}, err
 	}
+
+	now := time.Now()
 	rev := Revocation{
-		Timestamp: nowUnix,
+		Timestamp: now.Unix(),
 		KeyHash:   keyHash[:],
 	}
+	id := RevocationExtID
+	if gracePeriod > 0 {
+		rev.EffectiveAt = now.Add(gracePeriod).Unix()
+		id = RevocationGracePeriodExtID
+	}
 
 	if err := rev.Sign(key); err != nil {
 		return pkix.Extension{}, err
@@ -78,43 +107,60 @@ func NewRevocationExt(key crypto.PrivateKey, revokedCert *x509.Certificate) (pki
 	}
 
 	ext := pkix.Extension{
-		Id:    RevocationExtID,
+		Id:    id,
 		Value: revBytes,
 	}
 
 	return ext, nil
 }
 
+// InEffect returns whether the revocation has taken effect at the given time.
+// Revocations with an effective time in the future are within their grace period.
+func (r Revocation) InEffect(now time.Time) bool {
+	return r.EffectiveAt <= now.Unix()
+}
+
+// effectiveTime returns when the revocation takes effect as a Unix timestamp.
+func (r Revocation) effectiveTime() int64 {
+	return max(r.Timestamp, r.EffectiveAt)
+}
+
+// CheckRevocation returns ErrRevokedCert when the last revocation in the
+// database for the chain revokes its CA or leaf and is in effect.
+func CheckRevocation(ctx context.Context, db RevocationDB, chain []*x509.Certificate) error {
+	ca, leaf := chain[peertls.CAIndex], chain[peertls.LeafIndex]
+	lastRev, lastRevErr := db.Get(ctx, chain)
+	if lastRevErr != nil {
+		return Error.Wrap(lastRevErr)
+	}
+	if lastRev == nil || !lastRev.InEffect(time.Now()) {
+		return nil
+	}
+
+	nodeID, err := peertls.DoubleSHA256PublicKey(ca.PublicKey)
+	if err != nil {
+		return err
+	}
+	leafKeyHash, err := peertls.DoubleSHA256PublicKey(leaf.PublicKey)
+	if err != nil {
+		return err
+	}
+
+	// NB: we trust that anything that made it into the revocation DB is valid
+	//		(i.e. no need for further verification)
+	switch {
+	case bytes.Equal(lastRev.KeyHash, nodeID[:]):
+		fallthrough
+	case bytes.Equal(lastRev.KeyHash, leafKeyHash[:]):
+		return ErrRevokedCert
+	default:
+		return nil
+	}
+}
+
 func revocationChecker(opts *Options) HandlerFunc {
 	return func(_ pkix.Extension, chains [][]*x509.Certificate) error {
-		ca, leaf := chains[0][peertls.CAIndex], chains[0][peertls.LeafIndex]
-		lastRev, lastRevErr := opts.RevocationDB.Get(context.TODO(), chains[0])
-		if lastRevErr != nil {
-			return Error.Wrap(lastRevErr)
-		}
-		if lastRev == nil {
-			return nil
-		}
-
-		nodeID, err := peertls.DoubleSHA256PublicKey(ca.PublicKey)
-		if err != nil {
-			return err
-		}
-		leafKeyHash, err := peertls.DoubleSHA256PublicKey(leaf.PublicKey)
-		if err != nil {
-			return err
-		}
-
-		// NB: we trust that anything that made it into the revocation DB is valid
-		//		(i.e. no need for further verification)
-		switch {
-		case bytes.Equal(lastRev.KeyHash, nodeID[:]):
-			fallthrough
-		case bytes.Equal(lastRev.KeyHash, leafKeyHash[:]):
-			return ErrRevokedCert
-		default:
-			return nil
-		}
+		return CheckRevocation(context.TODO(), opts.RevocationDB, chains[0])
 	}
 }
 
@@ -138,11 +184,17 @@ func (r Revocation) Verify(signingCert *x509.Certificate) error {
 }
 
 // TBSBytes (ToBeSigned) returns the hash of the revoked certificate key hash
-// and the timestamp (i.e. hash(hash(cert bytes) + timestamp)).
+// and the timestamp (i.e. hash(hash(cert bytes) + timestamp)). The effective
+// time is appended to the timestamp, when it's set.
 func (r *Revocation) TBSBytes() []byte {
 	var tsBytes [binary.MaxVarintLen64]byte
 	binary.PutVarint(tsBytes[:], r.Timestamp)
 	toHash := slices.Concat(r.KeyHash, tsBytes[:])
+	if r.EffectiveAt != 0 {
+		var effectiveBytes [binary.MaxVarintLen64]byte
+		binary.PutVarint(effectiveBytes[:], r.EffectiveAt)
+		toHash = append(toHash, effectiveBytes[:]...)
+	}
 
 	return pkcrypto.SHA256Hash(toHash)
 }
@@ -86,8 +86,13 @@ func VerifyCAWhitelist(cas []*x509.Certificate) PeerCertVerificationFunc {
 	}
 }
 
-// TLSCert creates a tls.Certificate from chains, key and leaf.
+// TLSCert creates a tls.Certificate from chains, key and leaf. The key may be
+// any crypto.Signer, e.g. a key held in a hardware token.
 func TLSCert(chain [][]byte, leaf *x509.Certificate, key crypto.PrivateKey) (*tls.Certificate, error) {
+	if _, ok := key.(crypto.Signer); !ok {
+		return nil, errs.New("can't use key of type %T for tls", key)
+	}
+
 	var err error
 	if leaf == nil {
 		leaf, err = pkcrypto.CertFromDER(chain[LeafIndex])
@@ -155,9 +160,16 @@ func CreateCertificate(signee crypto.PublicKey, signer crypto.PrivateKey, templa
 
 	// TODO: should we check for uniqueness?
 	template.ExtraExtensions = append(template.ExtraExtensions, template.Extensions...)
+
+	// NB: the signature algorithm is chosen based on the signer key, since the
+	// template may be a certificate signed with another type of key, e.g. when
+	// signing the CA of an identity with a different version.
+	signingTemplate := *template
+	signingTemplate.SignatureAlgorithm = x509.UnknownSignatureAlgorithm
+
 	cb, err := x509.CreateCertificate(
 		rand.Reader,
-		template,
+		&signingTemplate,
 		issuer,
 		signee,
 		signer,
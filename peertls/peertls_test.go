@@ -100,6 +100,25 @@ func TestVerifyPeerFunc(t *testing.T) {
 	require.NoError(t, err)
 }
 
+func TestVerifyPeerCertChains_IDVersions(t *testing.T) {
+	for number := range storj.IDVersions {
+		keys, chain, err := testpeertls.NewCertChain(2, number)
+		require.NoError(t, err)
+
+		err = peertls.VerifyPeerFunc(peertls.VerifyPeerCertChains)([][]byte{chain[peertls.LeafIndex].Raw, chain[peertls.CAIndex].Raw}, nil)
+		require.NoError(t, err, "version %d", number)
+
+		// certificates of other versions can be signed with the key.
+		_, otherChain, err := testpeertls.NewCertChain(1, storj.V0)
+		require.NoError(t, err)
+		signed, err := peertls.CreateCertificate(otherChain[0].PublicKey, keys[peertls.CAIndex], otherChain[0], chain[peertls.CAIndex])
+		require.NoError(t, err)
+
+		err = peertls.VerifyCAWhitelist([]*x509.Certificate{chain[peertls.CAIndex]})(nil, [][]*x509.Certificate{{chain[peertls.LeafIndex], signed}})
+		require.NoError(t, err, "version %d", number)
+	}
+}
+
 func TestVerifyPeerCertChains(t *testing.T) {
 	t.Skip("Go 1.17 doesn't allow using wrong key for certificate creation")
 
@@ -22,7 +22,7 @@ func NewCertChain(length int, versionNumber storj.IDVersionNumber) (keys []crypt
 	}
 
 	for i := range length {
-		key, err := pkcrypto.GeneratePrivateKey()
+		key, err := version.NewPrivateKey()
 		if err != nil {
 			return nil, nil, err
 		}
@@ -4,6 +4,8 @@
 package tlsopts
 
 import (
+	"time"
+
 	"storj.io/common/peertls/extensions"
 )
 
@@ -14,4 +16,8 @@ type Config struct {
 	UsePeerCAWhitelist  bool   `devDefault:"false" releaseDefault:"true" help:"if true, uses peer ca whitelist checking"`
 	PeerIDVersions      string `default:"latest" help:"identity version(s) the server will be allowed to talk to"`
 	Extensions          extensions.Config
+
+	RevocationBundlesDir           string        `help:"directory to periodically import signed revocation bundles from (requires a revocation database storing revocation records; the importer in the tls options must be run by the caller)"`
+	RevocationBundlesInterval      time.Duration `default:"10m" help:"how often to import revocation bundles"`
+	RevocationBundlePublishersPath string        `help:"path to the certificates of trusted revocation bundle publishers. if empty, bundles of any publisher are imported"`
 }
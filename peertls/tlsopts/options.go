@@ -32,6 +32,16 @@ type Options struct {
 	PeerCAWhitelist   []*x509.Certificate
 	VerificationFuncs *VerificationFuncs
 	Cert              *tls.Certificate
+
+	// RevocationBundles imports revocation bundles into RevDB, when
+	// Config.RevocationBundlesDir is set. The imported revocations are
+	// enforced during handshakes. The caller is responsible for running it,
+	// e.g. with RevocationBundles.Run, and closing it.
+	RevocationBundles *RevocationBundleImporter
+
+	// StatusChecker, when set, checks the certificate status of peers during
+	// handshakes and staples the status of the own identity as a server.
+	StatusChecker *StatusChecker
 }
 
 // VerificationFuncs keeps track of client and server peer certificate verification
@@ -108,6 +118,8 @@ func (opts *Options) configure() (err error) {
 		handlers.Register(
 			extensions.RevocationCheckHandler,
 			extensions.RevocationUpdateHandler,
+			extensions.RevocationGracePeriodCheckHandler,
+			extensions.RevocationGracePeriodUpdateHandler,
 		)
 	}
 
@@ -117,10 +129,45 @@ func (opts *Options) configure() (err error) {
 
 	opts.handleExtensions(handlers)
 
+	if opts.RevDB != nil {
+		// NB: replaced leaves don't carry a revocation extension, hence the
+		// extension handlers don't check them.
+		opts.VerificationFuncs.Add(VerifyNotRevoked(opts.RevDB))
+	}
+
+	if opts.Config.RevocationBundlesDir != "" {
+		if err := opts.configureRevocationBundles(); err != nil {
+			return err
+		}
+	}
+
 	opts.Cert, err = peertls.TLSCert(opts.Ident.RawChain(), opts.Ident.Leaf, opts.Ident.Key)
 	return err
 }
 
+// configureRevocationBundles creates the importer of revocation bundles.
+func (opts *Options) configureRevocationBundles() error {
+	db, ok := opts.RevDB.(extensions.RevocationRecordDB)
+	if !ok {
+		return Error.New("revocation database %T can't import revocation bundles", opts.RevDB)
+	}
+
+	var publishers []*x509.Certificate
+	if opts.Config.RevocationBundlePublishersPath != "" {
+		publishersPEM, err := os.ReadFile(opts.Config.RevocationBundlePublishersPath)
+		if err != nil {
+			return Error.New("unable to find revocation bundle publishers file %v: %v", opts.Config.RevocationBundlePublishersPath, err)
+		}
+		publishers, err = pkcrypto.CertsFromPEM(publishersPEM)
+		if err != nil {
+			return Error.Wrap(err)
+		}
+	}
+
+	opts.RevocationBundles = NewRevocationBundleImporter(db, opts.Config.RevocationBundlesDir, opts.Config.RevocationBundlesInterval, publishers...)
+	return nil
+}
+
 // handleExtensions combines and wraps all extension handler functions into a peer
 // certificate verification function. This allows extension handling via the
 // `VerifyPeerCertificate` field in a `tls.Config` during a TLS handshake.
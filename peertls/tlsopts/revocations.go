@@ -0,0 +1,119 @@
+// Copyright (C) 2026 Storj Labs, Inc.
+// See LICENSE for copying information.
+
+package tlsopts
+
+import (
+	"context"
+	"crypto/x509"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/zeebo/errs"
+
+	"storj.io/common/peertls/extensions"
+	"storj.io/common/sync2"
+)
+
+// RevocationBundleExt is the file extension of revocation bundles, which are
+// imported by RevocationBundleImporter.
+const RevocationBundleExt = ".revocations"
+
+// RevocationBundleImporter periodically imports the revocation bundles in a
+// directory into a revocation database.
+type RevocationBundleImporter struct {
+	db      extensions.RevocationRecordDB
+	dir     string
+	trusted []*x509.Certificate
+	Loop    *sync2.Cycle
+
+	mu       sync.Mutex
+	imported map[string]time.Time
+}
+
+// NewRevocationBundleImporter returns an importer of the bundles in the
+// directory. When trusted publishers are passed, bundles of other publishers
+// are rejected.
+func NewRevocationBundleImporter(db extensions.RevocationRecordDB, dir string, interval time.Duration, trusted ...*x509.Certificate) *RevocationBundleImporter {
+	return &RevocationBundleImporter{
+		db:       db,
+		dir:      dir,
+		trusted:  trusted,
+		Loop:     sync2.NewCycle(interval),
+		imported: map[string]time.Time{},
+	}
+}
+
+// Run imports the bundles until the context is canceled. Failing bundles
+// don't stop the importer; they are retried once they are modified.
+func (importer *RevocationBundleImporter) Run(ctx context.Context) error {
+	return importer.Loop.Run(ctx, func(ctx context.Context) error {
+		_, err := importer.ImportOnce(ctx)
+		if err != nil {
+			mon.Event("revocation_bundle_import_failed")
+		}
+		return nil
+	})
+}
+
+// Close stops the importer.
+func (importer *RevocationBundleImporter) Close() error {
+	importer.Loop.Close()
+	return nil
+}
+
+// ImportOnce imports the bundles, which were added or modified since the last
+// import, and returns the number of stored revocations.
+func (importer *RevocationBundleImporter) ImportOnce(ctx context.Context) (imported int, err error) {
+	defer mon.Task()(&ctx)(&err)
+
+	importer.mu.Lock()
+	defer importer.mu.Unlock()
+
+	entries, err := os.ReadDir(importer.dir)
+	if err != nil {
+		return 0, Error.Wrap(err)
+	}
+
+	var group errs.Group
+	for _, entry := range entries {
+		if entry.IsDir() || !strings.HasSuffix(entry.Name(), RevocationBundleExt) {
+			continue
+		}
+		info, err := entry.Info()
+		if err != nil {
+			group.Add(err)
+			continue
+		}
+		if modTime, ok := importer.imported[entry.Name()]; ok && modTime.Equal(info.ModTime()) {
+			continue
+		}
+
+		n, err := importer.importFile(ctx, filepath.Join(importer.dir, entry.Name()))
+		imported += n
+		if err != nil {
+			group.Add(errs.New("%s: %w", entry.Name(), err))
+			continue
+		}
+		importer.imported[entry.Name()] = info.ModTime()
+	}
+
+	mon.IntVal("revocation_bundle_imported").Observe(int64(imported))
+	return imported, Error.Wrap(group.Err())
+}
+
+func (importer *RevocationBundleImporter) importFile(ctx context.Context, path string) (int, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return 0, err
+	}
+
+	var bundle extensions.RevocationBundle
+	if err := bundle.Unmarshal(data); err != nil {
+		return 0, err
+	}
+	return extensions.ImportRevocationBundle(ctx, importer.db, &bundle, importer.trusted...)
+}
@@ -0,0 +1,103 @@
+// Copyright (C) 2026 Storj Labs, Inc.
+// See LICENSE for copying information.
+
+package tlsopts_test
+
+import (
+	"crypto/x509"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"storj.io/common/identity"
+	"storj.io/common/identity/testidentity"
+	"storj.io/common/peertls/extensions"
+	"storj.io/common/peertls/tlsopts"
+	"storj.io/common/pkcrypto"
+	"storj.io/common/storj"
+	"storj.io/common/testcontext"
+)
+
+func TestRevocationBundleImporter(t *testing.T) {
+	ctx := testcontext.New(t)
+
+	publisher := testidentity.NewPregeneratedSigner(storj.DefaultIDVersion())
+	dir := ctx.Dir("bundles")
+	publishersPath := ctx.File("publishers.pem")
+	require.NoError(t, os.WriteFile(publishersPath, pkcrypto.CertToPEM(publisher.Cert), 0644))
+
+	ident := testidentity.MustPregeneratedIdentity(0, storj.DefaultIDVersion())
+	config := tlsopts.Config{
+		PeerIDVersions:                 "*",
+		RevocationBundlesDir:           dir,
+		RevocationBundlesInterval:      time.Hour,
+		RevocationBundlePublishersPath: publishersPath,
+	}
+
+	// the revocation database must store revocation records.
+	_, err := tlsopts.NewOptions(ident, config, &revocationDB{})
+	require.Error(t, err)
+
+	db := extensions.NewMemoryRevocationDB()
+	opts, err := tlsopts.NewOptions(ident, config, db)
+	require.NoError(t, err)
+	require.NotNil(t, opts.RevocationBundles)
+
+	// revoke the leaf of a peer and publish the revocation.
+	peer, err := testidentity.NewTestManageableFullIdentity(ctx)
+	require.NoError(t, err)
+	oldChain := peer.Chain()
+	_, err = peer.Rotate(0)
+	require.NoError(t, err)
+
+	source := extensions.NewMemoryRevocationDB()
+	require.NoError(t, source.Put(ctx, peer.Chain(), tlsopts.NewExtensionsMap(peer.Leaf)[extensions.RevocationExtID.String()]))
+	records, err := source.Records(ctx)
+	require.NoError(t, err)
+
+	writeBundle := func(name string, ca *identity.FullCertificateAuthority) {
+		bundle, err := extensions.NewRevocationBundle(ca.Key, ca.Cert, records)
+		require.NoError(t, err)
+		data, err := bundle.Marshal()
+		require.NoError(t, err)
+		require.NoError(t, os.WriteFile(filepath.Join(dir, name), data, 0644))
+	}
+
+	// the imported revocations are enforced by the verification functions of
+	// the options.
+	verify := func(_ [][]byte, chains [][]*x509.Certificate) error {
+		for _, fn := range opts.VerificationFuncs.Server() {
+			if err := fn(nil, chains); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+	require.NoError(t, verify(nil, identity.ToChains(oldChain)))
+
+	// bundles of untrusted publishers aren't imported.
+	untrusted, err := testidentity.NewTestCA(ctx)
+	require.NoError(t, err)
+	writeBundle("untrusted"+tlsopts.RevocationBundleExt, untrusted)
+	imported, err := opts.RevocationBundles.ImportOnce(ctx)
+	require.Error(t, err)
+	require.Zero(t, imported)
+	require.NoError(t, os.Remove(filepath.Join(dir, "untrusted"+tlsopts.RevocationBundleExt)))
+
+	// files with other extensions are ignored.
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "README"), []byte("ignored"), 0644))
+
+	writeBundle("satellite"+tlsopts.RevocationBundleExt, publisher)
+	imported, err = opts.RevocationBundles.ImportOnce(ctx)
+	require.NoError(t, err)
+	require.Equal(t, 1, imported)
+	require.ErrorIs(t, verify(nil, identity.ToChains(oldChain)), extensions.ErrRevokedCert)
+
+	// unmodified bundles aren't imported again.
+	imported, err = opts.RevocationBundles.ImportOnce(ctx)
+	require.NoError(t, err)
+	require.Zero(t, imported)
+}
@@ -0,0 +1,331 @@
+// Copyright (C) 2026 Storj Labs, Inc.
+// See LICENSE for copying information.
+
+package tlsopts
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"sync"
+	"time"
+
+	"github.com/zeebo/errs"
+
+	"storj.io/common/pb"
+	"storj.io/common/peertls"
+	"storj.io/common/peertls/extensions"
+	"storj.io/common/pkcrypto"
+	"storj.io/common/rpc/rpcstatus"
+	"storj.io/common/signing"
+	"storj.io/common/tracing"
+)
+
+const (
+	// statusClockSkew is the clock difference to the authority, which is
+	// tolerated when checking the validity of statuses.
+	statusClockSkew = time.Minute
+
+	// maxCachedStatuses limits the number of statuses kept by a StatusChecker.
+	maxCachedStatuses = 10000
+)
+
+var (
+	// ErrCertificateStatus is used when the status of a certificate can't be
+	// determined or is invalid.
+	ErrCertificateStatus = errs.Class("certificate status")
+
+	// ErrStatusRevoked is used when the authority reports a certificate as revoked.
+	ErrStatusRevoked = ErrCertificateStatus.New("certificate is revoked")
+
+	// ErrStatusUnknown is used when the authority doesn't know a certificate.
+	ErrStatusUnknown = ErrCertificateStatus.New("certificate status is unknown")
+)
+
+// StatusFailMode determines how peers are treated, whose certificate status
+// can't be determined, e.g. because the authority is unreachable.
+type StatusFailMode int
+
+const (
+	// StatusSoftFail accepts peers without a status. Only peers, which the
+	// authority reports as revoked, are rejected.
+	StatusSoftFail StatusFailMode = iota
+	// StatusHardFail rejects peers without a good status.
+	StatusHardFail
+)
+
+// CertificateStatusClient requests certificate statuses from an authority,
+// e.g. pb.DRPCCertificateStatusesClient.
+type CertificateStatusClient interface {
+	Status(ctx context.Context, in *pb.CertificateStatusRequest) (*pb.CertificateStatusResponse, error)
+}
+
+// StatusChecker checks the status of certificates with an authority, similar
+// to OCSP. Statuses are cached until they need to be updated.
+//
+// The status of a peer is either stapled to the handshake by the peer, see
+// Options.StatusChecker, or requested from the authority during the handshake.
+type StatusChecker struct {
+	client    CertificateStatusClient
+	authority signing.Signee
+	failMode  StatusFailMode
+
+	// Timeout limits status requests during handshakes.
+	Timeout time.Duration
+
+	mu    sync.Mutex
+	cache map[[sha256.Size]byte]*pb.CertificateStatus
+}
+
+// NewStatusChecker returns a status checker, which requests statuses from the
+// client and verifies them against the authority.
+func NewStatusChecker(client CertificateStatusClient, authority signing.Signee, failMode StatusFailMode) *StatusChecker {
+	return &StatusChecker{
+		client:    client,
+		authority: authority,
+		failMode:  failMode,
+		Timeout:   5 * time.Second,
+		cache:     map[[sha256.Size]byte]*pb.CertificateStatus{},
+	}
+}
+
+// Status returns the verified status of the leaf of the chain. It's requested
+// from the authority when there's no valid cached status.
+func (checker *StatusChecker) Status(ctx context.Context, chain []*x509.Certificate) (_ *pb.CertificateStatus, err error) {
+	defer mon.Task()(&ctx)(&err)
+
+	leafHash := sha256.Sum256(chain[peertls.LeafIndex].Raw)
+	if status := checker.cached(leafHash, time.Now()); status != nil {
+		return status, nil
+	}
+
+	rawChain := make([][]byte, 0, len(chain))
+	for _, cert := range chain {
+		rawChain = append(rawChain, cert.Raw)
+	}
+
+	response, err := checker.client.Status(ctx, &pb.CertificateStatusRequest{Chain: rawChain})
+	if err != nil {
+		return nil, ErrCertificateStatus.Wrap(err)
+	}
+	if response.Status == nil {
+		return nil, ErrCertificateStatus.New("missing status")
+	}
+
+	if err := checker.add(ctx, leafHash, response.Status); err != nil {
+		return nil, err
+	}
+	return response.Status, nil
+}
+
+// Verify checks the status of the leaf of the chain and applies the fail mode.
+func (checker *StatusChecker) Verify(ctx context.Context, chain []*x509.Certificate) (err error) {
+	defer mon.Task()(&ctx)(&err)
+
+	status, err := checker.Status(ctx, chain)
+	if err != nil {
+		return checker.fail(err)
+	}
+	return checker.check(status)
+}
+
+// VerifyPeer returns a peer certificate verification function, which requests
+// the status of the peer from the authority. It can be added to
+// VerificationFuncs, when peers don't staple their status.
+func (checker *StatusChecker) VerifyPeer() peertls.PeerCertVerificationFunc {
+	return func(_ [][]byte, parsedChains [][]*x509.Certificate) (err error) {
+		ctx := tracing.WithoutDistributedTracing(context.Background())
+		defer mon.TaskNamed("verifyCertificateStatus")(&ctx)(&err)
+
+		ctx, cancel := context.WithTimeout(ctx, checker.Timeout)
+		defer cancel()
+
+		return checker.Verify(ctx, parsedChains[0])
+	}
+}
+
+// Staple returns the encoded status of the leaf of the chain, e.g. the own
+// identity, which can be stapled to handshakes.
+func (checker *StatusChecker) Staple(ctx context.Context, chain []*x509.Certificate) (_ []byte, err error) {
+	defer mon.Task()(&ctx)(&err)
+
+	status, err := checker.Status(ctx, chain)
+	if err != nil {
+		return nil, err
+	}
+	staple, err := pb.Marshal(status)
+	return staple, ErrCertificateStatus.Wrap(err)
+}
+
+// VerifyConnection verifies the status of the peer of the connection. It uses
+// the status stapled by the peer, when it's valid, and otherwise requests the
+// status from the authority. It can be used as tls.Config.VerifyConnection.
+func (checker *StatusChecker) VerifyConnection(state tls.ConnectionState) (err error) {
+	ctx := tracing.WithoutDistributedTracing(context.Background())
+	defer mon.Task()(&ctx)(&err)
+
+	if len(state.PeerCertificates) <= peertls.CAIndex {
+		return ErrCertificateStatus.New("missing peer certificates")
+	}
+
+	if len(state.OCSPResponse) > 0 {
+		leafHash := sha256.Sum256(state.PeerCertificates[peertls.LeafIndex].Raw)
+
+		var status pb.CertificateStatus
+		stapleErr := pb.Unmarshal(state.OCSPResponse, &status)
+		if stapleErr == nil {
+			stapleErr = checker.add(ctx, leafHash, &status)
+		}
+		if stapleErr == nil {
+			return checker.check(&status)
+		}
+		// NB: the authority may still provide a valid status.
+		mon.Event("invalid_certificate_status_staple")
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, checker.Timeout)
+	defer cancel()
+
+	return checker.Verify(ctx, state.PeerCertificates)
+}
+
+// check returns the error for the status.
+func (checker *StatusChecker) check(status *pb.CertificateStatus) error {
+	switch status.Status {
+	case pb.CertificateStatus_GOOD:
+		return nil
+	case pb.CertificateStatus_REVOKED:
+		return ErrStatusRevoked
+	default:
+		return checker.fail(ErrStatusUnknown)
+	}
+}
+
+// fail applies the fail mode to the error.
+func (checker *StatusChecker) fail(err error) error {
+	if checker.failMode == StatusHardFail {
+		return err
+	}
+	mon.Event("certificate_status_soft_fail")
+	return nil
+}
+
+// cached returns the cached status of the leaf, when it's still valid.
+func (checker *StatusChecker) cached(leafHash [sha256.Size]byte, now time.Time) *pb.CertificateStatus {
+	checker.mu.Lock()
+	defer checker.mu.Unlock()
+
+	status, ok := checker.cache[leafHash]
+	if !ok || !now.Add(statusClockSkew).Before(status.NextUpdate) {
+		return nil
+	}
+	return status
+}
+
+// add verifies the status of the leaf and adds it to the cache.
+func (checker *StatusChecker) add(ctx context.Context, leafHash [sha256.Size]byte, status *pb.CertificateStatus) error {
+	if err := signing.VerifyCertificateStatus(ctx, checker.authority, status); err != nil {
+		return ErrCertificateStatus.Wrap(err)
+	}
+	if !bytes.Equal(status.LeafHash, leafHash[:]) {
+		return ErrCertificateStatus.New("status of another certificate")
+	}
+
+	now := time.Now()
+	switch {
+	case status.ProducedAt.After(now.Add(statusClockSkew)):
+		return ErrCertificateStatus.New("status produced in the future: %v", status.ProducedAt)
+	case !now.Before(status.NextUpdate):
+		return ErrCertificateStatus.New("status expired: %v", status.NextUpdate)
+	}
+
+	checker.mu.Lock()
+	defer checker.mu.Unlock()
+
+	if cached, ok := checker.cache[leafHash]; ok && cached.ProducedAt.After(status.ProducedAt) {
+		return nil
+	}
+	if len(checker.cache) >= maxCachedStatuses {
+		for key, cached := range checker.cache {
+			if !now.Before(cached.NextUpdate) || len(checker.cache) >= maxCachedStatuses {
+				delete(checker.cache, key)
+			}
+		}
+	}
+	checker.cache[leafHash] = status
+	return nil
+}
+
+// CertificateStatusEndpoint answers certificate status requests with statuses
+// signed by the authority. Certificates are reported as revoked, when their
+// chain is revoked by a revocation in the revocation database.
+type CertificateStatusEndpoint struct {
+	pb.DRPCCertificateStatusesUnimplementedServer
+
+	signer   signing.Signer
+	revDB    extensions.RevocationDB
+	validity time.Duration
+}
+
+var _ pb.DRPCCertificateStatusesServer = (*CertificateStatusEndpoint)(nil)
+
+// NewCertificateStatusEndpoint returns an endpoint, which signs statuses with
+// the signer. The statuses are valid for the validity period.
+func NewCertificateStatusEndpoint(signer signing.Signer, revDB extensions.RevocationDB, validity time.Duration) *CertificateStatusEndpoint {
+	return &CertificateStatusEndpoint{
+		signer:   signer,
+		revDB:    revDB,
+		validity: validity,
+	}
+}
+
+// Status returns the signed status of the leaf of the requested chain.
+func (endpoint *CertificateStatusEndpoint) Status(ctx context.Context, req *pb.CertificateStatusRequest) (_ *pb.CertificateStatusResponse, err error) {
+	defer mon.Task()(&ctx)(&err)
+
+	chain, err := parseStatusChain(req.Chain)
+	if err != nil {
+		return nil, rpcstatus.Error(rpcstatus.InvalidArgument, err.Error())
+	}
+
+	status := pb.CertificateStatus_GOOD
+	err = extensions.CheckRevocation(ctx, endpoint.revDB, chain)
+	switch {
+	case errors.Is(err, extensions.ErrRevokedCert):
+		status = pb.CertificateStatus_REVOKED
+	case err != nil:
+		return nil, rpcstatus.Error(rpcstatus.Internal, err.Error())
+	}
+
+	leafHash := sha256.Sum256(chain[peertls.LeafIndex].Raw)
+	now := time.Now()
+	signed, err := signing.SignCertificateStatus(ctx, endpoint.signer, &pb.CertificateStatus{
+		LeafHash:   leafHash[:],
+		Status:     status,
+		ProducedAt: now,
+		NextUpdate: now.Add(endpoint.validity),
+	})
+	if err != nil {
+		return nil, rpcstatus.Error(rpcstatus.Internal, err.Error())
+	}
+
+	return &pb.CertificateStatusResponse{Status: signed}, nil
+}
+
+// parseStatusChain parses and verifies the chain of a status request.
+func parseStatusChain(rawChain [][]byte) ([]*x509.Certificate, error) {
+	chain, err := pkcrypto.CertsFromDER(rawChain)
+	if err != nil {
+		return nil, err
+	}
+	if len(chain) <= peertls.CAIndex {
+		return nil, ErrCertificateStatus.New("chain too short")
+	}
+	if err := peertls.VerifyPeerCertChains(rawChain, [][]*x509.Certificate{chain}); err != nil {
+		return nil, err
+	}
+	return chain, nil
+}
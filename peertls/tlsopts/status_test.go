@@ -0,0 +1,156 @@
+// Copyright (C) 2026 Storj Labs, Inc.
+// See LICENSE for copying information.
+
+package tlsopts_test
+
+import (
+	"context"
+	"crypto/tls"
+	"errors"
+	"net"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"storj.io/common/identity"
+	"storj.io/common/identity/testidentity"
+	"storj.io/common/pb"
+	"storj.io/common/peertls/extensions"
+	"storj.io/common/peertls/tlsopts"
+	"storj.io/common/signing"
+	"storj.io/common/storj"
+	"storj.io/common/testcontext"
+)
+
+// statusClient counts the status requests and fails them when down.
+type statusClient struct {
+	endpoint *tlsopts.CertificateStatusEndpoint
+	down     atomic.Bool
+	requests atomic.Int64
+}
+
+func (client *statusClient) Status(ctx context.Context, req *pb.CertificateStatusRequest) (*pb.CertificateStatusResponse, error) {
+	client.requests.Add(1)
+	if client.down.Load() {
+		return nil, errors.New("authority unavailable")
+	}
+	return client.endpoint.Status(ctx, req)
+}
+
+func TestStatusChecker(t *testing.T) {
+	ctx := testcontext.New(t)
+
+	authority := testidentity.MustPregeneratedIdentity(0, storj.DefaultIDVersion())
+	db := extensions.NewMemoryRevocationDB()
+	client := &statusClient{
+		endpoint: tlsopts.NewCertificateStatusEndpoint(signing.SignerFromFullIdentity(authority), db, time.Hour),
+	}
+	signee := signing.SigneeFromPeerIdentity(authority.PeerIdentity())
+
+	peer, err := testidentity.NewTestManageableFullIdentity(ctx)
+	require.NoError(t, err)
+	oldChain := peer.Chain()
+	_, err = peer.Rotate(0)
+	require.NoError(t, err)
+	require.NoError(t, db.Put(ctx, peer.Chain(), tlsopts.NewExtensionsMap(peer.Leaf)[extensions.RevocationExtID.String()]))
+
+	soft := tlsopts.NewStatusChecker(client, signee, tlsopts.StatusSoftFail)
+	hard := tlsopts.NewStatusChecker(client, signee, tlsopts.StatusHardFail)
+
+	for _, checker := range []*tlsopts.StatusChecker{soft, hard} {
+		status, err := checker.Status(ctx, peer.Chain())
+		require.NoError(t, err)
+		require.Equal(t, pb.CertificateStatus_GOOD, status.Status)
+		require.Equal(t, authority.ID, status.AuthorityId)
+
+		require.NoError(t, checker.VerifyPeer()(nil, identity.ToChains(peer.Chain())))
+		require.ErrorIs(t, checker.VerifyPeer()(nil, identity.ToChains(oldChain)), tlsopts.ErrStatusRevoked)
+	}
+
+	// statuses are cached.
+	requests := client.requests.Load()
+	require.NoError(t, hard.Verify(ctx, peer.Chain()))
+	require.Equal(t, requests, client.requests.Load())
+
+	// revoked peers are rejected regardless of the fail mode, other peers
+	// only when failing hard.
+	client.down.Store(true)
+	other := testidentity.MustPregeneratedIdentity(1, storj.DefaultIDVersion())
+	require.NoError(t, soft.Verify(ctx, other.Chain()))
+	require.True(t, tlsopts.ErrCertificateStatus.Has(hard.Verify(ctx, other.Chain())))
+	require.ErrorIs(t, soft.Verify(ctx, oldChain), tlsopts.ErrStatusRevoked)
+	client.down.Store(false)
+
+	// statuses must be signed by the authority.
+	impostor := signing.SigneeFromPeerIdentity(other.PeerIdentity())
+	_, err = tlsopts.NewStatusChecker(client, impostor, tlsopts.StatusHardFail).Status(ctx, other.Chain())
+	require.True(t, tlsopts.ErrCertificateStatus.Has(err), err)
+
+	signed, err := client.Status(ctx, &pb.CertificateStatusRequest{Chain: other.RawChain()})
+	require.NoError(t, err)
+	require.NoError(t, signing.VerifyCertificateStatus(ctx, signee, signed.Status))
+	signed.Status.Status = pb.CertificateStatus_GOOD + 1
+	require.Error(t, signing.VerifyCertificateStatus(ctx, signee, signed.Status))
+
+	// invalid chains are rejected by the authority.
+	_, err = client.Status(ctx, &pb.CertificateStatusRequest{Chain: [][]byte{other.Leaf.Raw}})
+	require.Error(t, err)
+}
+
+func TestStatusChecker_Handshake(t *testing.T) {
+	ctx := testcontext.New(t)
+
+	authority := testidentity.MustPregeneratedIdentity(0, storj.DefaultIDVersion())
+	signee := signing.SigneeFromPeerIdentity(authority.PeerIdentity())
+	endpoint := tlsopts.NewCertificateStatusEndpoint(signing.SignerFromFullIdentity(authority), extensions.NewMemoryRevocationDB(), time.Hour)
+
+	serverIdent := testidentity.MustPregeneratedIdentity(1, storj.DefaultIDVersion())
+	clientIdent := testidentity.MustPregeneratedIdentity(2, storj.DefaultIDVersion())
+
+	config := tlsopts.Config{PeerIDVersions: "*"}
+	serverOpts, err := tlsopts.NewOptions(serverIdent, config, nil)
+	require.NoError(t, err)
+	serverClient := &statusClient{endpoint: endpoint}
+	serverOpts.StatusChecker = tlsopts.NewStatusChecker(serverClient, signee, tlsopts.StatusHardFail)
+
+	// the client can't reach the authority, hence it relies on the staple.
+	clientOpts, err := tlsopts.NewOptions(clientIdent, config, nil)
+	require.NoError(t, err)
+	clientClient := &statusClient{endpoint: endpoint}
+	clientClient.down.Store(true)
+	clientOpts.StatusChecker = tlsopts.NewStatusChecker(clientClient, signee, tlsopts.StatusHardFail)
+
+	handshake := func() (*tls.Conn, error) {
+		clientConn, serverConn := net.Pipe()
+		t.Cleanup(func() { _ = clientConn.Close(); _ = serverConn.Close() })
+
+		server := tls.Server(serverConn, serverOpts.ServerTLSConfig())
+		serverErr := make(chan error, 1)
+		go func() { serverErr <- server.HandshakeContext(ctx) }()
+
+		client := tls.Client(clientConn, clientOpts.ClientTLSConfig(serverIdent.ID))
+		if err := client.HandshakeContext(ctx); err != nil {
+			_ = clientConn.Close()
+			<-serverErr
+			return nil, err
+		}
+		return client, <-serverErr
+	}
+
+	client, err := handshake()
+	require.NoError(t, err)
+	require.NotEmpty(t, client.ConnectionState().OCSPResponse)
+
+	var stapled pb.CertificateStatus
+	require.NoError(t, pb.Unmarshal(client.ConnectionState().OCSPResponse, &stapled))
+	require.Equal(t, pb.CertificateStatus_GOOD, stapled.Status)
+
+	// without a staple, the client fails hard.
+	serverClient.down.Store(true)
+	serverOpts.StatusChecker = tlsopts.NewStatusChecker(serverClient, signee, tlsopts.StatusSoftFail)
+	clientOpts.StatusChecker = tlsopts.NewStatusChecker(clientClient, signee, tlsopts.StatusHardFail)
+	_, err = handshake()
+	require.Error(t, err)
+}
@@ -10,6 +10,7 @@ import (
 
 	"storj.io/common/identity"
 	"storj.io/common/peertls"
+	"storj.io/common/peertls/extensions"
 	"storj.io/common/storj"
 	"storj.io/common/tracing"
 )
@@ -73,9 +74,35 @@ func (opts *Options) tlsConfig(isServer bool, verificationFuncs ...peertls.PeerC
 		config.ClientAuth = tls.RequireAnyClientCert
 	}
 
+	if opts.StatusChecker != nil {
+		config.VerifyConnection = opts.StatusChecker.VerifyConnection
+		if isServer {
+			// NB: GetCertificate is only used without Certificates.
+			config.Certificates = nil
+			config.GetCertificate = opts.stapledCertificate
+		}
+	}
+
 	return config
 }
 
+// stapledCertificate returns the certificate with the status of the identity
+// stapled to it. The certificate is returned without status, when it can't be
+// determined, such that peers request it from the authority instead.
+func (opts *Options) stapledCertificate(hello *tls.ClientHelloInfo) (*tls.Certificate, error) {
+	ctx, cancel := context.WithTimeout(hello.Context(), opts.StatusChecker.Timeout)
+	defer cancel()
+
+	cert := *opts.Cert
+	staple, err := opts.StatusChecker.Staple(ctx, opts.Ident.Chain())
+	if err != nil {
+		mon.Event("certificate_status_staple_failed")
+		return &cert, nil
+	}
+	cert.OCSPStaple = staple
+	return &cert, nil
+}
+
 func verifyIdentity(id storj.NodeID) peertls.PeerCertVerificationFunc {
 	return func(_ [][]byte, parsedChains [][]*x509.Certificate) (err error) {
 		ctx := tracing.WithoutDistributedTracing(context.Background())
@@ -92,3 +119,16 @@ func verifyIdentity(id storj.NodeID) peertls.PeerCertVerificationFunc {
 		return nil
 	}
 }
+
+// VerifyNotRevoked returns a verification function, which rejects peers whose
+// certificate chain is revoked by a revocation in the database that is in
+// effect. Unlike the revocation extension handlers, it checks every chain,
+// including the ones without a revocation extension, e.g. leaves that were
+// replaced by identity.ManageableFullIdentity.Rotate after the grace period.
+func VerifyNotRevoked(revocationDB extensions.RevocationDB) peertls.PeerCertVerificationFunc {
+	return func(_ [][]byte, parsedChains [][]*x509.Certificate) (err error) {
+		ctx := tracing.WithoutDistributedTracing(context.Background())
+		defer mon.TaskNamed("verifyNotRevoked")(&ctx)(&err)
+		return Error.Wrap(extensions.CheckRevocation(ctx, revocationDB, parsedChains[0]))
+	}
+}
@@ -4,15 +4,25 @@
 package tlsopts_test
 
 import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"fmt"
+	"net"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 
 	"storj.io/common/identity"
 	"storj.io/common/identity/testidentity"
+	"storj.io/common/peertls"
+	"storj.io/common/peertls/extensions"
 	"storj.io/common/peertls/tlsopts"
 	"storj.io/common/storj"
+	"storj.io/common/testcontext"
 )
 
 func TestVerifyIdentity_success(t *testing.T) {
@@ -59,3 +69,154 @@ func TestVerifyIdentity_error(t *testing.T) {
 		})
 	}
 }
+
+// revocationDB keeps the last revocation of every CA.
+type revocationDB struct {
+	revocations map[storj.NodeID]*extensions.Revocation
+}
+
+func (db *revocationDB) Get(ctx context.Context, chain []*x509.Certificate) (*extensions.Revocation, error) {
+	id, err := identity.NodeIDFromCert(chain[peertls.CAIndex])
+	if err != nil {
+		return nil, err
+	}
+	return db.revocations[id], nil
+}
+
+func (db *revocationDB) Put(ctx context.Context, chain []*x509.Certificate, ext pkix.Extension) error {
+	var rev extensions.Revocation
+	if err := rev.Unmarshal(ext.Value); err != nil {
+		return err
+	}
+	if err := rev.Verify(chain[peertls.CAIndex]); err != nil {
+		return err
+	}
+	id, err := identity.NodeIDFromCert(chain[peertls.CAIndex])
+	if err != nil {
+		return err
+	}
+	db.revocations[id] = &rev
+	return nil
+}
+
+func (db *revocationDB) List(ctx context.Context) ([]*extensions.Revocation, error) {
+	return nil, nil
+}
+
+func TestVerifyNotRevoked_Rotate(t *testing.T) {
+	ctx := testcontext.New(t)
+
+	db := &revocationDB{revocations: map[storj.NodeID]*extensions.Revocation{}}
+	verify := tlsopts.VerifyNotRevoked(db)
+
+	for _, gracePeriod := range []time.Duration{0, time.Hour} {
+		ident, err := testidentity.NewTestManageableFullIdentity(ctx)
+		require.NoError(t, err)
+
+		oldChain := ident.Chain()
+
+		_, err = ident.Rotate(gracePeriod)
+		require.NoError(t, err)
+
+		opts, err := tlsopts.NewOptions(ident.FullIdentity, tlsopts.Config{
+			PeerIDVersions: "*",
+			Extensions:     extensions.Config{Revocation: true},
+		}, db)
+		require.NoError(t, err)
+
+		// the revocation is recorded when the peer presents the new leaf.
+		for _, fn := range opts.VerificationFuncs.Server() {
+			require.NoError(t, fn(nil, identity.ToChains(ident.Chain())))
+		}
+		require.NoError(t, verify(nil, identity.ToChains(ident.Chain())))
+
+		err = verify(nil, identity.ToChains(oldChain))
+		if gracePeriod > 0 {
+			require.NoError(t, err)
+		} else {
+			require.ErrorIs(t, err, extensions.ErrRevokedCert)
+		}
+	}
+}
+
+func TestHandshake_MixedIDVersions(t *testing.T) {
+	ctx := testcontext.New(t)
+
+	for _, clientVersion := range storj.IDVersions {
+		for _, serverVersion := range storj.IDVersions {
+			t.Run(fmt.Sprintf("V%d-V%d", clientVersion.Number, serverVersion.Number), func(t *testing.T) {
+				clientIdent := testidentity.MustPregeneratedSignedIdentity(0, clientVersion)
+				serverIdent := testidentity.MustPregeneratedSignedIdentity(1, serverVersion)
+
+				config := tlsopts.Config{PeerIDVersions: "*"}
+				clientOpts, err := tlsopts.NewOptions(clientIdent, config, nil)
+				require.NoError(t, err)
+				serverOpts, err := tlsopts.NewOptions(serverIdent, config, nil)
+				require.NoError(t, err)
+
+				clientConn, serverConn := net.Pipe()
+				defer ctx.Check(clientConn.Close)
+				defer ctx.Check(serverConn.Close)
+
+				server := tls.Server(serverConn, serverOpts.ServerTLSConfig())
+				ctx.Go(func() error { return server.HandshakeContext(ctx) })
+
+				client := tls.Client(clientConn, clientOpts.ClientTLSConfig(serverIdent.ID))
+				require.NoError(t, client.HandshakeContext(ctx))
+
+				peer, err := identity.PeerIdentityFromChain(client.ConnectionState().PeerCertificates)
+				require.NoError(t, err)
+				require.Equal(t, serverIdent.ID, peer.ID)
+				require.Equal(t, serverVersion.Number, peer.ID.Version().Number)
+			})
+		}
+	}
+}
+
+func TestHandshake_RotatedLeaf(t *testing.T) {
+	ctx := testcontext.New(t)
+
+	ident, err := testidentity.NewTestManageableFullIdentity(ctx)
+	require.NoError(t, err)
+	serverIdent := testidentity.MustPregeneratedSignedIdentity(0, storj.DefaultIDVersion())
+
+	config := tlsopts.Config{
+		PeerIDVersions: "*",
+		Extensions:     extensions.Config{Revocation: true},
+	}
+	serverOpts, err := tlsopts.NewOptions(serverIdent, config, extensions.NewMemoryRevocationDB())
+	require.NoError(t, err)
+
+	handshake := func(clientIdent *identity.FullIdentity) error {
+		clientOpts, err := tlsopts.NewOptions(clientIdent, config, nil)
+		require.NoError(t, err)
+
+		clientConn, serverConn := net.Pipe()
+		defer ctx.Check(clientConn.Close)
+		defer ctx.Check(serverConn.Close)
+
+		client := tls.Client(clientConn, clientOpts.ClientTLSConfig(serverIdent.ID))
+		ctx.Go(func() error {
+			// NB: the client has to read the alert of a server rejecting it.
+			if client.HandshakeContext(ctx) == nil {
+				_, _ = client.Read(make([]byte, 1))
+			}
+			return nil
+		})
+
+		return tls.Server(serverConn, serverOpts.ServerTLSConfig()).HandshakeContext(ctx)
+	}
+
+	previous, err := ident.Rotate(time.Second)
+	require.NoError(t, err)
+
+	// the revocation is recorded when the peer presents the new leaf, and the
+	// old leaf is accepted during the grace period.
+	require.NoError(t, handshake(ident.FullIdentity))
+	require.NoError(t, handshake(previous))
+
+	// once the grace period has passed, the old leaf is rejected.
+	time.Sleep(time.Until(time.Now().Add(time.Second).Truncate(time.Second).Add(time.Second)))
+	require.ErrorContains(t, handshake(previous), extensions.ErrRevokedCert.Error())
+	require.NoError(t, handshake(ident.FullIdentity))
+}
@@ -82,6 +82,13 @@ func verifyChainSignatures(certs []*x509.Certificate) error {
 }
 
 func verifyCertSignature(parentCert, childCert *x509.Certificate) error {
+	switch childCert.SignatureAlgorithm {
+	case x509.ECDSAWithSHA384, x509.ECDSAWithSHA512, x509.PureEd25519:
+		// NB: certificates signed with P-384 and Ed25519 keys don't sign a
+		// SHA-256 digest of the certificate.
+		err := parentCert.CheckSignature(childCert.SignatureAlgorithm, childCert.RawTBSCertificate, childCert.Signature)
+		return pkcrypto.ErrVerifySignature.Wrap(err)
+	}
 	return pkcrypto.HashAndVerifySignature(parentCert.PublicKey, childCert.RawTBSCertificate, childCert.Signature)
 }
 
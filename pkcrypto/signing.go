@@ -6,6 +6,7 @@ package pkcrypto
 import (
 	"crypto"
 	"crypto/ecdsa"
+	"crypto/ed25519"
 	"crypto/elliptic"
 	"crypto/hmac"
 	"crypto/rand"
@@ -49,6 +50,13 @@ func GeneratePrivateECDSAKey(curve elliptic.Curve) (*ecdsa.PrivateKey, error) {
 	return ecdsa.GenerateKey(curve, rand.Reader)
 }
 
+// GeneratePrivateEd25519Key returns a new private Ed25519 key for signing
+// messages.
+func GeneratePrivateEd25519Key() (ed25519.PrivateKey, error) {
+	_, key, err := ed25519.GenerateKey(rand.Reader)
+	return key, err
+}
+
 // GeneratePrivateRSAKey returns a new private RSA key for signing messages.
 func GeneratePrivateRSAKey(bits int) (*rsa.PrivateKey, error) {
 	return rsa.GenerateKey(rand.Reader, bits)
@@ -71,6 +79,8 @@ func VerifySignatureWithoutHashing(pubKey crypto.PublicKey, digest, signature []
 		return verifyECDSASignatureWithoutHashing(key, digest, signature)
 	case *rsa.PublicKey:
 		return verifyRSASignatureWithoutHashing(key, digest, signature)
+	case ed25519.PublicKey:
+		return verifyEd25519SignatureWithoutHashing(key, digest, signature)
 	}
 	return ErrUnsupportedKey.New("%T", pubKey)
 }
@@ -96,6 +106,21 @@ func verifyRSASignatureWithoutHashing(pubKey *rsa.PublicKey, digest, signatureBy
 	return nil
 }
 
+// verifyEd25519SignatureWithoutHashing verifies the signature of the digest,
+// which Ed25519 signs as a message.
+func verifyEd25519SignatureWithoutHashing(pubKey ed25519.PublicKey, digest, signatureBytes []byte) error {
+	race2.ReadSlice(digest)
+	race2.ReadSlice(signatureBytes)
+
+	if len(pubKey) != ed25519.PublicKeySize {
+		return ErrUnsupportedKey.New("invalid ed25519 public key size %d", len(pubKey))
+	}
+	if !ed25519.Verify(pubKey, digest, signatureBytes) {
+		return ErrVerifySignature.New("signature is not valid")
+	}
+	return nil
+}
+
 // PublicKeyFromPrivate returns the public key corresponding to a given private
 // key.
 // It returns an error if the key isn't of an accepted implementation.
@@ -105,23 +130,34 @@ func PublicKeyFromPrivate(privKey crypto.PrivateKey) (crypto.PublicKey, error) {
 		return key.Public(), nil
 	case *rsa.PrivateKey:
 		return key.Public(), nil
+	case ed25519.PrivateKey:
+		return key.Public(), nil
+	case crypto.Signer:
+		return key.Public(), nil
 	}
 	return nil, ErrUnsupportedKey.New("%T", privKey)
 }
 
 // SignWithoutHashing signs the given digest with the private key and returns
-// the new signature.
+// the new signature. Besides the supported private keys, the key may be any
+// crypto.Signer for one of the supported public keys, e.g. a key held in a
+// hardware token.
 func SignWithoutHashing(privKey crypto.PrivateKey, digest []byte) ([]byte, error) {
 	switch key := privKey.(type) {
 	case *ecdsa.PrivateKey:
 		return signECDSAWithoutHashing(key, digest)
 	case *rsa.PrivateKey:
 		return signRSAWithoutHashing(key, digest)
+	case ed25519.PrivateKey:
+		return signEd25519WithoutHashing(key, digest)
+	case crypto.Signer:
+		return signSignerWithoutHashing(key, digest)
 	}
 	return nil, ErrUnsupportedKey.New("%T", privKey)
 }
 
 // SignHMACSHA256 signs the given data with HMAC-SHA256 using privKey as the secret.
+// Keys that are only available as crypto.Signer aren't supported.
 func SignHMACSHA256(privKey crypto.PrivateKey, data []byte) ([]byte, error) {
 	race2.ReadSlice(data)
 
@@ -135,6 +171,8 @@ func SignHMACSHA256(privKey crypto.PrivateKey, data []byte) ([]byte, error) {
 		}
 	case *rsa.PrivateKey:
 		secret = x509.MarshalPKCS1PrivateKey(key)
+	case ed25519.PrivateKey:
+		secret = key.Seed()
 	default:
 		return nil, ErrUnsupportedKey.New("%T", privKey)
 	}
@@ -171,6 +209,36 @@ func signRSAWithoutHashing(privKey *rsa.PrivateKey, digest []byte) ([]byte, erro
 	return privKey.Sign(rand.Reader, digest, &pssParams)
 }
 
+func signEd25519WithoutHashing(privKey ed25519.PrivateKey, digest []byte) ([]byte, error) {
+	race2.ReadSlice(digest)
+
+	if len(privKey) != ed25519.PrivateKeySize {
+		return nil, ErrUnsupportedKey.New("invalid ed25519 private key size %d", len(privKey))
+	}
+	return ed25519.Sign(privKey, digest), nil
+}
+
+// signSignerWithoutHashing signs the digest with the signer, such that the
+// signature matches the signatures of the corresponding private key.
+func signSignerWithoutHashing(signer crypto.Signer, digest []byte) ([]byte, error) {
+	race2.ReadSlice(digest)
+
+	var opts crypto.SignerOpts
+	switch pubKey := signer.Public().(type) {
+	case *ecdsa.PublicKey:
+		opts = crypto.SHA256
+	case *rsa.PublicKey:
+		opts = &pssParams
+	case ed25519.PublicKey:
+		opts = crypto.Hash(0)
+	default:
+		return nil, ErrUnsupportedKey.New("%T", pubKey)
+	}
+
+	sig, err := signer.Sign(rand.Reader, digest, opts)
+	return sig, ErrSign.Wrap(err)
+}
+
 // HashAndSign signs a SHA-256 digest of the given data and returns the new
 // signature.
 func HashAndSign(key crypto.PrivateKey, data []byte) ([]byte, error) {
@@ -197,6 +265,12 @@ func PublicKeyEqual(a, b crypto.PublicKey) bool {
 			return false
 		}
 		return publicRSAKeyEqual(aConcrete, bConcrete)
+	case ed25519.PublicKey:
+		bConcrete, ok := b.(ed25519.PublicKey)
+		if !ok {
+			return false
+		}
+		return aConcrete.Equal(bConcrete)
 	}
 	// a best-effort here is probably better than adding an err return
 	return reflect.DeepEqual(a, b)
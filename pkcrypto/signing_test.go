@@ -5,8 +5,9 @@ package pkcrypto
 
 import (
 	"crypto"
+	"crypto/ecdsa"
 	"crypto/ed25519"
-	"crypto/rand"
+	"crypto/elliptic"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
@@ -84,6 +85,51 @@ func TestSigningAndVerifyingRSA(t *testing.T) {
 	}
 }
 
+func TestSigningAndVerifyingEd25519(t *testing.T) {
+	privKey, err := GeneratePrivateEd25519Key()
+	require.NoError(t, err)
+	pubKey, err := PublicKeyFromPrivate(privKey)
+	require.NoError(t, err)
+	require.IsType(t, ed25519.PublicKey{}, pubKey)
+
+	for _, data := range []string{"", "C", string(make([]byte, 2000))} {
+		// test signing and verifying a hash of the data
+		sig, err := HashAndSign(privKey, []byte(data))
+		require.NoError(t, err)
+		require.NoError(t, HashAndVerifySignature(pubKey, []byte(data), sig))
+
+		// Ed25519 signatures are deterministic
+		sig2, err := HashAndSign(privKey, []byte(data))
+		require.NoError(t, err)
+		require.Equal(t, sig, sig2)
+
+		err = HashAndVerifySignature(pubKey, []byte(data+"a"), sig)
+		require.True(t, ErrVerifySignature.Has(err), err)
+	}
+
+	err = VerifySignatureWithoutHashing(ed25519.PublicKey("short"), nil, nil)
+	require.True(t, ErrUnsupportedKey.Has(err), err)
+
+	otherKey, err := GeneratePrivateEd25519Key()
+	require.NoError(t, err)
+	otherPubKey, err := PublicKeyFromPrivate(otherKey)
+	require.NoError(t, err)
+	require.True(t, PublicKeyEqual(pubKey, pubKey))
+	require.False(t, PublicKeyEqual(pubKey, otherPubKey))
+}
+
+func TestSigningAndVerifyingECDSAP384(t *testing.T) {
+	privKey, err := GeneratePrivateECDSAKey(elliptic.P384())
+	require.NoError(t, err)
+	pubKey, err := PublicKeyFromPrivate(privKey)
+	require.NoError(t, err)
+	require.Equal(t, elliptic.P384(), pubKey.(*ecdsa.PublicKey).Curve)
+
+	sig, err := HashAndSign(privKey, []byte("data"))
+	require.NoError(t, err)
+	require.NoError(t, HashAndVerifySignature(pubKey, []byte("data"), sig))
+}
+
 func TestSigningAndVerifyingHMACSHA256(t *testing.T) {
 	tests := []struct {
 		name string
@@ -100,6 +146,9 @@ func TestSigningAndVerifyingHMACSHA256(t *testing.T) {
 	rsaKey, err := GeneratePrivateRSAKey(StorjRSAKeyBits)
 	require.NoError(t, err)
 
+	ed25519Key, err := GeneratePrivateEd25519Key()
+	require.NoError(t, err)
+
 	for _, tt := range tests {
 		test := tt
 		testFunc := func(t *testing.T, privKey crypto.PrivateKey) {
@@ -125,6 +174,10 @@ func TestSigningAndVerifyingHMACSHA256(t *testing.T) {
 			testFunc(t, rsaKey)
 		})
 
+		t.Run("Ed25519: "+test.name, func(t *testing.T) {
+			testFunc(t, ed25519Key)
+		})
+
 		t.Run("nil key", func(t *testing.T) {
 			_, err = SignHMACSHA256(nil, []byte(test.data))
 			assert.True(t, ErrUnsupportedKey.Has(err), "invalid error class")
@@ -133,8 +186,7 @@ func TestSigningAndVerifyingHMACSHA256(t *testing.T) {
 		})
 
 		t.Run("invalid key type", func(t *testing.T) {
-			_, privKey, err := ed25519.GenerateKey(rand.Reader)
-			require.NoError(t, err)
+			privKey := []byte("invalid")
 			_, err = SignHMACSHA256(privKey, []byte(test.data))
 			assert.True(t, ErrUnsupportedKey.Has(err), "invalid error class")
 			err = VerifyHMACSHA256(privKey, []byte(test.data), nil)
@@ -0,0 +1,284 @@
+// Copyright (C) 2026 Storj Labs, Inc.
+// See LICENSE for copying information.
+
+package signing
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/binary"
+	"runtime"
+	"sync"
+
+	"storj.io/common/pb"
+	"storj.io/common/storj"
+	"storj.io/common/tracing"
+)
+
+// SigneeLookup returns the signee with the node ID, e.g. a trusted satellite.
+type SigneeLookup func(ctx context.Context, id storj.NodeID) (Signee, error)
+
+// UplinkOrder is an order with the piece public key of its order limit.
+type UplinkOrder struct {
+	PublicKey storj.PiecePublicKey
+	Order     *pb.Order
+}
+
+// SignedPieceHash is a piece hash with its signer, e.g. a storage node.
+type SignedPieceHash struct {
+	Signee Signee
+	Hash   *pb.PieceHash
+}
+
+// UplinkPieceHash is a piece hash with the piece public key of the uplink.
+type UplinkPieceHash struct {
+	PublicKey storj.PiecePublicKey
+	Hash      *pb.PieceHash
+}
+
+// BatchVerifier verifies the signatures of many messages concurrently.
+//
+// The results are the same as verifying every message on its own, e.g. with
+// VerifyOrderLimitSignature, however signees are looked up once per node and
+// duplicate messages are verified once.
+type BatchVerifier struct {
+	// Workers limits the number of concurrent verifications. When zero,
+	// GOMAXPROCS workers are used.
+	Workers int
+}
+
+// VerifyOrderLimits verifies the signatures of the order limits by their
+// satellites, which are looked up once per satellite. It returns the error of
+// every order limit, which is nil when its signature is valid.
+func (batch BatchVerifier) VerifyOrderLimits(ctx context.Context, satellites SigneeLookup, limits []*pb.OrderLimit) []error {
+	errs := make([]error, len(limits))
+	if areSignaturesDisabled(ctx) {
+		return errs
+	}
+
+	type lookupResult struct {
+		signee Signee
+		err    error
+	}
+	signees := map[storj.NodeID]lookupResult{}
+
+	verifications := make([]verification, len(limits))
+	for i, limit := range limits {
+		result, ok := signees[limit.SatelliteId]
+		if !ok {
+			result.signee, result.err = satellites(ctx, limit.SatelliteId)
+			if result.err != nil {
+				result.err = Error.Wrap(result.err)
+			}
+			signees[limit.SatelliteId] = result
+		}
+		if result.err != nil {
+			errs[i] = result.err
+			continue
+		}
+
+		verifications[i] = verification{
+			signee: signeeKey(result.signee),
+			encode: func(ctx context.Context) ([]byte, []byte, error) {
+				data, err := EncodeOrderLimit(ctx, limit)
+				return data, limit.SatelliteSignature, err
+			},
+			verify: result.signee.HashAndVerifySignature,
+		}
+	}
+
+	batch.run(ctx, verifications, errs)
+	return errs
+}
+
+// VerifyUplinkOrders verifies the signatures of the orders by the piece public
+// keys of their order limits. It returns the error of every order, which is
+// nil when its signature is valid.
+func (batch BatchVerifier) VerifyUplinkOrders(ctx context.Context, orders []UplinkOrder) []error {
+	errs := make([]error, len(orders))
+	if areSignaturesDisabled(ctx) {
+		return errs
+	}
+
+	verifications := make([]verification, len(orders))
+	for i, order := range orders {
+		verifications[i] = verification{
+			signee:       string(order.PublicKey.Bytes()),
+			unrecognized: order.Order.XXX_unrecognized,
+			encode: func(ctx context.Context) ([]byte, []byte, error) {
+				data, err := EncodeOrder(ctx, order.Order)
+				return data, order.Order.UplinkSignature, err
+			},
+			verify: verifyPiecePublicKey(order.PublicKey),
+		}
+	}
+
+	batch.run(ctx, verifications, errs)
+	return errs
+}
+
+// VerifyPieceHashes verifies the signatures of the piece hashes by their
+// signees. Signees are identified by their node ID. It returns the error of
+// every piece hash, which is nil when its signature is valid.
+func (batch BatchVerifier) VerifyPieceHashes(ctx context.Context, hashes []SignedPieceHash) []error {
+	errs := make([]error, len(hashes))
+	if areSignaturesDisabled(ctx) {
+		return errs
+	}
+
+	verifications := make([]verification, len(hashes))
+	for i, hash := range hashes {
+		verifications[i] = verification{
+			signee:       signeeKey(hash.Signee),
+			unrecognized: hash.Hash.XXX_unrecognized,
+			encode: func(ctx context.Context) ([]byte, []byte, error) {
+				data, err := EncodePieceHash(ctx, hash.Hash)
+				return data, hash.Hash.Signature, err
+			},
+			verify: hash.Signee.HashAndVerifySignature,
+		}
+	}
+
+	batch.run(ctx, verifications, errs)
+	return errs
+}
+
+// VerifyUplinkPieceHashes verifies the signatures of the piece hashes by the
+// piece public keys. It returns the error of every piece hash, which is nil
+// when its signature is valid.
+func (batch BatchVerifier) VerifyUplinkPieceHashes(ctx context.Context, hashes []UplinkPieceHash) []error {
+	errs := make([]error, len(hashes))
+	if areSignaturesDisabled(ctx) {
+		return errs
+	}
+
+	verifications := make([]verification, len(hashes))
+	for i, hash := range hashes {
+		verifications[i] = verification{
+			signee:       string(hash.PublicKey.Bytes()),
+			unrecognized: hash.Hash.XXX_unrecognized,
+			encode: func(ctx context.Context) ([]byte, []byte, error) {
+				data, err := EncodePieceHash(ctx, hash.Hash)
+				return data, hash.Hash.Signature, err
+			},
+			verify: verifyPiecePublicKey(hash.PublicKey),
+		}
+	}
+
+	batch.run(ctx, verifications, errs)
+	return errs
+}
+
+// verification is the verification of a single message in a batch.
+type verification struct {
+	// signee identifies the key, which verifies the signature.
+	signee string
+	// unrecognized are the unrecognized fields of the message, which aren't
+	// allowed.
+	unrecognized []byte
+	// encode returns the signed data and the signature.
+	encode func(ctx context.Context) (data, signature []byte, err error)
+	// verify verifies the signature of the data.
+	verify func(ctx context.Context, data, signature []byte) error
+}
+
+// verificationResult is the shared result of duplicate verifications.
+type verificationResult struct {
+	done chan struct{}
+	err  error
+}
+
+// run runs the verifications, which have an encode func, and stores their
+// errors at the same index.
+func (batch BatchVerifier) run(ctx context.Context, verifications []verification, errs []error) {
+	ctx = tracing.WithoutDistributedTracing(ctx)
+	defer mon.Task()(&ctx)(nil)
+
+	workers := batch.Workers
+	if workers <= 0 {
+		workers = runtime.GOMAXPROCS(0)
+	}
+	workers = min(workers, len(verifications))
+
+	var mu sync.Mutex
+	results := map[[sha256.Size]byte]*verificationResult{}
+
+	// verifyOnce verifies the message, unless a duplicate was verified.
+	verifyOnce := func(v verification) error {
+		if len(v.unrecognized) > 0 {
+			return Error.New("unrecognized fields are not allowed")
+		}
+		data, signature, err := v.encode(ctx)
+		if err != nil {
+			return Error.Wrap(err)
+		}
+
+		key := verificationKey(v.signee, data, signature)
+		mu.Lock()
+		result, ok := results[key]
+		if !ok {
+			result = &verificationResult{done: make(chan struct{})}
+			results[key] = result
+		}
+		mu.Unlock()
+
+		if ok {
+			<-result.done
+			return result.err
+		}
+		result.err = v.verify(ctx, data, signature)
+		close(result.done)
+		return result.err
+	}
+
+	next := make(chan int)
+	var wg sync.WaitGroup
+	for range workers {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range next {
+				errs[i] = verifyOnce(verifications[i])
+			}
+		}()
+	}
+
+	for i, v := range verifications {
+		if v.encode == nil {
+			continue
+		}
+		if err := ctx.Err(); err != nil {
+			errs[i] = err
+			continue
+		}
+		next <- i
+	}
+	close(next)
+	wg.Wait()
+}
+
+// verifyPiecePublicKey returns a func, which verifies signatures with the key.
+func verifyPiecePublicKey(publicKey storj.PiecePublicKey) func(ctx context.Context, data, signature []byte) error {
+	return func(ctx context.Context, data, signature []byte) error {
+		return Error.Wrap(publicKey.Verify(data, signature))
+	}
+}
+
+// signeeKey identifies the signee in a batch.
+func signeeKey(signee Signee) string {
+	id := signee.ID()
+	return string(id[:])
+}
+
+// verificationKey identifies duplicate verifications.
+func verificationKey(signee string, data, signature []byte) [sha256.Size]byte {
+	h := sha256.New()
+	for _, field := range [][]byte{[]byte(signee), data, signature} {
+		_, _ = h.Write(binary.BigEndian.AppendUint64(nil, uint64(len(field))))
+		_, _ = h.Write(field)
+	}
+
+	var key [sha256.Size]byte
+	h.Sum(key[:0])
+	return key
+}
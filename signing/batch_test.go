@@ -0,0 +1,244 @@
+// Copyright (C) 2026 Storj Labs, Inc.
+// See LICENSE for copying information.
+
+package signing_test
+
+import (
+	"context"
+	"errors"
+	"strconv"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"storj.io/common/identity/testidentity"
+	"storj.io/common/pb"
+	"storj.io/common/signing"
+	"storj.io/common/storj"
+	"storj.io/common/testcontext"
+	"storj.io/common/testrand"
+)
+
+// countingSignee counts the verified signatures.
+type countingSignee struct {
+	signing.Signee
+	verified atomic.Int64
+}
+
+func (signee *countingSignee) HashAndVerifySignature(ctx context.Context, data, signature []byte) error {
+	signee.verified.Add(1)
+	return signee.Signee.HashAndVerifySignature(ctx, data, signature)
+}
+
+func TestBatchVerifier_OrderLimits(t *testing.T) {
+	ctx := testcontext.New(t)
+
+	satellite := testidentity.MustPregeneratedSignedIdentity(0, storj.DefaultIDVersion())
+	signer := signing.SignerFromFullIdentity(satellite)
+	signee := &countingSignee{Signee: signing.SigneeFromPeerIdentity(satellite.PeerIdentity())}
+	unknown := testrand.NodeID()
+
+	var lookups atomic.Int64
+	lookup := func(ctx context.Context, id storj.NodeID) (signing.Signee, error) {
+		lookups.Add(1)
+		if id != satellite.ID {
+			return nil, errors.New("untrusted satellite")
+		}
+		return signee, nil
+	}
+
+	limits := make([]*pb.OrderLimit, 10)
+	for i := range limits {
+		limit, err := signing.SignOrderLimit(ctx, signer, &pb.OrderLimit{
+			SerialNumber:    testrand.SerialNumber(),
+			SatelliteId:     satellite.ID,
+			StorageNodeId:   testrand.NodeID(),
+			PieceId:         testrand.PieceID(),
+			Limit:           int64(i),
+			Action:          pb.PieceAction_GET,
+			OrderCreation:   time.Now(),
+			OrderExpiration: time.Now().Add(time.Hour),
+		})
+		require.NoError(t, err)
+		limits[i] = limit
+	}
+	limits[2].Limit++
+	limits[5] = &pb.OrderLimit{SatelliteId: unknown}
+	limits[7] = limits[0]
+
+	errs := signing.BatchVerifier{Workers: 3}.VerifyOrderLimits(ctx, lookup, limits)
+	require.Len(t, errs, len(limits))
+	for i, err := range errs {
+		switch i {
+		case 2:
+			require.Error(t, err)
+		case 5:
+			require.True(t, signing.Error.Has(err), err)
+		default:
+			require.NoError(t, err, i)
+		}
+	}
+
+	// satellites are looked up once and duplicates are verified once.
+	require.EqualValues(t, 2, lookups.Load())
+	require.EqualValues(t, len(limits)-2, signee.verified.Load())
+}
+
+func TestBatchVerifier_Uplink(t *testing.T) {
+	ctx := testcontext.New(t)
+
+	publicKey, privateKey, err := storj.NewPieceKey()
+	require.NoError(t, err)
+	otherKey, _, err := storj.NewPieceKey()
+	require.NoError(t, err)
+
+	var orders []signing.UplinkOrder
+	var hashes []signing.UplinkPieceHash
+	for i := range 5 {
+		order, err := signing.SignUplinkOrder(ctx, privateKey, &pb.Order{
+			SerialNumber: testrand.SerialNumber(),
+			Amount:       int64(i),
+		})
+		require.NoError(t, err)
+		orders = append(orders, signing.UplinkOrder{PublicKey: publicKey, Order: order})
+
+		hash, err := signing.SignUplinkPieceHash(ctx, privateKey, &pb.PieceHash{
+			PieceId:   testrand.PieceID(),
+			Hash:      testrand.Bytes(32),
+			PieceSize: int64(i),
+		})
+		require.NoError(t, err)
+		hashes = append(hashes, signing.UplinkPieceHash{PublicKey: publicKey, Hash: hash})
+	}
+	orders[1].PublicKey = otherKey
+	orders[3].Order.XXX_unrecognized = []byte{1}
+	hashes[4].Hash.PieceSize++
+
+	batch := signing.BatchVerifier{}
+	for i, err := range batch.VerifyUplinkOrders(ctx, orders) {
+		require.Equal(t, i == 1 || i == 3, err != nil, i)
+		require.Equal(t, signing.VerifyUplinkOrderSignature(ctx, orders[i].PublicKey, orders[i].Order) == nil, err == nil, i)
+	}
+	for i, err := range batch.VerifyUplinkPieceHashes(ctx, hashes) {
+		require.Equal(t, i == 4, err != nil, i)
+	}
+}
+
+func TestBatchVerifier_PieceHashes(t *testing.T) {
+	ctx := testcontext.New(t)
+
+	nodes := make([]*countingSignee, 3)
+	var hashes []signing.SignedPieceHash
+	for i := range nodes {
+		node := testidentity.MustPregeneratedSignedIdentity(i, storj.DefaultIDVersion())
+		nodes[i] = &countingSignee{Signee: signing.SigneeFromPeerIdentity(node.PeerIdentity())}
+
+		hash, err := signing.SignPieceHash(ctx, signing.SignerFromFullIdentity(node), &pb.PieceHash{
+			PieceId: testrand.PieceID(),
+			Hash:    []byte(strconv.Itoa(i)),
+		})
+		require.NoError(t, err)
+
+		// the same hash is verified once per signee.
+		for range 4 {
+			hashes = append(hashes, signing.SignedPieceHash{Signee: nodes[i], Hash: hash})
+		}
+	}
+	// the hash of another node is invalid.
+	hashes = append(hashes, signing.SignedPieceHash{Signee: nodes[0], Hash: hashes[len(hashes)-1].Hash})
+
+	errs := signing.BatchVerifier{}.VerifyPieceHashes(ctx, hashes)
+	for i, err := range errs {
+		require.Equal(t, i == len(hashes)-1, err != nil, i)
+	}
+	require.EqualValues(t, 2, nodes[0].verified.Load())
+	require.EqualValues(t, 1, nodes[1].verified.Load())
+	require.EqualValues(t, 1, nodes[2].verified.Load())
+
+	// canceled batches aren't verified.
+	canceled, cancel := context.WithCancel(ctx)
+	cancel()
+	for _, err := range (signing.BatchVerifier{}).VerifyPieceHashes(canceled, hashes) {
+		require.ErrorIs(t, err, context.Canceled)
+	}
+}
+
+func BenchmarkBatchVerifier(b *testing.B) {
+	ctx := context.Background()
+
+	satellite := testidentity.MustPregeneratedSignedIdentity(0, storj.DefaultIDVersion())
+	signer := signing.SignerFromFullIdentity(satellite)
+	signee := signing.SigneeFromPeerIdentity(satellite.PeerIdentity())
+	lookup := func(ctx context.Context, id storj.NodeID) (signing.Signee, error) { return signee, nil }
+
+	publicKey, privateKey, err := storj.NewPieceKey()
+	require.NoError(b, err)
+
+	const count = 1000
+	limits := make([]*pb.OrderLimit, count)
+	orders := make([]signing.UplinkOrder, count)
+	for i := range limits {
+		limit, err := signing.SignOrderLimit(ctx, signer, &pb.OrderLimit{
+			SerialNumber:    testrand.SerialNumber(),
+			SatelliteId:     satellite.ID,
+			StorageNodeId:   testrand.NodeID(),
+			PieceId:         testrand.PieceID(),
+			UplinkPublicKey: publicKey,
+			Limit:           int64(i),
+			Action:          pb.PieceAction_GET,
+			OrderCreation:   time.Now(),
+			OrderExpiration: time.Now().Add(time.Hour),
+		})
+		require.NoError(b, err)
+		limits[i] = limit
+
+		order, err := signing.SignUplinkOrder(ctx, privateKey, &pb.Order{
+			SerialNumber: limit.SerialNumber,
+			Amount:       int64(i),
+		})
+		require.NoError(b, err)
+		orders[i] = signing.UplinkOrder{PublicKey: publicKey, Order: order}
+	}
+
+	b.Run("OrderLimits/PerItem", func(b *testing.B) {
+		for b.Loop() {
+			for _, limit := range limits {
+				if err := signing.VerifyOrderLimitSignature(ctx, signee, limit); err != nil {
+					b.Fatal(err)
+				}
+			}
+		}
+	})
+
+	b.Run("OrderLimits/Batch", func(b *testing.B) {
+		for b.Loop() {
+			for _, err := range (signing.BatchVerifier{}).VerifyOrderLimits(ctx, lookup, limits) {
+				if err != nil {
+					b.Fatal(err)
+				}
+			}
+		}
+	})
+
+	b.Run("UplinkOrders/PerItem", func(b *testing.B) {
+		for b.Loop() {
+			for _, order := range orders {
+				if err := signing.VerifyUplinkOrderSignature(ctx, order.PublicKey, order.Order); err != nil {
+					b.Fatal(err)
+				}
+			}
+		}
+	})
+
+	b.Run("UplinkOrders/Batch", func(b *testing.B) {
+		for b.Loop() {
+			for _, err := range (signing.BatchVerifier{}).VerifyUplinkOrders(ctx, orders) {
+				if err != nil {
+					b.Fatal(err)
+				}
+			}
+		}
+	})
+}
@@ -103,6 +103,14 @@ func TestVerificationWhenDisabled(t *testing.T) {
 		require.NoError(t, err)
 	})
 
+	t.Run("BatchVerifier", func(t *testing.T) {
+		lookup := func(context.Context, storj.NodeID) (Signee, error) { return panicSignee{}, nil }
+		batch := BatchVerifier{}
+		require.Equal(t, []error{nil}, batch.VerifyOrderLimits(ctx, lookup, []*pb.OrderLimit{{SatelliteSignature: badSignature}}))
+		require.Equal(t, []error{nil}, batch.VerifyUplinkOrders(ctx, []UplinkOrder{{PublicKey: publicKey, Order: &pb.Order{UplinkSignature: badSignature}}}))
+		require.Equal(t, []error{nil}, batch.VerifyPieceHashes(ctx, []SignedPieceHash{{Signee: panicSignee{}, Hash: &pb.PieceHash{Signature: badSignature}}}))
+		require.Equal(t, []error{nil}, batch.VerifyUplinkPieceHashes(ctx, []UplinkPieceHash{{PublicKey: publicKey, Hash: &pb.PieceHash{Signature: badSignature}}}))
+	})
 }
 
 type panicSigner struct{}
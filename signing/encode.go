@@ -115,3 +115,14 @@ func EncodeExitFailed(ctx context.Context, exitFailed *pb.ExitFailed) (_ []byte,
 
 	return out, err
 }
+
+// EncodeCertificateStatus encodes CertificateStatus into bytes for signing.
+func EncodeCertificateStatus(ctx context.Context, status *pb.CertificateStatus) (_ []byte, err error) {
+	defer mon.Task()(&ctx)(&err)
+
+	// NB: statuses are cached and verified concurrently, hence the signature
+	// is removed from a copy.
+	unsigned := *status
+	unsigned.Signature = nil
+	return pb.Marshal(&unsigned)
+}
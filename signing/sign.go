@@ -171,3 +171,28 @@ func SignExitFailed(ctx context.Context, signer Signer, unsigned *pb.ExitFailed)
 
 	return &signed, nil
 }
+
+// SignCertificateStatus signs the CertificateStatus using the specified signer.
+// Signer is a certificate status authority.
+func SignCertificateStatus(ctx context.Context, signer Signer, unsigned *pb.CertificateStatus) (_ *pb.CertificateStatus, err error) {
+	defer mon.Task()(&ctx)(&err)
+
+	signed := *unsigned
+	signed.AuthorityId = signer.ID()
+	if areSignaturesDisabled(ctx) {
+		signed.Signature = disabledSignature
+		return &signed, nil
+	}
+
+	bytes, err := EncodeCertificateStatus(ctx, &signed)
+	if err != nil {
+		return nil, Error.Wrap(err)
+	}
+
+	signed.Signature, err = signer.HashAndSign(ctx, bytes)
+	if err != nil {
+		return nil, Error.Wrap(err)
+	}
+
+	return &signed, nil
+}
@@ -101,6 +101,32 @@ func TestVerifyOrderLimitSignature(t *testing.T) {
 	}
 }
 
+func TestSignOrderLimit_IDVersions(t *testing.T) {
+	ctx := testcontext.New(t)
+
+	for _, version := range storj.IDVersions {
+		satellite := testidentity.MustPregeneratedSignedIdentity(0, version)
+
+		signed, err := signing.SignOrderLimit(ctx, signing.SignerFromFullIdentity(satellite), &pb.OrderLimit{
+			SerialNumber:    testrand.SerialNumber(),
+			SatelliteId:     satellite.ID,
+			StorageNodeId:   testrand.NodeID(),
+			PieceId:         testrand.PieceID(),
+			Limit:           1000,
+			Action:          pb.PieceAction_GET,
+			OrderCreation:   time.Now(),
+			OrderExpiration: time.Now().Add(time.Hour),
+		})
+		require.NoError(t, err)
+
+		signee := signing.SigneeFromPeerIdentity(satellite.PeerIdentity())
+		require.NoError(t, signing.VerifyOrderLimitSignature(ctx, signee, signed), "version %d", version.Number)
+
+		signed.Limit++
+		require.Error(t, signing.VerifyOrderLimitSignature(ctx, signee, signed), "version %d", version.Number)
+	}
+}
+
 // Ensure that an order limit can survive a round trip through JSON and still have its
 // signature validated. (This also verifies that JSON encoding and decoding are correctly
 // matched in terms of how they treat each of the OrderLimit fields.)
@@ -73,6 +73,15 @@ func VerifyExitFailed(ctx context.Context, satellite Signee, signed *pb.ExitFail
 	return verifyExitFailed(ctx, satellite, signed)
 }
 
+// VerifyCertificateStatus verifies that the signature inside CertificateStatus
+// belongs to the authority.
+func VerifyCertificateStatus(ctx context.Context, authority Signee, signed *pb.CertificateStatus) (err error) {
+	if areSignaturesDisabled(ctx) {
+		return nil
+	}
+	return verifyCertificateStatus(ctx, authority, signed)
+}
+
 var monVerifyOrderLimitSignature = mon.Task()
 
 func verifyOrderLimitSignature(ctx context.Context, satellite Signee, signed *pb.OrderLimit) (err error) {
@@ -179,3 +188,18 @@ func verifyExitFailed(ctx context.Context, satellite Signee, signed *pb.ExitFail
 
 	return Error.Wrap(satellite.HashAndVerifySignature(ctx, bytes, signed.ExitFailureSignature))
 }
+
+func verifyCertificateStatus(ctx context.Context, authority Signee, signed *pb.CertificateStatus) (err error) {
+	defer mon.Task()(&ctx)(&err)
+
+	if signed.AuthorityId != authority.ID() {
+		return Error.New("certificate status of authority %s, expected %s", signed.AuthorityId, authority.ID())
+	}
+
+	bytes, err := EncodeCertificateStatus(ctx, signed)
+	if err != nil {
+		return Error.Wrap(err)
+	}
+
+	return Error.Wrap(authority.HashAndVerifySignature(ctx, bytes, signed.Signature))
+}
@@ -54,6 +54,15 @@ const (
 	// EncNullBase64URL is like EncNull but Base64 encodes/decodes the
 	// binary path data (URL-safe).
 	EncNullBase64URL
+	// EncXChaCha20Poly1305Stream indicates use of XChaCha20-Poly1305 encryption
+	// in a STREAM-like construction, which authenticates the position of every
+	// block and the end of the stream.
+	EncXChaCha20Poly1305Stream
+	// EncPathOrderPreserving indicates use of deterministic path encryption
+	// that preserves the lexicographic order within path components. It can
+	// only be used for paths and reveals the order and common prefixes of the
+	// path components.
+	EncPathOrderPreserving
 )
 
 // String representation of the cipher suite.
@@ -69,6 +78,10 @@ func (suite CipherSuite) String() string {
 		return "SecretBox"
 	case EncNullBase64URL:
 		return "null-Base64URL"
+	case EncXChaCha20Poly1305Stream:
+		return "XChaCha20-Poly1305-STREAM"
+	case EncPathOrderPreserving:
+		return "order-preserving"
 	default:
 		return "CipherSuite(" + strconv.Itoa(int(suite)) + ")"
 	}
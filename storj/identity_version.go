@@ -5,6 +5,7 @@ package storj
 
 import (
 	"crypto"
+	"crypto/elliptic"
 	"crypto/x509"
 	"crypto/x509/pkix"
 	"strconv"
@@ -19,6 +20,13 @@ const (
 	// NB: identities created before identity versioning (i.e. which don't have a
 	// version extension; "legacy") will be recognized as V0.
 	V0 = IDVersionNumber(iota)
+
+	// V1 represents identity version 1, which uses ECDSA P-384 keys.
+	V1
+
+	// V2 represents identity version 2, which uses Ed25519 keys. Signing with
+	// Ed25519 keys is considerably faster, e.g. for signing orders.
+	V2
 )
 
 var (
@@ -28,6 +36,18 @@ var (
 			Number:        V0,
 			NewPrivateKey: pkcrypto.GeneratePrivateKey,
 		},
+		V1: {
+			Number: V1,
+			NewPrivateKey: func() (crypto.PrivateKey, error) {
+				return pkcrypto.GeneratePrivateECDSAKey(elliptic.P384())
+			},
+		},
+		V2: {
+			Number: V2,
+			NewPrivateKey: func() (crypto.PrivateKey, error) {
+				return pkcrypto.GeneratePrivateEd25519Key()
+			},
+		},
 	}
 
 	// IDVersionHandler compares the identity version of the remote peers
@@ -62,6 +82,15 @@ func GetIDVersion(number IDVersionNumber) (IDVersion, error) {
 	return version, nil
 }
 
+// DefaultIDVersion returns the IDVersion that is used for new identities and
+// accepted by peers by default.
+//
+// NB: newer versions are opt-in, such that peers which only accept the default
+// version don't reject new identities before they are upgraded.
+func DefaultIDVersion() IDVersion {
+	return IDVersions[V0]
+}
+
 // LatestIDVersion returns the last IDVersion registered.
 func LatestIDVersion() IDVersion {
 	return IDVersions[IDVersionNumber(len(IDVersions)-1)]
@@ -92,7 +121,9 @@ func IDVersionInVersions(versionNumber IDVersionNumber, versionsStr string) erro
 	case "*":
 		return nil
 	case "latest":
-		if versionNumber == LatestIDVersion().Number {
+		// NB: "latest" only accepts the default version, because newer versions
+		// are opt-in and have to be allowed explicitly, e.g. "0-2" or "*".
+		if versionNumber == DefaultIDVersion().Number {
 			return nil
 		}
 	default:
@@ -20,6 +20,11 @@ import (
 
 func TestLatestVersion(t *testing.T) {
 	version := storj.LatestIDVersion()
+	assert.Equal(t, storj.V2, version.Number)
+}
+
+func TestDefaultVersion(t *testing.T) {
+	version := storj.DefaultIDVersion()
 	assert.Equal(t, storj.V0, version.Number)
 }
 
@@ -46,7 +51,10 @@ func TestIDVersionInVersions_match(t *testing.T) {
 	}{
 		{"single version", storj.V0, "0"},
 		{"beginning of version range", storj.V0, "0-1"},
-		{"latest alias", storj.LatestIDVersion().Number, "latest"},
+		{"latest alias", storj.DefaultIDVersion().Number, "latest"},
+		{"version range with newer versions", storj.V1, "0-2"},
+		{"list of newer versions", storj.V2, "1,2"},
+		{"any with newer version", storj.V2, "*"},
 	}
 
 	for _, testcase := range testcases {
@@ -64,6 +72,8 @@ func TestIDVersionInVersions_error(t *testing.T) {
 	}{
 		{"version range", storj.V0, "1-2"},
 		{"malformed PeerIDVersions", storj.V0, "0-"},
+		{"latest alias with newer version", storj.V2, "latest"},
+		{"latest alias with unknown version", storj.IDVersionNumber(200), "latest"},
 	}
 
 	for _, testcase := range testcases {
@@ -79,6 +89,9 @@ func TestIDVersionExtensionHandler_success(t *testing.T) {
 
 	latestVersionChain := identityV1Chain
 
+	_, ed25519Chain, err := testpeertls.NewCertChain(2, storj.V2)
+	assert.NoError(t, err)
+
 	testcases := []struct {
 		name     string
 		versions string
@@ -87,6 +100,8 @@ func TestIDVersionExtensionHandler_success(t *testing.T) {
 		{"V0", "0", identityV1Chain},
 		{"any with V0 chain", "*", identityV1Chain},
 		{"latest version", "latest", latestVersionChain},
+		{"list of newer versions with V2 chain", "1,2", ed25519Chain},
+		{"any with V2 chain", "*", ed25519Chain},
 	}
 
 	for _, testcase := range testcases {